@@ -1,8 +1,8 @@
 module github.com/zephyrtronium/ikitai
 
-go 1.12
+go 1.25.0
 
 require (
-	golang.org/x/arch v0.0.0-20190815191158-8a70ba74b3a1 // indirect
-	golang.org/x/sys v0.0.0-20190813064441-fde4db37ae7a
+	golang.org/x/arch v0.30.0
+	golang.org/x/sys v0.47.0
 )
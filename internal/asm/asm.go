@@ -0,0 +1,361 @@
+// Package asm assembles x86-64 instructions with symbolic labels into
+// unsafewx blocks.
+//
+// An Assembler collects instructions, encoded by x86enc, and labels. Branches
+// and RIP-relative memory operands can refer to labels. When the program is
+// complete, Assemble lays it out, choosing between the rel8 and rel32 forms
+// of branches like JMP and Jcc so that every branch uses the short form if
+// its target is in range, and writes the result into a Block. Align pads the
+// program with NOPs so that the next instruction, like the head of a loop,
+// starts at a multiple of a power of two.
+package asm
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/zephyrtronium/ikitai/internal/unsafewx"
+	"github.com/zephyrtronium/ikitai/internal/x86enc"
+)
+
+// An Assembler builds a program from instructions and labels. The zero value
+// is an empty program which encodes for 64-bit mode on the processor running
+// the program.
+type Assembler struct {
+	// Encoder encodes the program's instructions. If it is nil, the
+	// Assembler uses the functions of x86enc.
+	Encoder *x86enc.Encoder
+
+	items  []item
+	labels map[string]int // index of the item following each label
+	err    error
+}
+
+// item is an instruction, raw bytes, or alignment in a program.
+type item struct {
+	// b is the encoding of the item if it does not refer to a label.
+	b []byte
+	// label is the label the item refers to, if any. short and long are its
+	// encodings with an 8-bit and a 32-bit displacement; one of them may be
+	// nil. addend is added to the displacement.
+	label       string
+	short, long *field
+	addend      int64
+	// far is set once the label is out of range of the short form.
+	far bool
+	// src describes the item for errors.
+	src string
+	// align is the alignment of the item following padding, or 0 if the
+	// item is not padding. trap is set if the padding is INT3 rather than
+	// NOPs.
+	align int
+	trap  bool
+}
+
+// field is an encoding of an instruction with the position and size of the
+// displacement which refers to a label.
+type field struct {
+	b        []byte
+	at, size int
+}
+
+// Label defines a label at the current end of the program. Each label may be
+// defined only once.
+func (a *Assembler) Label(name string) {
+	if a.labels == nil {
+		a.labels = make(map[string]int)
+	}
+	if _, ok := a.labels[name]; ok {
+		a.fail(fmt.Errorf("asm: label %s redefined", name))
+		return
+	}
+	a.labels[name] = len(a.items)
+}
+
+// Inst adds an instruction to the program. Errors in encoding it are
+// reported by Encode and Assemble.
+func (a *Assembler) Inst(op string, args ...x86enc.Operand) {
+	if err := a.inst(op, args); err != nil {
+		a.fail(err)
+	}
+}
+
+func (a *Assembler) inst(op string, args []x86enc.Operand) error {
+	b, err := a.encode(op, args)
+	if err != nil {
+		return fmt.Errorf("asm: %s %v: %w", op, args, err)
+	}
+	a.items = append(a.items, item{b: b})
+	return nil
+}
+
+// Ref adds an instruction which refers to a label. Exactly one of its
+// operands must be a Rel, like the target of JMP, or a RIP-relative Mem; the
+// value of the Rel or the displacement of the Mem is added to the label's
+// offset. Branches which have both rel8 and rel32 forms use rel8 when the
+// label is in range.
+func (a *Assembler) Ref(label, op string, args ...x86enc.Operand) {
+	if err := a.ref(label, op, args); err != nil {
+		a.fail(err)
+	}
+}
+
+func (a *Assembler) ref(label, op string, args []x86enc.Operand) error {
+	it := item{label: label, src: fmt.Sprintf("%s %v", op, args)}
+	ref := -1
+	for i, o := range args {
+		switch o := o.(type) {
+		case x86enc.Rel:
+			it.addend = int64(o)
+		case x86enc.Mem:
+			if _, ok := o.Base.(x86enc.IP); !ok {
+				continue
+			}
+			it.addend = int64(o.Disp)
+		default:
+			continue
+		}
+		if ref >= 0 {
+			ref = -1
+			break
+		}
+		ref = i
+	}
+	if ref < 0 {
+		return fmt.Errorf("asm: %s must have exactly one relative operand", it.src)
+	}
+	// Find the displacement by encoding with two values which differ in
+	// every byte. The short values select a rel8 form if there is one.
+	var err error
+	if _, ok := args[ref].(x86enc.Rel); ok {
+		it.short, _ = a.field(op, args, ref, 0x11, 0x55)
+		if it.short != nil && it.short.size != 1 {
+			it.short = nil
+		}
+	}
+	it.long, err = a.field(op, args, ref, 0x11223344, 0x55667788)
+	if it.long != nil && it.long.size != 4 {
+		it.long, err = nil, fmt.Errorf("no 32-bit displacement")
+	}
+	if it.short == nil && it.long == nil {
+		return fmt.Errorf("asm: %s: %w", it.src, err)
+	}
+	it.far = it.short == nil
+	a.items = append(a.items, it)
+	return nil
+}
+
+// Raw adds bytes to the program, such as data referred to by RIP-relative
+// operands.
+func (a *Assembler) Raw(p []byte) {
+	a.items = append(a.items, item{b: append([]byte(nil), p...)})
+}
+
+// Align pads the program with NOPs to a multiple of n bytes, which must be a
+// power of two, so that the next instruction starts at an aligned offset.
+// Assemble places the program at an offset of the block aligned to the
+// largest alignment in it.
+func (a *Assembler) Align(n int) {
+	a.align(n, false)
+}
+
+// AlignTrap pads the program like Align, but with INT3, for gaps which are
+// never executed, like those before data or after an unconditional branch.
+func (a *Assembler) AlignTrap(n int) {
+	a.align(n, true)
+}
+
+func (a *Assembler) align(n int, trap bool) {
+	if n <= 0 || n&(n-1) != 0 {
+		a.fail(fmt.Errorf("asm: alignment %d is not a power of two", n))
+		return
+	}
+	a.items = append(a.items, item{align: n, trap: trap})
+}
+
+// field encodes an instruction with two values of its relative operand and
+// locates the displacement where the encodings differ.
+func (a *Assembler) field(op string, args []x86enc.Operand, ref int, v, w int32) (*field, error) {
+	with := func(d int32) ([]byte, error) {
+		args := append([]x86enc.Operand(nil), args...)
+		switch o := args[ref].(type) {
+		case x86enc.Rel:
+			args[ref] = x86enc.Rel(d)
+		case x86enc.Mem:
+			o.Disp = d
+			args[ref] = o
+		}
+		return a.encode(op, args)
+	}
+	b, err := with(v)
+	if err != nil {
+		return nil, err
+	}
+	c, err := with(w)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != len(c) {
+		return nil, fmt.Errorf("encoding length depends on the displacement")
+	}
+	f := &field{b: b, at: -1}
+	for i := range b {
+		if b[i] != c[i] {
+			if f.at < 0 {
+				f.at = i
+			}
+			f.size = i + 1 - f.at
+		}
+	}
+	return f, nil
+}
+
+func (a *Assembler) encode(op string, args []x86enc.Operand) ([]byte, error) {
+	if a.Encoder == nil {
+		return x86enc.Encode(op, args...)
+	}
+	return a.Encoder.Encode(op, args...)
+}
+
+// mode returns the processor mode the program encodes for.
+func (a *Assembler) mode() int {
+	if a.Encoder == nil || a.Encoder.Mode == 0 {
+		return 64
+	}
+	return a.Encoder.Mode
+}
+
+// fail records the first error in building the program.
+func (a *Assembler) fail(err error) {
+	if a.err == nil {
+		a.err = err
+	}
+}
+
+// Encode lays out the program and returns its code and the offset of each
+// label in it. Offsets are aligned as though the code starts at an offset
+// aligned to the program's largest alignment.
+func (a *Assembler) Encode() ([]byte, map[string]int, error) {
+	if a.err != nil {
+		return nil, nil, a.err
+	}
+	for _, it := range a.items {
+		if _, ok := a.labels[it.label]; it.label != "" && !ok {
+			return nil, nil, fmt.Errorf("asm: %s refers to undefined label %s", it.src, it.label)
+		}
+	}
+	// Every branch starts short, and branches become long when their
+	// targets are out of range. Lengthening a branch only moves later items
+	// to greater offsets, despite padding, so this ends once no branch
+	// changes.
+	offs := make([]int, len(a.items)+1)
+	for changed := true; changed; {
+		changed = false
+		a.layout(offs)
+		for i := range a.items {
+			it := &a.items[i]
+			if it.label == "" || it.far {
+				continue
+			}
+			end := offs[i] + len(it.short.b)
+			if d := int64(offs[a.labels[it.label]]) + it.addend - int64(end); d != int64(int8(d)) {
+				if it.long == nil {
+					return nil, nil, fmt.Errorf("asm: %s: label %s is out of range", it.src, it.label)
+				}
+				it.far, changed = true, true
+			}
+		}
+	}
+	code := make([]byte, 0, offs[len(a.items)])
+	for i := range a.items {
+		it := &a.items[i]
+		switch {
+		case it.align != 0:
+			pad := offs[i+1] - offs[i]
+			if it.trap {
+				code = x86enc.AppendINT3(code, pad)
+			} else {
+				code = x86enc.AppendNOP(code, pad)
+			}
+			continue
+		case it.label == "":
+			code = append(code, it.b...)
+			continue
+		}
+		f := it.short
+		if it.far {
+			f = it.long
+		}
+		start := len(code)
+		code = append(code, f.b...)
+		d := int64(offs[a.labels[it.label]]) + it.addend - int64(start+len(f.b))
+		if d != int64(int32(d)) {
+			return nil, nil, fmt.Errorf("asm: %s: label %s is out of range", it.src, it.label)
+		}
+		p := code[start+f.at : start+f.at+f.size]
+		if f.size == 1 {
+			p[0] = byte(d)
+		} else {
+			binary.LittleEndian.PutUint32(p, uint32(d))
+		}
+	}
+	labels := make(map[string]int, len(a.labels))
+	for name, i := range a.labels {
+		labels[name] = offs[i]
+	}
+	return code, labels, nil
+}
+
+// layout computes the offset of each item with the current choice of
+// branch forms. offs[len(a.items)] is the length of the program.
+func (a *Assembler) layout(offs []int) {
+	n := 0
+	for i := range a.items {
+		offs[i] = n
+		it := &a.items[i]
+		switch {
+		case it.align != 0:
+			n = (n + it.align - 1) &^ (it.align - 1)
+		case it.label == "":
+			n += len(it.b)
+		case it.far:
+			n += len(it.long.b)
+		default:
+			n += len(it.short.b)
+		}
+	}
+	offs[len(a.items)] = n
+}
+
+// Assemble lays out the program and writes it at the cursor of a writeable
+// block, after padding the block with INT3 to the program's largest
+// alignment. It returns the offset in the block of each label, for use with
+// Block.Func.
+func (a *Assembler) Assemble(b *unsafewx.Block) (map[string]uintptr, error) {
+	code, labels, err := a.Encode()
+	if err != nil {
+		return nil, err
+	}
+	align := 1
+	for _, it := range a.items {
+		if it.align > align {
+			align = it.align
+		}
+	}
+	pad := int(-b.Cursor() & uintptr(align-1))
+	if pad+len(code) > b.Available() {
+		return nil, unsafewx.ErrCapacityExceeded
+	}
+	if _, err := b.Write(x86enc.AppendINT3(nil, pad)); err != nil {
+		return nil, err
+	}
+	base := b.Cursor()
+	if _, err := b.Write(code); err != nil {
+		return nil, err
+	}
+	offs := make(map[string]uintptr, len(labels))
+	for name, off := range labels {
+		offs[name] = base + uintptr(off)
+	}
+	return offs, nil
+}
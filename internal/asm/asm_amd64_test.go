@@ -0,0 +1,193 @@
+package asm_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zephyrtronium/ikitai/internal/asm"
+	"github.com/zephyrtronium/ikitai/internal/unsafewx"
+	. "github.com/zephyrtronium/ikitai/internal/x86enc"
+)
+
+func Example() {
+	// func(n int) int {
+	// 	s := 0
+	// 	for ; n > 0; n-- {
+	// 		s += n
+	// 	}
+	// 	return s
+	// }
+	// Go's internal ABI passes n in AX and expects the result in AX.
+	var a asm.Assembler
+	a.Label("sum")
+	a.Inst("XOR", ECX, ECX)
+	a.Ref("done", "JMP", Rel(0))
+	a.Label("loop")
+	a.Inst("ADD", RCX, RAX)
+	a.Inst("DEC", RAX)
+	a.Label("done")
+	a.Inst("TEST", RAX, RAX)
+	a.Ref("loop", "JG", Rel(0))
+	a.Inst("MOV", RAX, RCX)
+	a.Inst("RET")
+
+	b := unsafewx.MustAlloc(64)
+	defer b.Close()
+	labels, err := a.Assemble(b)
+	if err != nil {
+		panic(err)
+	}
+	b.Exec()
+	var f func(int) int
+	f = b.Func(labels["sum"], reflect.TypeOf(f)).(func(int) int)
+	fmt.Println(f(10), f(0))
+	// Output: 55 0
+}
+
+func ExampleAssembler_Align() {
+	b := unsafewx.MustAlloc(64)
+	defer b.Close()
+	b.Write([]byte{0xc3}) // RET
+	// The program starts at the block's next offset aligned to 16 bytes.
+	var a asm.Assembler
+	a.Align(16)
+	a.Label("seven")
+	a.Inst("MOV", EAX, Imm(7))
+	a.Inst("RET")
+	labels, err := a.Assemble(b)
+	if err != nil {
+		panic(err)
+	}
+	b.Exec()
+	var f func() int32
+	f = b.Func(labels["seven"], reflect.TypeOf(f)).(func() int32)
+	fmt.Println(labels["seven"], f())
+	// Output: 16 7
+}
+
+func ExampleAssembler_Plan9() {
+	src := `
+#include "textflag.h"
+
+// func mix(x, y uint64) uint64
+// Block.Func passes x and y in AX and BX.
+TEXT ·mix(SB), NOSPLIT, $0
+	XORQ BX, AX
+	IMULQ mul<>(SB), AX
+	RET
+
+DATA mul<>+0(SB)/8, $0x9e3779b97f4a7c15
+GLOBL mul<>(SB), RODATA, $8
+`
+	var a asm.Assembler
+	if err := a.Plan9("mix.s", []byte(src)); err != nil {
+		panic(err)
+	}
+	b := unsafewx.MustAlloc(64)
+	defer b.Close()
+	labels, err := a.Assemble(b)
+	if err != nil {
+		panic(err)
+	}
+	b.Exec()
+	var f func(x, y uint64) uint64
+	f = b.Func(labels["mix"], reflect.TypeOf(f)).(func(uint64, uint64) uint64)
+	fmt.Printf("%#x\n", f(1, 3))
+	// Output: 0x3c6ef372fe94f82a
+}
+
+func ExampleAssembler_Intel() {
+	src := `
+; func popcount(x uint64) int
+; Block.Func passes x in RAX.
+popcount:
+	xor ecx, ecx
+	test rax, rax
+	jz done
+loop:	lea rdx, [rax-1]
+	inc ecx
+	and rax, rdx
+	jnz loop
+done:	mov eax, ecx
+	ret
+`
+	var a asm.Assembler
+	if err := a.Intel("popcount.asm", []byte(src)); err != nil {
+		panic(err)
+	}
+	b := unsafewx.MustAlloc(64)
+	defer b.Close()
+	labels, err := a.Assemble(b)
+	if err != nil {
+		panic(err)
+	}
+	b.Exec()
+	var f func(x uint64) int
+	f = b.Func(labels["popcount"], reflect.TypeOf(f)).(func(uint64) int)
+	fmt.Println(f(0xff), f(0), f(1<<63|1))
+	// Output: 8 0 2
+}
+
+// TestPlan9ABI0 tests calling Go assembly functions which take their arguments
+// and return their results through FP.
+func TestPlan9ABI0(t *testing.T) {
+	src := `
+#include "textflag.h"
+
+// func sum(p []int) int
+TEXT ·sum(SB), NOSPLIT, $0-32
+	MOVQ p_base+0(FP), SI
+	MOVQ p_len+8(FP), CX
+	XORL AX, AX
+	JMP test
+loop:
+	ADDQ (SI), AX
+	ADDQ $8, SI
+	DECQ CX
+test:
+	TESTQ CX, CX
+	JNE loop
+	MOVQ AX, ret+24(FP)
+	RET
+
+// func scale(x int32, k int64) (int64, bool)
+TEXT ·scale(SB), $16-25
+	MOVLQSX x+0(FP), AX
+	MOVQ AX, t-8(SP)
+	MOVQ k+8(FP), AX
+	IMULQ t-8(SP), AX
+	MOVQ AX, ret+16(FP)
+	SETLT ret1+24(FP)
+	RET
+`
+	var a asm.Assembler
+	if err := a.Plan9("abi0.s", []byte(src)); err != nil {
+		t.Fatal(err)
+	}
+	b := unsafewx.MustAlloc(256)
+	defer b.Close()
+	labels, err := a.Assemble(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Exec(); err != nil {
+		t.Fatal(err)
+	}
+	var sum func([]int) int
+	sum = b.FuncABI0(labels["sum"], reflect.TypeOf(sum)).(func([]int) int)
+	if r := sum([]int{1, 2, 3, 4}); r != 10 {
+		t.Errorf("wrong sum: wanted 10, have %d", r)
+	}
+	if r := sum(nil); r != 0 {
+		t.Errorf("wrong sum of nil: wanted 0, have %d", r)
+	}
+	var scale func(int32, int64) (int64, bool)
+	scale = b.FuncABI0(labels["scale"], reflect.TypeOf(scale)).(func(int32, int64) (int64, bool))
+	if r, neg := scale(-3, 1<<33); r != -3<<33 || !neg {
+		t.Errorf("wrong scale: wanted %d, true; have %d, %t", -3<<33, r, neg)
+	}
+	if r, neg := scale(7, 6); r != 42 || neg {
+		t.Errorf("wrong scale: wanted 42, false; have %d, %t", r, neg)
+	}
+}
@@ -0,0 +1,198 @@
+package asm
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/arch/x86/x86asm"
+
+	"github.com/zephyrtronium/ikitai/internal/x86enc"
+)
+
+// anyCPU targets every feature, so that tests of encoding do not depend on
+// the processor running them.
+var anyCPU = &x86enc.Encoder{Features: x86enc.AllFeatures}
+
+// disasm decodes code as though it were loaded at 0x1000+at, so that branch
+// targets print as absolute addresses.
+func disasm(t *testing.T, code []byte, at int) []string {
+	t.Helper()
+	var r []string
+	for pc := 0; pc < len(code); {
+		inst, err := x86asm.Decode(code[pc:], 64)
+		if err != nil {
+			t.Fatalf("%x does not decode at %#x: %v", code, pc, err)
+		}
+		r = append(r, x86asm.IntelSyntax(inst, uint64(0x1000+at+pc), nil))
+		pc += inst.Len
+	}
+	return r
+}
+
+func checkCode(t *testing.T, code []byte, at int, want ...string) {
+	t.Helper()
+	got := disasm(t, code, at)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("wrong code %x:\ngot:\n\t%s\nwant:\n\t%s", code, strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+	}
+}
+
+func TestBranches(t *testing.T) {
+	var a Assembler
+	a.Label("top")
+	a.Ref("end", "JE", x86enc.Rel(0))
+	a.Inst("ADD", x86enc.RAX, x86enc.RBX)
+	a.Ref("top", "JMP", x86enc.Rel(0))
+	a.Label("end")
+	a.Inst("RET")
+	code, labels, err := a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCode(t, code, 0,
+		"jz 0x1007",
+		"add rax, rbx",
+		"jmp 0x1000",
+		"ret",
+	)
+	if labels["top"] != 0 || labels["end"] != 7 {
+		t.Errorf("wrong labels %v", labels)
+	}
+}
+
+func TestRelaxation(t *testing.T) {
+	var a Assembler
+	// The first branch reaches its target in 125 bytes while the second is
+	// short, but the second is out of range, so both become long.
+	a.Label("back")
+	a.Raw(make([]byte, 130))
+	a.Ref("far", "JNE", x86enc.Rel(0))
+	a.Raw(make([]byte, 123))
+	a.Ref("back", "JMP", x86enc.Rel(0))
+	a.Label("far")
+	a.Inst("RET")
+	code, labels, err := a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 130 + 6 + 123 + 5 + 1; len(code) != want {
+		t.Fatalf("code is %d bytes, want %d", len(code), want)
+	}
+	checkCode(t, code[130:136], 130, "jnz 0x1108")
+	checkCode(t, code[259:264], 259, "jmp 0x1000")
+	if labels["far"] != 0x108 {
+		t.Errorf("far is at %#x", labels["far"])
+	}
+
+	// Backward branches at the edge of the rel8 range stay short.
+	a = Assembler{}
+	a.Label("top")
+	a.Raw(make([]byte, 126))
+	a.Ref("top", "JMP", x86enc.Rel(0))
+	a.Ref("top", "JMP", x86enc.Rel(0))
+	code, _, err = a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCode(t, code[126:], 126, "jmp 0x1000", "jmp 0x1000")
+	if len(code) != 126+2+5 {
+		t.Errorf("code is %d bytes", len(code))
+	}
+}
+
+func TestRIPRelative(t *testing.T) {
+	var a Assembler
+	a.Ref("data", "LEA", x86enc.RAX, x86enc.Mem{Base: x86enc.RIP})
+	a.Ref("data", "MOV", x86enc.ECX, x86enc.Mem{Base: x86enc.RIP, Disp: 4})
+	a.Inst("RET")
+	a.Label("data")
+	a.Raw([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	code, labels, err := a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCode(t, code[:labels["data"]], 0,
+		"lea rax, ptr [rip+0x7]",
+		"mov ecx, dword ptr [rip+0x5]",
+		"ret",
+	)
+}
+
+func TestAlign(t *testing.T) {
+	var a Assembler
+	a.Inst("XOR", x86enc.EAX, x86enc.EAX)
+	a.Align(16)
+	a.Label("loop")
+	a.Inst("DEC", x86enc.ECX)
+	a.Ref("loop", "JNE", x86enc.Rel(0))
+	a.Inst("RET")
+	a.AlignTrap(8)
+	a.Label("data")
+	a.Raw([]byte{1})
+	code, labels, err := a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCode(t, code[:labels["data"]], 0,
+		"xor eax, eax",
+		"nop word ptr [rax+rax], ax",
+		"nop dword ptr [rax+rax], eax",
+		"dec ecx",
+		"jnz 0x1010",
+		"ret",
+		"int3",
+		"int3",
+		"int3",
+	)
+	if labels["loop"] != 16 || labels["data"] != 24 || len(code) != 25 {
+		t.Errorf("wrong labels %v for %d bytes", labels, len(code))
+	}
+
+	// Padding shrinks when a branch before it becomes long.
+	a = Assembler{}
+	a.Ref("end", "JMP", x86enc.Rel(0))
+	a.Align(16)
+	a.Raw(make([]byte, 130))
+	a.Label("end")
+	code, labels, err = a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCode(t, code[:16], 0, "jmp 0x1092", "nop word ptr [rax+rax], ax", "data16 nop")
+	if labels["end"] != 146 {
+		t.Errorf("end is at %d", labels["end"])
+	}
+}
+
+func TestErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		f    func(a *Assembler)
+		want string
+	}{
+		{"undefined", func(a *Assembler) { a.Ref("nowhere", "JMP", x86enc.Rel(0)) }, "undefined label nowhere"},
+		{"redefined", func(a *Assembler) { a.Label("x"); a.Label("x") }, "label x redefined"},
+		{"encode", func(a *Assembler) { a.Inst("ADD", x86enc.RAX) }, "asm: ADD"},
+		{"align", func(a *Assembler) { a.Align(24) }, "alignment 24 is not a power of two"},
+		{"no rel", func(a *Assembler) { a.Ref("x", "ADD", x86enc.RAX, x86enc.RBX) }, "exactly one relative operand"},
+		{"range", func(a *Assembler) {
+			a.Label("x")
+			a.Raw(make([]byte, 200))
+			a.Ref("x", "JRCXZ", x86enc.Rel(0))
+		}, "label x is out of range"},
+		{"features", func(a *Assembler) {
+			a.Encoder = &x86enc.Encoder{}
+			a.Inst("ADDPD", x86enc.X0, x86enc.X1)
+		}, "requires SSE2"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var a Assembler
+			c.f(&a)
+			_, _, err := a.Encode()
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("wrong error %v, want %q", err, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// eval evaluates a constant expression with Go's operators and precedence:
+// | ^ + - bind more loosely than * / % << >> &, and the unary operators are
+// + - and ~ or ^. Operands are integer and character literals. Arithmetic
+// wraps at 64 bits, and >> is an unsigned shift.
+func eval(s string) (int64, error) {
+	e := exprParser{s: s}
+	v, err := e.expr()
+	if err != nil {
+		return 0, err
+	}
+	if e.skip(); e.i < len(e.s) {
+		return 0, fmt.Errorf("unexpected %q in expression %q", e.s[e.i:], s)
+	}
+	return int64(v), nil
+}
+
+type exprParser struct {
+	s string
+	i int
+}
+
+func (e *exprParser) skip() {
+	for e.i < len(e.s) && (e.s[e.i] == ' ' || e.s[e.i] == '\t') {
+		e.i++
+	}
+}
+
+// op consumes the first of ops which appears next in the input.
+func (e *exprParser) op(ops ...string) string {
+	e.skip()
+	for _, op := range ops {
+		if strings.HasPrefix(e.s[e.i:], op) {
+			e.i += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+func (e *exprParser) expr() (uint64, error) {
+	x, err := e.term()
+	for err == nil {
+		op := e.op("+", "-", "|", "^")
+		if op == "" {
+			break
+		}
+		var y uint64
+		if y, err = e.term(); err != nil {
+			break
+		}
+		switch op {
+		case "+":
+			x += y
+		case "-":
+			x -= y
+		case "|":
+			x |= y
+		case "^":
+			x ^= y
+		}
+	}
+	return x, err
+}
+
+func (e *exprParser) term() (uint64, error) {
+	x, err := e.factor()
+	for err == nil {
+		op := e.op("*", "/", "%", "<<", ">>", "&")
+		if op == "" {
+			break
+		}
+		var y uint64
+		if y, err = e.factor(); err != nil {
+			break
+		}
+		switch op {
+		case "*":
+			x *= y
+		case "/", "%":
+			if y == 0 {
+				return 0, fmt.Errorf("division by zero in %q", e.s)
+			}
+			if op == "/" {
+				x = uint64(int64(x) / int64(y))
+			} else {
+				x = uint64(int64(x) % int64(y))
+			}
+		case "<<":
+			x <<= y
+		case ">>":
+			x >>= y
+		case "&":
+			x &= y
+		}
+	}
+	return x, err
+}
+
+func (e *exprParser) factor() (uint64, error) {
+	switch e.op("(", "+", "-", "~", "^", "'") {
+	case "(":
+		x, err := e.expr()
+		if err != nil {
+			return 0, err
+		}
+		if e.op(")") == "" {
+			return 0, fmt.Errorf("missing ) in expression %q", e.s)
+		}
+		return x, nil
+	case "+":
+		return e.factor()
+	case "-":
+		x, err := e.factor()
+		return -x, err
+	case "~", "^":
+		x, err := e.factor()
+		return ^x, err
+	case "'":
+		r, _, tail, err := strconv.UnquoteChar(e.s[e.i:], '\'')
+		if err != nil || !strings.HasPrefix(tail, "'") {
+			return 0, fmt.Errorf("bad character literal in %q", e.s)
+		}
+		e.i = len(e.s) - len(tail) + 1
+		return uint64(r), nil
+	}
+	j := e.i
+	for j < len(e.s) && (isIdent(e.s[j]) || e.s[j] == '.') {
+		j++
+	}
+	tok := e.s[e.i:j]
+	if tok == "" {
+		return 0, fmt.Errorf("missing operand in expression %q", e.s)
+	}
+	if tok[0] < '0' || tok[0] > '9' {
+		return 0, fmt.Errorf("undefined %s in expression %q", tok, e.s)
+	}
+	x, err := strconv.ParseUint(tok, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad number %s", tok)
+	}
+	e.i = j
+	return x, nil
+}
+
+// isIdent returns whether c can appear in an identifier or number. Bytes of
+// multibyte characters like · count.
+func isIdent(c byte) bool {
+	return c == '_' || c >= 0x80 || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,1022 @@
+package asm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zephyrtronium/ikitai/internal/x86enc"
+)
+
+// Intel parses instructions in Intel syntax, as printed by x86asm.IntelSyntax
+// and objdump -M intel, and adds them to the program. name identifies the
+// source in errors.
+//
+// Each line holds one instruction, optionally preceded by labels and
+// prefixes:
+//
+//	loop:	add rax, qword ptr [rdi+8*rcx]
+//		lock xadd dword ptr [rsi], eax
+//		jnz loop
+//
+// Mnemonics, registers, and operand sizes are case-insensitive, and ";" and
+// "#" begin comments. An identifier as a branch target refers to a label, as
+// does one in a memory operand like [table+8] or [rip+table], which is
+// RIP-relative. Branch targets may also be relative to the end of the
+// instruction, like .+0x4, which is how x86asm prints them when disassembling
+// at pc 0. The lock, rep, and repne prefixes and fs: and gs: segment
+// overrides must suit the instruction, except that rep and repne before
+// instructions other than string instructions are written as raw bytes, as
+// are prefixes like bnd and data16. Operands which the encoding implies but
+// disassemblers show anyway, like the memory operands of string instructions
+// and st0 in x87 instructions, may be given or omitted, though a segment
+// override on the source of a string instruction is kept.
+//
+// Lines copied from objdump -d may keep their addresses and bytes. A symbol
+// heading like "0000000000401000 <main>:" defines a label, and a branch to
+// the address of a line in the source refers to a label named by the source's
+// name and the address, like "dump:0x401010". Other absolute branch targets
+// are errors, since the program's address is not known until it is
+// assembled.
+func (a *Assembler) Intel(name string, src []byte) error {
+	p := intel{
+		a:       a,
+		file:    name,
+		defined: make(map[string]bool),
+		addrs:   make(map[int64]bool),
+		targets: make(map[int64]bool),
+	}
+	err := p.parse(src)
+	if err != nil {
+		a.fail(err)
+	}
+	return err
+}
+
+// ParseIntel parses one instruction in Intel syntax, as accepted by
+// Assembler.Intel, and returns its mnemonic and operands for x86enc.Encode.
+// The lock, rep, and repne prefixes are returned as leading operands. The
+// instruction may not have other prefixes, nor refer to labels. Where
+// disassemblers show operands the encoding implies, ParseIntel returns the
+// operands which encode.
+func ParseIntel(s string) (string, []x86enc.Operand, error) {
+	st, err := parseIntel(s, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("asm: %s: %v", s, err)
+	}
+	if len(st.prefix) > 0 {
+		return "", nil, fmt.Errorf("asm: %s: prefixes require Assembler.Intel", s)
+	}
+	for _, a := range st.args {
+		if a.label != "" {
+			return "", nil, fmt.Errorf("asm: %s: labels require Assembler.Intel", s)
+		}
+	}
+	op, vs := resolveIntel(st.op, st.operands(), 64)
+	if isBranch(op) {
+		for _, o := range vs[0] {
+			if v, ok := o.(x86enc.Imm); ok {
+				return "", nil, fmt.Errorf("asm: %s: branch target %#x is not relative", s, int64(v))
+			}
+		}
+	}
+	var first error
+	for _, v := range vs {
+		v = append(prefixOperands(st.prefixes), v...)
+		_, err := x86enc.Encode(op, v...)
+		if err == nil {
+			return op, v, nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return "", nil, fmt.Errorf("asm: %s: %w", s, first)
+}
+
+// intel is the state of parsing Intel syntax.
+type intel struct {
+	a       *Assembler
+	file    string
+	line    int
+	stmts   []intelStmt
+	pending []string // labels preceding the next statement
+	defined map[string]bool
+	// addrs are the addresses of lines copied from objdump, and targets are
+	// those which branches refer to.
+	addrs   map[int64]bool
+	targets map[int64]bool
+}
+
+// intelStmt is an instruction in Intel syntax.
+type intelStmt struct {
+	line   int
+	addr   int64 // address from objdump, or -1
+	labels []string
+	// prefixes are those which x86enc checks against the instruction, and
+	// prefix holds the bytes of any others.
+	prefixes []x86enc.Prefix
+	prefix   []byte
+	op       string
+	args     []intelArg
+	src      string
+}
+
+// intelArg is an operand in Intel syntax.
+type intelArg struct {
+	op x86enc.Operand
+	// label is the label a branch target or RIP-relative operand refers to.
+	label string
+}
+
+func (st *intelStmt) operands() []x86enc.Operand {
+	ops := make([]x86enc.Operand, len(st.args))
+	for i, a := range st.args {
+		ops[i] = a.op
+	}
+	return ops
+}
+
+func (p *intel) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("asm: %s:%d: %s", p.file, p.line, fmt.Sprintf(format, args...))
+}
+
+var (
+	// objdumpSymbol matches a symbol heading in objdump -d output.
+	objdumpSymbol = regexp.MustCompile(`^[0-9a-fA-F]+ <([^>]+)>:\s*$`)
+	// objdumpBytes matches a line holding only the remaining bytes of a long
+	// instruction.
+	objdumpBytes = regexp.MustCompile(`^\s+[0-9a-fA-F]+:\t[0-9a-fA-F]{2}( [0-9a-fA-F]{2})* *$`)
+	// objdumpLine matches an instruction with its address and, unless
+	// objdump ran with --no-show-raw-insn, bytes.
+	objdumpLine = regexp.MustCompile(`^\s+([0-9a-fA-F]+):\t(?:[0-9a-fA-F]{2}(?: [0-9a-fA-F]{2})* *\t)?(.*)$`)
+	// annotation matches objdump's symbolic annotations like <main+0x10>.
+	annotation = regexp.MustCompile(`<[^>]*>`)
+)
+
+// parse reads each line of the source, then resolves and adds the
+// instructions.
+func (p *intel) parse(src []byte) error {
+	for i, s := range strings.Split(string(src), "\n") {
+		p.line = i + 1
+		if err := p.statement(s); err != nil {
+			return err
+		}
+	}
+	// Resolve every instruction before adding any, so that branches can
+	// refer to the addresses of later lines.
+	ops := make([]string, len(p.stmts))
+	vs := make([][][]x86enc.Operand, len(p.stmts))
+	labels := make([]string, len(p.stmts))
+	for i := range p.stmts {
+		st := &p.stmts[i]
+		p.line = st.line
+		var err error
+		ops[i], vs[i], labels[i], err = p.resolve(st)
+		if err != nil {
+			return err
+		}
+	}
+	for i := range p.stmts {
+		st := &p.stmts[i]
+		p.line = st.line
+		for _, l := range st.labels {
+			if err := p.label(l); err != nil {
+				return err
+			}
+		}
+		if p.targets[st.addr] {
+			if err := p.label(p.addrLabel(st.addr)); err != nil {
+				return err
+			}
+		}
+		if err := p.emit(st, ops[i], vs[i], labels[i]); err != nil {
+			return err
+		}
+	}
+	for _, l := range p.pending {
+		if err := p.label(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// define adds a label to precede the next statement.
+func (p *intel) define(name string) error {
+	if _, ok := p.a.labels[name]; ok || p.defined[name] {
+		return p.errorf("label %s redefined", name)
+	}
+	p.defined[name] = true
+	p.pending = append(p.pending, name)
+	return nil
+}
+
+// label defines a label at the current end of the program.
+func (p *intel) label(name string) error {
+	if _, ok := p.a.labels[name]; ok {
+		return p.errorf("label %s redefined", name)
+	}
+	p.a.Label(name)
+	return nil
+}
+
+// statement parses a line of the source.
+func (p *intel) statement(s string) error {
+	if m := objdumpSymbol.FindStringSubmatch(s); m != nil {
+		return p.define(m[1])
+	}
+	if objdumpBytes.MatchString(s) {
+		return nil
+	}
+	addr := int64(-1)
+	if m := objdumpLine.FindStringSubmatch(s); m != nil {
+		v, err := strconv.ParseUint(m[1], 16, 64)
+		if err != nil {
+			return p.errorf("bad address %s", m[1])
+		}
+		addr, s = int64(v), m[2]
+		p.addrs[addr] = true
+	}
+	if i := strings.IndexAny(s, ";#"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(annotation.ReplaceAllString(s, ""))
+	for {
+		n := labelLen(s)
+		if n == 0 || n >= len(s) || s[n] != ':' {
+			break
+		}
+		if _, ok := intelPrefixes[strings.ToLower(s[:n])]; ok {
+			// A segment override like fs:[rax].
+			break
+		}
+		if err := p.define(s[:n]); err != nil {
+			return err
+		}
+		s = strings.TrimSpace(s[n+1:])
+	}
+	if s == "" {
+		return nil
+	}
+	st, err := parseIntel(s, addr >= 0)
+	if err != nil {
+		return p.errorf("%v", err)
+	}
+	st.line, st.addr, st.labels, p.pending = p.line, addr, p.pending, nil
+	p.stmts = append(p.stmts, st)
+	return nil
+}
+
+// parseIntel parses an instruction with its prefixes. If hex is set, the
+// instruction is from objdump, which shows branch targets as hexadecimal
+// without 0x.
+func parseIntel(s string, hex bool) (intelStmt, error) {
+	st := intelStmt{addr: -1, src: s}
+	for {
+		var word string
+		word, s = cutSpace(s)
+		if s == "" {
+			st.op = word
+			break
+		}
+		if pfx, ok := intelPrefixOps[strings.ToLower(word)]; ok {
+			st.prefixes = append(st.prefixes, pfx)
+			continue
+		}
+		pfx, ok := intelPrefixes[strings.ToLower(word)]
+		if !ok {
+			st.op = word
+			break
+		}
+		st.prefix = append(st.prefix, pfx)
+	}
+	if word, tail := cutSpace(s); strings.EqualFold(word, "far") {
+		// Far CALL, JMP, and RET.
+		st.op, s = "l"+st.op, tail
+	}
+	if s == "" {
+		return st, nil
+	}
+	branch := isBranch(strings.ToUpper(st.op))
+	for _, a := range split(s, ',') {
+		if hex && branch && isHex(a) {
+			a = "0x" + a
+		}
+		arg, err := intelOperand(a)
+		if err != nil {
+			return st, err
+		}
+		if n := len(st.args); n > 0 {
+			if m, ok := st.args[n-1].op.(x86enc.Masked); ok && m.Op == nil {
+				// The mask of a prefetching gather or scatter is written
+				// before its operand.
+				m.Op = arg.op
+				arg.op = m
+				st.args[n-1] = arg
+				continue
+			}
+		}
+		st.args = append(st.args, arg)
+	}
+	return st, nil
+}
+
+// cutSpace splits s at its first run of spaces or tabs.
+func cutSpace(s string) (string, string) {
+	i := strings.IndexAny(s, " \t")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// labelLen returns the length of the label name at the start of s.
+func labelLen(s string) int {
+	i := 0
+	for i < len(s) && (isIdent(s[i]) || s[i] == '.' || s[i] == '$' || s[i] == '@') {
+		i++
+	}
+	if i > 0 && ('0' <= s[0] && s[0] <= '9' || s[0] == '.' && (i == 1 || s[1] == '+' || s[1] == '-')) {
+		return 0
+	}
+	return i
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", rune(s[i])) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// intelPrefixOps are the prefixes of Intel syntax which x86enc encodes.
+var intelPrefixOps = map[string]x86enc.Prefix{
+	"lock": x86enc.LOCK, "rep": x86enc.REP, "repe": x86enc.REP, "repz": x86enc.REP,
+	"repne": x86enc.REPN, "repnz": x86enc.REPN,
+}
+
+// intelPrefixes are the other instruction prefixes and segment overrides of
+// Intel syntax.
+var intelPrefixes = map[string]byte{
+	"xacquire": 0xf2, "xrelease": 0xf3, "bnd": 0xf2, "notrack": 0x3e,
+	"data16": 0x66, "addr32": 0x67,
+	"es": 0x26, "cs": 0x2e, "ss": 0x36, "ds": 0x3e, "fs": 0x64, "gs": 0x65,
+}
+
+// intelRegs are the registers of Intel syntax.
+var intelRegs = func() map[string]x86enc.Reg {
+	m := map[string]x86enc.Reg{"rip": x86enc.RIP, "eip": x86enc.EIP, "st": x86enc.ST0}
+	add := func(r x86enc.Reg) {
+		m[strings.ToLower(r.String())] = r
+	}
+	for i := 0; i < 16; i++ {
+		add(x86enc.GPR8(i))
+		add(x86enc.GPR16(i))
+		add(x86enc.GPR32(i))
+		add(x86enc.GPR64(i))
+		add(x86enc.CR(i))
+	}
+	for i := 16; i < 20; i++ {
+		add(x86enc.GPR8(i))
+	}
+	for i := 0; i < 6; i++ {
+		add(x86enc.Seg(i))
+	}
+	for i := 0; i < 32; i++ {
+		m[fmt.Sprintf("xmm%d", i)] = x86enc.XMM(i)
+		m[fmt.Sprintf("ymm%d", i)] = x86enc.YMM(i)
+		m[fmt.Sprintf("zmm%d", i)] = x86enc.ZMM(i)
+	}
+	for i := 0; i < 8; i++ {
+		m[fmt.Sprintf("k%d", i)] = x86enc.K(i)
+		m[fmt.Sprintf("mm%d", i)] = x86enc.MMX(i)
+		m[fmt.Sprintf("mmx%d", i)] = x86enc.MMX(i)
+		m[fmt.Sprintf("tr%d", i)] = x86enc.TR(i)
+		m[fmt.Sprintf("st%d", i)] = x86enc.ST(i)
+		m[fmt.Sprintf("st(%d)", i)] = x86enc.ST(i)
+		m[fmt.Sprintf("dr%d", i)] = x86enc.DR(i)
+	}
+	return m
+}()
+
+// intelSizes are the widths named by memory operands.
+var intelSizes = map[string]int{
+	"byte": 1, "word": 2, "dword": 4, "fword": 6, "qword": 8, "mmword": 8,
+	"tbyte": 10, "tword": 10, "xmmword": 16, "oword": 16, "ymmword": 32, "zmmword": 64,
+}
+
+// intelRounding are the static rounding modes of EVEX instructions.
+var intelRounding = map[string]x86enc.Rounding{
+	"rn-sae": x86enc.RoundNearest, "rd-sae": x86enc.RoundDown,
+	"ru-sae": x86enc.RoundUp, "rz-sae": x86enc.RoundZero, "sae": x86enc.SAE,
+}
+
+// intelOperand parses an operand.
+func intelOperand(s string) (intelArg, error) {
+	var a intelArg
+	// AVX-512 decorations follow the operand, like zmm1 {k1} {z} or
+	// qword ptr [rax]{1to8}, or are an operand alone, like {rz-sae}.
+	var mask *x86enc.K
+	var zero, bcst bool
+	for strings.HasSuffix(s, "}") {
+		i := strings.LastIndexByte(s, '{')
+		if i < 0 {
+			return a, fmt.Errorf("bad operand %s", s)
+		}
+		d := strings.ToLower(s[i+1 : len(s)-1])
+		s = strings.TrimSpace(s[:i])
+		if rc, ok := intelRounding[d]; ok && s == "" {
+			a.op = rc
+			return a, nil
+		}
+		switch r := intelRegs[d].(type) {
+		case x86enc.K:
+			mask = &r
+			continue
+		}
+		switch {
+		case d == "z":
+			zero = true
+		case strings.HasPrefix(d, "1to"):
+			bcst = true
+		default:
+			return a, fmt.Errorf("bad decoration {%s}", d)
+		}
+	}
+	l := strings.ToLower(s)
+	if s == "" && mask != nil {
+		a.op = x86enc.Masked{Mask: *mask}
+		return a, nil
+	}
+	if r, ok := intelRegs[l]; ok {
+		a.op = r
+	} else if n := labelLen(s); n > 0 && n == len(s) {
+		a.op, a.label = x86enc.Rel(0), s
+	} else if f := strings.Fields(l); len(f) > 1 || strings.ContainsAny(l, "[:") {
+		var err error
+		if a, err = intelMemory(s); err != nil {
+			return a, err
+		}
+	} else if strings.HasPrefix(s, ".") {
+		v, err := eval(s[1:])
+		if err != nil {
+			return a, err
+		}
+		if int64(int32(v)) != v {
+			return a, fmt.Errorf("branch target %s out of range", s)
+		}
+		a.op = x86enc.Rel(v)
+	} else {
+		v, err := eval(s)
+		if err != nil {
+			return a, err
+		}
+		a.op = x86enc.Imm(v)
+	}
+	if bcst {
+		m, ok := a.op.(x86enc.Mem)
+		if !ok {
+			return a, fmt.Errorf("broadcast of non-memory operand %s", s)
+		}
+		m.Broadcast = true
+		a.op = m
+	}
+	if zero && mask == nil {
+		return a, fmt.Errorf("{z} requires a mask in %s", s)
+	}
+	if mask != nil {
+		a.op = x86enc.Masked{Op: a.op, Mask: *mask, Zero: zero}
+	}
+	return a, nil
+}
+
+// intelMemory parses a memory operand like qword ptr fs:[rax+8*rcx+0x10].
+func intelMemory(s string) (intelArg, error) {
+	var a intelArg
+	var m x86enc.Mem
+	rest := s
+	for {
+		word, tail := cutSpace(rest)
+		switch w := strings.ToLower(word); {
+		case intelSizes[w] != 0:
+			m.Size = intelSizes[w]
+		case w == "ptr":
+		case w == "bcst":
+			m.Broadcast = true
+		default:
+			tail = ""
+		}
+		if tail == "" {
+			break
+		}
+		rest = tail
+	}
+	if len(rest) > 3 && rest[2] == ':' {
+		seg, ok := intelRegs[strings.ToLower(rest[:2])].(x86enc.Seg)
+		if !ok {
+			return a, fmt.Errorf("bad segment in %s", s)
+		}
+		// Other segment overrides have no effect in 64-bit mode.
+		switch seg {
+		case x86enc.FS, x86enc.GS:
+			m.Seg = seg
+		}
+		rest = strings.TrimSpace(rest[3:])
+		if !strings.HasPrefix(rest, "[") {
+			// objdump shows absolute addresses like ds:0x1000.
+			rest = "[" + rest + "]"
+		}
+	}
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+		return a, fmt.Errorf("bad operand %s", s)
+	}
+	var disp strings.Builder
+	for _, t := range terms(rest[1 : len(rest)-1]) {
+		sign := t[0]
+		t = strings.TrimSpace(t[1:])
+		if r, ok := intelRegs[strings.ToLower(t)]; ok {
+			switch {
+			case sign == '-':
+				return a, fmt.Errorf("negative register in %s", s)
+			case m.Base == nil:
+				m.Base = r
+			case m.Index == nil:
+				m.Index, m.Scale = r, 1
+			default:
+				return a, fmt.Errorf("too many registers in %s", s)
+			}
+			continue
+		}
+		if i := strings.IndexByte(t, '*'); i >= 0 {
+			x, y := strings.TrimSpace(t[:i]), strings.TrimSpace(t[i+1:])
+			r, ok := intelRegs[strings.ToLower(x)]
+			if !ok {
+				r, ok = intelRegs[strings.ToLower(y)]
+				y = x
+			}
+			if ok {
+				if sign == '-' || m.Index != nil {
+					return a, fmt.Errorf("bad index in %s", s)
+				}
+				v, err := eval(y)
+				if err != nil {
+					return a, err
+				}
+				if v != 1 && v != 2 && v != 4 && v != 8 {
+					return a, fmt.Errorf("bad scale in %s", s)
+				}
+				m.Index, m.Scale = r, uint8(v)
+				continue
+			}
+		}
+		if n := labelLen(t); n > 0 && n == len(t) {
+			if sign == '-' || a.label != "" {
+				return a, fmt.Errorf("bad reference to %s in %s", t, s)
+			}
+			a.label = t
+			continue
+		}
+		disp.WriteByte(sign)
+		disp.WriteString(t)
+	}
+	if disp.Len() > 0 {
+		v, err := eval(disp.String())
+		if err != nil {
+			return a, err
+		}
+		// x86asm shows 32-bit displacements as unsigned.
+		if int64(int32(v)) != v && int64(uint32(v)) != v {
+			return a, fmt.Errorf("displacement out of range in %s", s)
+		}
+		m.Disp = int32(v)
+	}
+	if a.label != "" {
+		if m.Base == nil && m.Index == nil {
+			m.Base = x86enc.RIP
+		}
+		if _, ok := m.Base.(x86enc.IP); !ok || m.Index != nil {
+			return a, fmt.Errorf("reference to %s must be RIP-relative in %s", a.label, s)
+		}
+	}
+	a.op = m
+	return a, nil
+}
+
+// terms splits the inside of a memory operand at each + or - outside of
+// parentheses. Each term begins with its sign.
+func terms(s string) []string {
+	var r []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case (c == '+' || c == '-') && depth == 0 && i > start:
+			if t := strings.TrimSpace(s[start:i]); t != "" && !strings.HasSuffix(t, "*") {
+				r = append(r, t)
+				start = i
+			}
+		}
+	}
+	r = append(r, strings.TrimSpace(s[start:]))
+	for i, t := range r {
+		if t == "" || t[0] != '+' && t[0] != '-' {
+			r[i] = "+" + t
+		}
+	}
+	return r
+}
+
+// resolve finds the mnemonic and operands of an instruction and the label it
+// refers to, if any.
+func (p *intel) resolve(st *intelStmt) (string, [][]x86enc.Operand, string, error) {
+	label := ""
+	for _, a := range st.args {
+		if a.label != "" {
+			if label != "" {
+				return "", nil, "", p.errorf("%s refers to more than one label", st.src)
+			}
+			label = a.label
+		}
+	}
+	op, vs := resolveIntel(st.op, st.operands(), p.a.mode())
+	if !isBranch(op) {
+		return op, vs, label, nil
+	}
+	for i, o := range vs[0] {
+		v, ok := o.(x86enc.Imm)
+		if !ok {
+			continue
+		}
+		if !p.addrs[int64(v)] {
+			return "", nil, "", p.errorf("branch target %#x is not an address in the source", int64(v))
+		}
+		p.targets[int64(v)] = true
+		label = p.addrLabel(int64(v))
+		for _, args := range vs {
+			args[i] = x86enc.Rel(0)
+		}
+	}
+	return op, vs, label, nil
+}
+
+func (p *intel) addrLabel(addr int64) string {
+	return fmt.Sprintf("%s:%#x", p.file, addr)
+}
+
+// emit adds an instruction with the first of its sets of operands which
+// encodes.
+func (p *intel) emit(st *intelStmt, op string, vs [][]x86enc.Operand, label string) error {
+	var hints []byte
+	var ops []x86enc.Prefix
+	for _, pfx := range st.prefixes {
+		if pfx != x86enc.LOCK && !isStringOp(op) {
+			// Before other instructions, rep and repne are hints like
+			// rep ret and bnd.
+			hints = append(hints, byte(pfx))
+			continue
+		}
+		ops = append(ops, pfx)
+	}
+	var first error
+	for _, args := range vs {
+		args = append(prefixOperands(ops), args...)
+		b, err := p.a.encode(op, args)
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		prefix := append(hints, st.prefix...)
+		if len(prefix) > 0 && prefix[len(prefix)-1] == 0x67 && len(b) > 0 && b[0] == 0x67 {
+			// x86asm shows the address size prefix of JECXZ.
+			prefix = prefix[:len(prefix)-1]
+		}
+		if len(prefix) > 0 {
+			p.a.Raw(prefix)
+		}
+		if label != "" {
+			err = p.a.ref(label, op, args)
+		} else {
+			err = p.a.inst(op, args)
+		}
+		if err != nil {
+			return p.errorf("%v", err)
+		}
+		return nil
+	}
+	if first == nil {
+		return p.errorf("unknown instruction %s", st.op)
+	}
+	return p.errorf("%s: %v", st.src, first)
+}
+
+// isBranch returns whether an instruction takes a relative branch target.
+func isBranch(op string) bool {
+	switch op {
+	case "CALL", "LOOP", "LOOPE", "LOOPNE", "XBEGIN":
+		return true
+	}
+	return strings.HasPrefix(op, "J")
+}
+
+// intelAliases are mnemonics of Intel syntax which x86enc names differently.
+var intelAliases = map[string]string{
+	"MOVABS": "MOV", "INT1": "ICEBP", "XLAT": "XLATB",
+	"LOOPZ": "LOOPE", "LOOPNZ": "LOOPNE",
+}
+
+// intelConds are the alternative names of condition codes.
+var intelConds = map[string]string{
+	"C": "B", "NAE": "B", "NB": "AE", "NC": "AE", "Z": "E", "NZ": "NE",
+	"NA": "BE", "NBE": "A", "PE": "P", "PO": "NP",
+	"NGE": "L", "NL": "GE", "NG": "LE", "NLE": "G",
+}
+
+// intelPredicates are the comparison predicates named by mnemonics like
+// cmpltps and vcmpneq_oqpd, in order of their immediates. SSE has only the
+// first eight.
+var intelPredicates = []string{
+	"eq", "lt", "le", "unord", "neq", "nlt", "nle", "ord",
+	"eq_uq", "nge", "ngt", "false", "neq_oq", "ge", "gt", "true",
+	"eq_os", "lt_oq", "le_oq", "unord_s", "neq_us", "nlt_uq", "nle_uq", "ord_s",
+	"eq_us", "nge_uq", "ngt_uq", "false_os", "neq_os", "ge_oq", "gt_oq", "true_us",
+}
+
+// stringOps are the string instructions, whose operands are implied.
+var stringOps = map[string]bool{
+	"MOVS": true, "CMPS": true, "STOS": true, "LODS": true, "SCAS": true, "INS": true, "OUTS": true,
+}
+
+// stringAddr returns the operands of a string instruction whose memory
+// operands are args: a prefix overriding the segment of the source, and
+// ADDRSIZE if the addresses are of a size other than the mode's. The
+// destination is always in ES, so if it names another segment, stringAddr
+// returns args, which no form accepts.
+func stringAddr(args []x86enc.Operand, mode int) []x86enc.Operand {
+	var ops []x86enc.Operand
+	var addr, sized bool
+	for _, a := range args {
+		switch a := a.(type) {
+		case x86enc.Seg:
+			// x86asm shows only the segment of xlat and outs.
+			ops = append(ops, x86enc.SegPrefix(a))
+		case x86enc.Mem:
+			if a.Seg != nil {
+				s, ok := a.Seg.(x86enc.Seg)
+				switch a.Base {
+				case x86enc.RDI, x86enc.EDI, x86enc.DI:
+					if s != x86enc.ES {
+						return args
+					}
+				default:
+					if !ok {
+						return args
+					}
+					ops = append(ops, x86enc.SegPrefix(s))
+				}
+			}
+			if sized {
+				continue
+			}
+			sized = true
+			switch a.Base.(type) {
+			case x86enc.GPR16:
+				addr = mode == 32
+			case x86enc.GPR32:
+				addr = mode == 64
+			}
+		}
+	}
+	if addr {
+		ops = append(ops, x86enc.ADDRSIZE)
+	}
+	return ops
+}
+
+// prefixOperands returns prefixes as operands for x86enc.
+func prefixOperands(ps []x86enc.Prefix) []x86enc.Operand {
+	if len(ps) == 0 {
+		return nil
+	}
+	ops := make([]x86enc.Operand, len(ps))
+	for i, p := range ps {
+		ops[i] = p
+	}
+	return ops
+}
+
+// isStringOp returns whether op is an x86enc mnemonic of a string
+// instruction, like MOVSB.
+func isStringOp(op string) bool {
+	return len(op) >= 4 && stringOps[op[:len(op)-1]] && strings.ContainsRune("BWDQ", rune(op[len(op)-1]))
+}
+
+// resolveIntel returns the x86enc mnemonic for an Intel mnemonic and the sets
+// of operands the instruction may mean, in order of preference, when encoding
+// in the given mode.
+func resolveIntel(op string, args []x86enc.Operand, mode int) (string, [][]x86enc.Operand) {
+	op = strings.ToUpper(op)
+	if alias, ok := intelAliases[op]; ok {
+		op = alias
+	}
+	vector := false
+	for _, a := range args {
+		_, ok := a.(x86enc.XMM)
+		vector = vector || ok
+	}
+	switch {
+	case op == "INT3":
+		return "INT", [][]x86enc.Operand{{x86enc.Imm(3)}}
+	case (op == "MOVSD" || op == "CMPSD") && vector:
+		op += "_XMM"
+	case isStringOp(op), op == "XLATB":
+		return op, [][]x86enc.Operand{stringAddr(args, mode)}
+	case stringOps[op]:
+		// objdump omits the width from the mnemonic.
+		for _, a := range args {
+			if m, ok := a.(x86enc.Mem); ok {
+				switch m.Size {
+				case 1:
+					op += "B"
+				case 2:
+					op += "W"
+				case 4:
+					op += "D"
+				case 8:
+					op += "Q"
+				}
+				break
+			}
+		}
+		return op, [][]x86enc.Operand{stringAddr(args, mode)}
+	}
+	for _, p := range []string{"J", "SET", "CMOV"} {
+		if c, ok := intelConds[strings.TrimPrefix(op, p)]; ok && strings.HasPrefix(op, p) {
+			op = p + c
+			break
+		}
+	}
+	if o, imm, ok := predicate(op); ok {
+		op = o
+		n := len(args)
+		if n > 0 {
+			if _, ok := args[n-1].(x86enc.Rounding); ok {
+				n--
+			}
+		}
+		args = append(append(args[:n:n], imm), args[n:]...)
+	}
+	vs := [][]x86enc.Operand{args}
+	n := len(args)
+	switch {
+	case strings.HasPrefix(op, "F") && n > 0:
+		// Disassemblers show st0 where x87 instructions imply it.
+		if args[0] == x86enc.ST0 {
+			vs = append(vs, args[1:])
+		}
+		if args[n-1] == x86enc.ST0 {
+			vs = append(vs, args[:n-1])
+		}
+		all := true
+		for _, a := range args {
+			_, ok := a.(x86enc.ST)
+			all = all && ok
+		}
+		if all {
+			vs = append(vs, nil)
+		}
+		// x86asm shows 80-bit memory operands without a width.
+		k := len(vs)
+		vs = relax(vs, func(o x86enc.Operand) (x86enc.Operand, bool) {
+			m, ok := o.(x86enc.Mem)
+			if !ok || m.Size != 0 {
+				return o, false
+			}
+			m.Size = 10
+			return m, true
+		})
+		vs = append(vs[k:], vs[:k]...)
+	case op == "NOP" && n == 2:
+		// x86asm shows a register operand of multi-byte NOP.
+		vs = append(vs, args[:1])
+	case op == "PUSH" && n == 1:
+		// x86asm shows the sign-extended immediate as 32 bits.
+		if v, ok := args[0].(x86enc.Imm); ok && v.FitsUnsigned(32) && !v.FitsSigned(32) {
+			vs = append(vs, []x86enc.Operand{x86enc.Imm(int32(v))})
+		}
+	case intelUnsized[op]:
+		vs = relax(vs, func(o x86enc.Operand) (x86enc.Operand, bool) {
+			m, ok := o.(x86enc.Mem)
+			if !ok || m.Size == 0 {
+				return o, false
+			}
+			m.Size = 0
+			return m, true
+		})
+	case strings.HasPrefix(op, "V"):
+		// x86asm omits {1toN} when the destination is a mask register.
+		vs = relax(vs, func(o x86enc.Operand) (x86enc.Operand, bool) {
+			m, ok := o.(x86enc.Mem)
+			if !ok || m.Broadcast || m.Index != nil {
+				return o, false
+			}
+			m.Broadcast = true
+			return m, true
+		})
+		// x86asm shows the rounding bits of forms which only suppress
+		// exceptions.
+		if n == 0 {
+			break
+		}
+		if rc, ok := args[n-1].(x86enc.Rounding); ok && rc != x86enc.SAE {
+			vs = append(vs, append(args[:n-1:n-1], x86enc.SAE))
+		}
+		if n == 1 {
+			// x86asm shows a mask register source like an opmask, or
+			// omits it if it is k0.
+			if m, ok := args[0].(x86enc.Masked); ok && !m.Zero {
+				vs = append(vs, []x86enc.Operand{m.Op, m.Mask})
+			} else {
+				vs = append(vs, []x86enc.Operand{args[0], x86enc.K0})
+			}
+		}
+	}
+	// x86asm shows some 8-bit immediates as unsigned.
+	vs = relax(vs, func(o x86enc.Operand) (x86enc.Operand, bool) {
+		v, ok := o.(x86enc.Imm)
+		if !ok || v < 0x80 || v > 0xff {
+			return o, false
+		}
+		return x86enc.Imm(int8(v)), true
+	})
+	return op, vs
+}
+
+// intelUnsized are the instructions whose memory operands x86asm shows with
+// widths that their forms do not have.
+var intelUnsized = map[string]bool{
+	"CLFLUSH": true, "PREFETCHW": true, "PREFETCHNTA": true,
+	"PREFETCHT0": true, "PREFETCHT1": true, "PREFETCHT2": true,
+	"LCALL": true, "LJMP": true,
+}
+
+// relax adds to vs each set of operands with f applied to its operands, if f
+// changes any of them.
+func relax(vs [][]x86enc.Operand, f func(x86enc.Operand) (x86enc.Operand, bool)) [][]x86enc.Operand {
+	for _, v := range vs {
+		w := make([]x86enc.Operand, len(v))
+		changed := false
+		for i, o := range v {
+			var ok bool
+			w[i], ok = f(o)
+			changed = changed || ok
+		}
+		if changed {
+			vs = append(vs, w)
+		}
+	}
+	return vs
+}
+
+// predicate parses a comparison mnemonic with a predicate, like cmpltps,
+// returning the mnemonic without it and the predicate's immediate.
+func predicate(op string) (string, x86enc.Imm, bool) {
+	v := strings.HasPrefix(op, "V")
+	s := strings.TrimPrefix(op, "V")
+	if !strings.HasPrefix(s, "CMP") || len(s) < 6 {
+		return "", 0, false
+	}
+	suffix := s[len(s)-2:]
+	switch suffix {
+	case "PS", "PD", "SS", "SD":
+	default:
+		return "", 0, false
+	}
+	name := strings.ToLower(s[3 : len(s)-2])
+	preds := intelPredicates
+	if !v {
+		preds = preds[:8]
+	}
+	for i, p := range preds {
+		if p != name {
+			continue
+		}
+		op = "CMP" + suffix
+		if v {
+			op = "V" + op
+		} else if suffix == "SD" {
+			op = "CMPSD_XMM"
+		}
+		return op, x86enc.Imm(i), true
+	}
+	return "", 0, false
+}
@@ -0,0 +1,233 @@
+package asm
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/arch/x86/x86asm"
+
+	"github.com/zephyrtronium/ikitai/internal/x86enc"
+)
+
+// TestIntelRoundTrip assembles instructions as x86asm.IntelSyntax prints them
+// and checks that they disassemble to the same text.
+func TestIntelRoundTrip(t *testing.T) {
+	cases := []string{
+		"mov rax, qword ptr [rsp+0x8]",
+		"lea rcx, ptr [r12+4*r13+0x40]",
+		"add dword ptr [rdi+rcx], -0x1",
+		"sub qword ptr [rbx+8*rcx+0xfffedcbb], rax",
+		"mov byte ptr [rip+0x10], 0x7f",
+		"mov eax, dword ptr [0x1000]",
+		"mov r9, 0x123456789",
+		"mov rax, qword ptr fs:[0x28]",
+		"mov qword ptr gs:[rcx+0x30], rdx",
+		"lock xadd dword ptr [rsi], eax",
+		"lock cmpxchg16b xmmword ptr [rdi]",
+		"rep stosq qword ptr [rdi]",
+		"repne scasb byte ptr [rdi]",
+		"rep cmpsb byte ptr [rsi], byte ptr [rdi]",
+		"rep movsd dword ptr [edi], dword ptr [esi]",
+		"bnd ret",
+		"movsq qword ptr [rdi], qword ptr [rsi]",
+		"setnle al",
+		"cmovnbe rax, rcx",
+		"jz .+0x4",
+		"jmp .-0x2",
+		"call .+0x100",
+		"int3",
+		"push 0xffffffff",
+		"nop dword ptr [rax], eax",
+		"movsd xmm0, qword ptr [rax]",
+		"movq rax, xmm1",
+		"pshufb mmx0, mmx1",
+		"mov cr0, rax",
+		"fld st0, st1",
+		"fld st0, ptr [rax]",
+		"fstp qword ptr [rax], st0",
+		"vroundpd xmm15, xmm15, 0x80",
+		"vcmpltps xmm1, xmm2, xmm3",
+		"vaddpd zmm3 {k1} {z}, zmm2, zmm1",
+		"vaddps zmm1, zmm2, dword ptr [rax]{1to16}",
+		"vaddpd zmm1, zmm2, zmm3, {rz-sae}",
+		"vpcmpeqd k1, zmm0, dword ptr [rcx+0x4]",
+		"vgatherdps zmm1 {k1}, dword ptr [rax+4*zmm2]",
+		"vgatherpf0dps {k1}, dword ptr [rax+8*zmm1]",
+		"vpmovm2d xmm3 {k1}",
+		"xlat",
+		"xlat fs",
+		"movsb byte ptr [rdi], byte ptr fs:[rsi]",
+		"rep cmpsb byte ptr gs:[rsi], byte ptr [rdi]",
+		"lodsd dword ptr fs:[esi]",
+	}
+	for _, want := range cases {
+		t.Run(want, func(t *testing.T) {
+			a := Assembler{Encoder: anyCPU}
+			if err := a.Intel("test.asm", []byte(want)); err != nil {
+				t.Fatal(err)
+			}
+			code, _, err := a.Encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for pc := 0; pc < len(code); {
+				inst, err := x86asm.Decode(code[pc:], 64)
+				if err != nil {
+					t.Fatalf("%x does not decode: %v", code, err)
+				}
+				got = append(got, x86asm.IntelSyntax(inst, 0, nil))
+				pc += inst.Len
+			}
+			if s := strings.Join(got, "; "); s != want {
+				t.Errorf("%x disassembles as %q", code, s)
+			}
+		})
+	}
+}
+
+// TestIntel tests labels and RIP-relative references to them.
+func TestIntel(t *testing.T) {
+	src := `
+; Sum the integers below the second element of the table.
+sum:	mov ecx, dword ptr [table+4]
+	xor eax, eax
+	jmp test
+loop:	add eax, ecx
+test:	dec ecx
+	jns loop
+	lea rdx, [rip+table]
+	ret
+`
+	a := Assembler{Encoder: anyCPU}
+	if err := a.Intel("test.asm", []byte(src)); err != nil {
+		t.Fatal(err)
+	}
+	a.Label("table")
+	a.Raw([]byte{1, 0, 0, 0, 10, 0, 0, 0})
+	code, labels, err := a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCode(t, code[:labels["table"]], 0,
+		"mov ecx, dword ptr [rip+0x16]",
+		"xor eax, eax",
+		"jmp 0x100c",
+		"add eax, ecx",
+		"dec ecx",
+		"jns 0x100a",
+		"lea rdx, ptr [rip+0x1]",
+		"ret",
+	)
+	if labels["sum"] != 0 || labels["loop"] != 10 || labels["test"] != 12 {
+		t.Errorf("wrong labels %v", labels)
+	}
+}
+
+// TestIntelObjdump tests assembling lines copied from objdump.
+func TestIntelObjdump(t *testing.T) {
+	src := `
+0000000000401000 <count>:
+  401000:	31 c0                	xor    eax,eax
+  401002:	eb 05                	jmp    401009 <count+0x9>
+  401004:	48 ff c0             	inc    rax
+  401007:	ff cf                	dec    edi
+  401009:	85 ff                	test   edi,edi
+  40100b:	7f f7                	jg     401004 <count+0x4>
+  40100d:	48 8b 0d ec 0f 00 00 	mov    rcx,QWORD PTR [rip+0xfec]        # 402000 <data>
+  401014:	48 b8 88 77 66 55 44 	movabs rax,0x1122334455667788
+  40101b:	33 22 11
+  40101e:	c3                   	ret
+`
+	a := Assembler{Encoder: anyCPU}
+	if err := a.Intel("dump", []byte(src)); err != nil {
+		t.Fatal(err)
+	}
+	code, labels, err := a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		0x31, 0xc0, 0xeb, 0x05, 0x48, 0xff, 0xc0, 0xff, 0xcf, 0x85, 0xff, 0x7f, 0xf7,
+		0x48, 0x8b, 0x0d, 0xec, 0x0f, 0x00, 0x00,
+		0x48, 0xb8, 0x88, 0x77, 0x66, 0x55, 0x44, 0x33, 0x22, 0x11, 0xc3,
+	}
+	if !bytes.Equal(code, want) {
+		t.Errorf("wrong code %x, want %x", code, want)
+	}
+	if labels["count"] != 0 || labels["dump:0x401004"] != 4 || labels["dump:0x401009"] != 9 {
+		t.Errorf("wrong labels %v", labels)
+	}
+}
+
+func TestParseIntel(t *testing.T) {
+	cases := []struct {
+		src  string
+		op   string
+		args []x86enc.Operand
+	}{
+		{"mov rax, qword ptr [rsp+8]", "MOV", []x86enc.Operand{x86enc.RAX, x86enc.Mem{Base: x86enc.RSP, Disp: 8, Size: 8}}},
+		{"MOV EAX, DWORD PTR [RBX+RCX*4-0x10]", "MOV", []x86enc.Operand{x86enc.EAX, x86enc.Mem{Base: x86enc.RBX, Index: x86enc.RCX, Scale: 4, Disp: -0x10, Size: 4}}},
+		{"jnz .-2", "JNE", []x86enc.Operand{x86enc.Rel(-2)}},
+		{"fld st0, st(1)", "FLD", []x86enc.Operand{x86enc.ST1}},
+		{"stosd dword ptr es:[rdi], eax", "STOSD", nil},
+		{"movsd xmm1, xmm2", "MOVSD_XMM", []x86enc.Operand{x86enc.X1, x86enc.X2}},
+		{"cmpltsd xmm1, xmm2", "CMPSD_XMM", []x86enc.Operand{x86enc.X1, x86enc.X2, x86enc.Imm(1)}},
+		{
+			"vaddps zmm1 {k2}{z}, zmm2, dword bcst [rax]",
+			"VADDPS",
+			[]x86enc.Operand{x86enc.Masked{Op: x86enc.Z1, Mask: x86enc.K2, Zero: true}, x86enc.Z2, x86enc.Mem{Base: x86enc.RAX, Size: 4, Broadcast: true}},
+		},
+		{"vcmpnle_uqpd k1, zmm2, zmm3, {sae}", "VCMPPD", []x86enc.Operand{x86enc.K1, x86enc.Z2, x86enc.Z3, x86enc.Imm(22), x86enc.SAE}},
+		{"lock add dword ptr [rax], eax", "ADD", []x86enc.Operand{x86enc.LOCK, x86enc.Mem{Base: x86enc.RAX, Size: 4}, x86enc.EAX}},
+		{"rep movsb byte ptr [edi], byte ptr [esi]", "MOVSB", []x86enc.Operand{x86enc.REP, x86enc.ADDRSIZE}},
+		{"movsb byte ptr [edi], byte ptr fs:[esi]", "MOVSB", []x86enc.Operand{x86enc.SegPrefix(x86enc.FS), x86enc.ADDRSIZE}},
+		{"mov rax, qword ptr fs:[0xfffffffffffffff8]", "MOV", []x86enc.Operand{x86enc.RAX, x86enc.Mem{Seg: x86enc.FS, Disp: -8, Size: 8}}},
+	}
+	for _, c := range cases {
+		op, args, err := ParseIntel(c.src)
+		if err != nil {
+			t.Errorf("%s: %v", c.src, err)
+			continue
+		}
+		if op != c.op || !reflect.DeepEqual(args, c.args) {
+			t.Errorf("%s: got %s %v, want %s %v", c.src, op, args, c.op, c.args)
+		}
+	}
+	for _, s := range []string{"lock add eax, ecx", "rep add eax, ecx", "bnd jmp rax", "jmp loop", "jmp 0x10", "stosb byte ptr fs:[rdi], al", "mov rax, [rbx+3*rcx]", "frob"} {
+		if op, args, err := ParseIntel(s); err == nil {
+			t.Errorf("%s: got %s %v, want error", s, op, args)
+		}
+	}
+}
+
+func TestIntelErrors(t *testing.T) {
+	cases := []struct {
+		src  string
+		want string
+	}{
+		{"\tfrob rax\n", "test.asm:2: frob rax: x86enc:"},
+		{"\tadd rax\n", "test.asm:2: add rax: x86enc: no form"},
+		{"\tmov rax, [rax+rbx+rcx]\n", "test.asm:2: too many registers"},
+		{"\tmov rax, [rbx+3*rcx]\n", "bad scale"},
+		{"\tmov rax, [rbx+0x100000000]\n", "displacement out of range"},
+		{"\tmov rax, [rbx+table]\n", "must be RIP-relative"},
+		{"\tvaddpd zmm1 {z}, zmm2, zmm3\n", "{z} requires a mask"},
+		{"\tjmp 0x1000\n", "test.asm:2: branch target 0x1000 is not an address in the source"},
+		{"x:\nx:\n\tret\n", "test.asm:3: label x redefined"},
+	}
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			a := Assembler{Encoder: anyCPU}
+			err := a.Intel("test.asm", []byte("\n"+c.src))
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("wrong error %v, want %q", err, c.want)
+			}
+			if _, _, err := a.Encode(); err == nil {
+				t.Errorf("Encode succeeded after error")
+			}
+		})
+	}
+}
@@ -0,0 +1,873 @@
+package asm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/zephyrtronium/ikitai/internal/x86enc"
+)
+
+// Plan9 parses Go assembly source for amd64, as accepted by the go tool's
+// assembler, and adds its functions and data to the program. name identifies
+// the source in errors.
+//
+// Each TEXT and GLOBL symbol becomes a label named by the symbol without its
+// package qualifier or <> suffix, so ·add(SB) is "add" and
+// runtime·memmove(SB) is "runtime.memmove". Labels within a function are
+// named by the function and the label, like "add:loop".
+//
+// The source may use #define, #undef, #ifdef, #ifndef, #else, and #endif, and
+// may include "textflag.h" and "funcdata.h". FUNCDATA and PCDATA are ignored,
+// and PCALIGN pads with NOPs. Functions with frames get the go tool's prologue
+// and epilogue saving BP, and FP and named SP operands refer to arguments and
+// locals accordingly, but no stack check is inserted: every function behaves
+// as though it were NOSPLIT. Data must be RODATA, since the block is
+// read-only once it is executable, and is aligned by its size up to 32 bytes,
+// as the go tool's linker aligns it. Floating-point immediates are loaded from
+// data labeled as the go tool does, like $f64.3ff0000000000000. Taking the
+// address of a symbol as an immediate is not supported.
+//
+// The source's functions follow ABI0, with arguments and results on the
+// stack, so functions which use FP to reach their arguments and results, as
+// Go assembly usually does, must be called through Block.FuncABI0. Block.Func
+// calls code with Go's register-based internal ABI, so functions called
+// through it must take their arguments from registers instead.
+func (a *Assembler) Plan9(name string, src []byte) error {
+	p := plan9{a: a, file: name, macros: make(map[string]*macro), globals: make(map[string]*global)}
+	for _, m := range []string{"GOARCH_amd64", "GOAMD64_v1", "GOOS_" + runtime.GOOS} {
+		p.macros[m] = &macro{body: "1"}
+	}
+	err := p.parse(src)
+	if err != nil {
+		a.fail(err)
+	}
+	return err
+}
+
+// plan9 is the state of parsing Go assembly.
+type plan9 struct {
+	a       *Assembler
+	file    string
+	line    int
+	macros  map[string]*macro
+	text    *text
+	pending []string // labels preceding the next statement
+	syms    map[string]int
+	globals map[string]*global
+	order   []*global
+}
+
+// macro is a #define. params is nil for macros which take no arguments.
+type macro struct {
+	params []string
+	body   string
+}
+
+// text is a function being assembled.
+type text struct {
+	sym   string
+	line  int
+	frame int64 // size of locals
+	bp    int64 // 8 if the function saves BP
+	stmts []stmt
+	end   []string // labels following the last statement
+}
+
+// stmt is an instruction in a function.
+type stmt struct {
+	line   int
+	labels []string
+	op     string
+	args   []string
+}
+
+// global is a symbol declared by GLOBL or filled by DATA.
+type global struct {
+	sym   string
+	line  int
+	data  []byte
+	size  int64 // -1 until GLOBL
+	flags int64
+}
+
+// Flags from textflag.h.
+const (
+	flagRODATA  = 8
+	flagNOFRAME = 512
+)
+
+var textflags = map[string]string{
+	"NOPROF": "1", "DUPOK": "2", "NOSPLIT": "4", "RODATA": "8", "NOPTR": "16",
+	"WRAPPER": "32", "NEEDCTXT": "64", "TLSBSS": "256", "NOFRAME": "512",
+	"REFLECTMETHOD": "1024", "TOPFRAME": "2048", "ABIWRAPPER": "4096",
+}
+
+func (p *plan9) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("asm: %s:%d: %s", p.file, p.line, fmt.Sprintf(format, args...))
+}
+
+// parse preprocesses the source and handles each statement.
+func (p *plan9) parse(src []byte) error {
+	lines := strings.Split(string(src), "\n")
+	var conds []cond
+	comment := false
+	for i := 0; i < len(lines); i++ {
+		p.line = i + 1
+		var s string
+		s, comment = uncomment(lines[i], comment)
+		for strings.HasSuffix(s, `\`) && i+1 < len(lines) {
+			i++
+			var t string
+			t, comment = uncomment(lines[i], comment)
+			s = s[:len(s)-1] + " " + t
+		}
+		s = strings.TrimSpace(s)
+		if strings.HasPrefix(s, "#") {
+			if err := p.directive(s[1:], &conds); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(conds) > 0 && !conds[len(conds)-1].on {
+			continue
+		}
+		s, err := p.expand(s, nil)
+		if err != nil {
+			return p.errorf("%v", err)
+		}
+		for _, st := range split(s, ';') {
+			if err := p.statement(st); err != nil {
+				return err
+			}
+		}
+	}
+	if comment {
+		return p.errorf("unterminated comment")
+	}
+	if len(conds) > 0 {
+		return p.errorf("missing #endif")
+	}
+	return p.finish()
+}
+
+// uncomment removes comments from a line. in is whether the line begins inside
+// a block comment, and uncomment returns whether it ends inside one.
+func uncomment(s string, in bool) (string, bool) {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case in:
+			if strings.HasPrefix(s[i:], "*/") {
+				in = false
+				i++
+				b.WriteByte(' ')
+			}
+			continue
+		case quote != 0:
+			if c == '\\' && i+1 < len(s) {
+				b.WriteByte(c)
+				i++
+				c = s[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(s[i:], "//"):
+			return b.String(), false
+		case strings.HasPrefix(s[i:], "/*"):
+			in = true
+			i++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), in
+}
+
+// split splits s at each sep outside of parentheses and quotes.
+func split(s string, sep byte) []string {
+	var r []string
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			r = append(r, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(r, strings.TrimSpace(s[start:]))
+}
+
+// ident returns the length of the identifier at the start of s.
+func ident(s string) int {
+	i := 0
+	for i < len(s) && isIdent(s[i]) {
+		i++
+	}
+	if i > 0 && '0' <= s[0] && s[0] <= '9' {
+		return 0
+	}
+	return i
+}
+
+// cond is an #ifdef or #ifndef. on is whether lines in it are assembled, and
+// outer is whether lines around it are.
+type cond struct {
+	on, outer, sawElse bool
+}
+
+// directive handles a preprocessor directive.
+func (p *plan9) directive(s string, conds *[]cond) error {
+	s = strings.TrimSpace(s)
+	n := ident(s)
+	d, rest := s[:n], strings.TrimSpace(s[n:])
+	on := len(*conds) == 0 || (*conds)[len(*conds)-1].on
+	switch d {
+	case "ifdef", "ifndef":
+		if ident(rest) != len(rest) || rest == "" {
+			return p.errorf("#%s requires a name", d)
+		}
+		_, ok := p.macros[rest]
+		*conds = append(*conds, cond{on: on && ok == (d == "ifdef"), outer: on})
+		return nil
+	case "else", "endif":
+		if len(*conds) == 0 {
+			return p.errorf("#%s without #ifdef", d)
+		}
+		c := &(*conds)[len(*conds)-1]
+		if d == "endif" {
+			*conds = (*conds)[:len(*conds)-1]
+			return nil
+		}
+		if c.sawElse {
+			return p.errorf("second #else")
+		}
+		c.on, c.sawElse = c.outer && !c.on, true
+		return nil
+	}
+	if !on {
+		return nil
+	}
+	switch d {
+	case "include":
+		f, err := strconv.Unquote(rest)
+		if err != nil {
+			return p.errorf("bad #include %s", rest)
+		}
+		switch f {
+		case "textflag.h":
+			for k, v := range textflags {
+				p.macros[k] = &macro{body: v}
+			}
+		case "funcdata.h":
+			for _, k := range []string{"NO_LOCAL_POINTERS", "GO_ARGS", "GO_RESULTS_INITIALIZED"} {
+				p.macros[k] = &macro{}
+			}
+		default:
+			return p.errorf("cannot include %s", f)
+		}
+	case "define":
+		n := ident(rest)
+		if n == 0 {
+			return p.errorf("#define requires a name")
+		}
+		name, body := rest[:n], rest[n:]
+		m := &macro{}
+		if strings.HasPrefix(body, "(") {
+			end := strings.IndexByte(body, ')')
+			if end < 0 {
+				return p.errorf("bad parameters of macro %s", name)
+			}
+			m.params = []string{}
+			if params := strings.TrimSpace(body[1:end]); params != "" {
+				for _, param := range strings.Split(params, ",") {
+					param = strings.TrimSpace(param)
+					if ident(param) != len(param) || param == "" {
+						return p.errorf("bad parameters of macro %s", name)
+					}
+					m.params = append(m.params, param)
+				}
+			}
+			body = body[end+1:]
+		}
+		m.body = strings.TrimSpace(body)
+		p.macros[name] = m
+	case "undef":
+		delete(p.macros, rest)
+	default:
+		return p.errorf("unknown directive #%s", d)
+	}
+	return nil
+}
+
+// expand replaces macros in s. Macros named in hide are being expanded and
+// are left alone.
+func (p *plan9) expand(s string, hide map[string]bool) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(s) && s[j] != c {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return "", fmt.Errorf("unterminated literal %s", s[i:])
+			}
+			b.WriteString(s[i : j+1])
+			i = j + 1
+			continue
+		case c == '.' || '0' <= c && c <= '9':
+			// Numbers and suffixes like .Z are not names.
+			j := i + 1
+			for j < len(s) && isIdent(s[j]) {
+				j++
+			}
+			b.WriteString(s[i:j])
+			i = j
+			continue
+		case !isIdent(c):
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		n := ident(s[i:])
+		name := s[i : i+n]
+		i += n
+		m := p.macros[name]
+		if m == nil || hide[name] {
+			b.WriteString(name)
+			continue
+		}
+		body := m.body
+		if m.params != nil {
+			j := i
+			for j < len(s) && (s[j] == ' ' || s[j] == '\t') {
+				j++
+			}
+			if j >= len(s) || s[j] != '(' {
+				b.WriteString(name)
+				continue
+			}
+			end := closing(s, j)
+			if end < 0 {
+				return "", fmt.Errorf("missing ) in use of macro %s", name)
+			}
+			args := split(s[j+1:end], ',')
+			if len(args) == 1 && args[0] == "" {
+				args = nil
+			}
+			if len(args) != len(m.params) {
+				return "", fmt.Errorf("macro %s takes %d arguments, not %d", name, len(m.params), len(args))
+			}
+			body = substitute(body, m.params, args)
+			i = end + 1
+		}
+		h := map[string]bool{name: true}
+		for k := range hide {
+			h[k] = true
+		}
+		x, err := p.expand(body, h)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(x)
+	}
+	return b.String(), nil
+}
+
+// closing returns the index of the parenthesis closing the one at s[i], or
+// -1 if there is none.
+func closing(s string, i int) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// substitute replaces the parameters of a macro in its body.
+func substitute(body string, params, args []string) string {
+	var b strings.Builder
+	for i := 0; i < len(body); {
+		n := ident(body[i:])
+		if n == 0 {
+			b.WriteByte(body[i])
+			i++
+			continue
+		}
+		w := body[i : i+n]
+		for k, param := range params {
+			if w == param {
+				w = args[k]
+				break
+			}
+		}
+		b.WriteString(w)
+		i += n
+	}
+	return b.String()
+}
+
+// statement handles labels and an instruction or directive.
+func (p *plan9) statement(s string) error {
+	for {
+		n := ident(s)
+		if n == 0 || n >= len(s) || s[n] != ':' {
+			break
+		}
+		p.pending = append(p.pending, s[:n])
+		s = strings.TrimSpace(s[n+1:])
+	}
+	if s == "" {
+		return nil
+	}
+	op, rest := s, ""
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		op, rest = s[:i], strings.TrimSpace(s[i:])
+	}
+	var args []string
+	if rest != "" {
+		args = split(rest, ',')
+	}
+	switch op {
+	case "TEXT":
+		return p.textDirective(args)
+	case "DATA":
+		return p.dataDirective(args)
+	case "GLOBL":
+		return p.globlDirective(args)
+	}
+	if p.text == nil {
+		return p.errorf("%s outside of TEXT", op)
+	}
+	p.text.stmts = append(p.text.stmts, stmt{line: p.line, labels: p.pending, op: op, args: args})
+	p.pending = nil
+	return nil
+}
+
+// textDirective begins a function: TEXT sym(SB), [flags,] $frame[-args].
+func (p *plan9) textDirective(args []string) error {
+	if err := p.flush(); err != nil {
+		return err
+	}
+	if len(args) != 2 && len(args) != 3 {
+		return p.errorf("TEXT requires a symbol, optional flags, and a frame size")
+	}
+	sym, off, err := p.symbol(args[0])
+	if err != nil {
+		return err
+	}
+	if off != 0 {
+		return p.errorf("TEXT symbol %s has an offset", sym)
+	}
+	var flags int64
+	if len(args) == 3 {
+		if flags, err = eval(args[1]); err != nil {
+			return p.errorf("%v", err)
+		}
+	}
+	size := args[len(args)-1]
+	if !strings.HasPrefix(size, "$") {
+		return p.errorf("bad frame size %s", size)
+	}
+	size = size[1:]
+	// The argument size follows a - which is not a sign.
+	for i := 1; i < len(size); i++ {
+		if size[i] == '-' && !strings.ContainsAny(size[:i], "(") {
+			size = size[:i]
+			break
+		}
+	}
+	frame, err := eval(size)
+	if err != nil {
+		return p.errorf("bad frame size: %v", err)
+	}
+	if err := p.define(sym); err != nil {
+		return err
+	}
+	t := &text{sym: sym, line: p.line}
+	if frame > 0 {
+		t.frame = frame
+		if flags&flagNOFRAME == 0 {
+			t.bp = 8
+		}
+	}
+	p.text = t
+	return nil
+}
+
+// define records the definition of a symbol.
+func (p *plan9) define(sym string) error {
+	if p.syms == nil {
+		p.syms = make(map[string]int)
+	}
+	if l, ok := p.syms[sym]; ok {
+		return p.errorf("%s redefined; previous definition at line %d", sym, l)
+	}
+	if _, ok := p.a.labels[sym]; ok {
+		return p.errorf("%s is already defined", sym)
+	}
+	p.syms[sym] = p.line
+	return nil
+}
+
+// symbol parses a reference to a symbol, sym+off(SB), and returns the
+// symbol's name and the offset.
+func (p *plan9) symbol(s string) (string, int64, error) {
+	if !strings.HasSuffix(s, "(SB)") {
+		return "", 0, p.errorf("%s is not a symbol", s)
+	}
+	sym, off, err := p.named(strings.TrimSuffix(s, "(SB)"))
+	if err != nil {
+		return "", 0, err
+	}
+	if sym == "" {
+		return "", 0, p.errorf("%s has no symbol name", s)
+	}
+	return symName(sym), off, nil
+}
+
+// named splits name+off into its name, which may be empty, and offset.
+func (p *plan9) named(s string) (string, int64, error) {
+	n := ident(s)
+	name, rest := s[:n], s[n:]
+	if strings.HasPrefix(rest, "<ABI") {
+		if i := strings.IndexByte(rest, '>'); i >= 0 {
+			rest = rest[i+1:]
+		}
+	}
+	if strings.HasPrefix(rest, "<>") {
+		name, rest = name+"<>", rest[2:]
+	}
+	if rest == "" {
+		return name, 0, nil
+	}
+	if name != "" && rest[0] != '+' && rest[0] != '-' {
+		return "", 0, p.errorf("bad offset in %s", s)
+	}
+	off, err := eval(rest)
+	if err != nil {
+		return "", 0, p.errorf("%v", err)
+	}
+	return name, off, nil
+}
+
+// symName converts a symbol in Go assembly to a label.
+func symName(s string) string {
+	s = strings.TrimSuffix(s, "<>")
+	s = strings.ReplaceAll(s, "·", ".")
+	s = strings.ReplaceAll(s, "∕", "/")
+	return strings.TrimPrefix(s, ".")
+}
+
+// dataDirective fills part of a symbol: DATA sym+off(SB)/width, $value.
+func (p *plan9) dataDirective(args []string) error {
+	if len(args) != 2 {
+		return p.errorf("DATA requires a location and a value")
+	}
+	i := strings.LastIndexByte(args[0], '/')
+	if i < 0 {
+		return p.errorf("DATA %s has no width", args[0])
+	}
+	sym, off, err := p.symbol(strings.TrimSpace(args[0][:i]))
+	if err != nil {
+		return err
+	}
+	width, err := eval(args[0][i+1:])
+	if err != nil {
+		return p.errorf("bad width: %v", err)
+	}
+	v, err := p.value(args[1], width)
+	if err != nil {
+		return err
+	}
+	g := p.global(sym)
+	if g.size >= 0 && off+width > g.size {
+		return p.errorf("DATA for %s is outside its %d bytes", sym, g.size)
+	}
+	if off < 0 || off+width > 1<<30 {
+		return p.errorf("bad offset %d in DATA", off)
+	}
+	if n := int(off + width); n > len(g.data) {
+		g.data = append(g.data, make([]byte, n-len(g.data))...)
+	}
+	copy(g.data[off:], v)
+	return nil
+}
+
+// value encodes the value of DATA: an integer, floating-point number, or
+// string.
+func (p *plan9) value(s string, width int64) ([]byte, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, p.errorf("DATA value %s is not an immediate", s)
+	}
+	s = strings.TrimSpace(s[1:])
+	b := make([]byte, width)
+	if strings.HasPrefix(s, `"`) {
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, p.errorf("bad string %s", s)
+		}
+		if int64(len(v)) > width {
+			return nil, p.errorf("string %s is longer than %d bytes", s, width)
+		}
+		copy(b, v)
+		return b, nil
+	}
+	if strings.HasSuffix(s, "(SB)") {
+		return nil, p.errorf("addresses of symbols are not supported")
+	}
+	v, err := eval(s)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(strings.Trim(s, "()"), 64)
+		if ferr != nil {
+			return nil, p.errorf("%v", err)
+		}
+		switch width {
+		case 4:
+			v = int64(math.Float32bits(float32(f)))
+		case 8:
+			v = int64(math.Float64bits(f))
+		default:
+			return nil, p.errorf("floating-point DATA must be 4 or 8 bytes")
+		}
+	}
+	switch width {
+	case 1:
+		b[0] = byte(v)
+	case 2:
+		binary.LittleEndian.PutUint16(b, uint16(v))
+	case 4:
+		binary.LittleEndian.PutUint32(b, uint32(v))
+	case 8:
+		binary.LittleEndian.PutUint64(b, uint64(v))
+	default:
+		return nil, p.errorf("bad DATA width %d", width)
+	}
+	return b, nil
+}
+
+// globlDirective declares a symbol: GLOBL sym(SB), [flags,] $size.
+func (p *plan9) globlDirective(args []string) error {
+	if len(args) != 2 && len(args) != 3 {
+		return p.errorf("GLOBL requires a symbol, optional flags, and a size")
+	}
+	sym, off, err := p.symbol(args[0])
+	if err != nil {
+		return err
+	}
+	if off != 0 {
+		return p.errorf("GLOBL symbol %s has an offset", sym)
+	}
+	var flags int64
+	if len(args) == 3 {
+		if flags, err = eval(args[1]); err != nil {
+			return p.errorf("%v", err)
+		}
+	}
+	if flags&flagRODATA == 0 {
+		return p.errorf("%s must be RODATA", sym)
+	}
+	size := args[len(args)-1]
+	if !strings.HasPrefix(size, "$") {
+		return p.errorf("bad size %s", size)
+	}
+	n, err := eval(size[1:])
+	if err != nil {
+		return p.errorf("bad size: %v", err)
+	}
+	if n < 0 || n > 1<<30 {
+		return p.errorf("bad size %d", n)
+	}
+	if err := p.define(sym); err != nil {
+		return err
+	}
+	g := p.global(sym)
+	if int64(len(g.data)) > n {
+		return p.errorf("DATA for %s is outside its %d bytes", sym, n)
+	}
+	g.size, g.flags, g.line = n, flags, p.line
+	return nil
+}
+
+// global returns the data of a symbol.
+func (p *plan9) global(sym string) *global {
+	g := p.globals[sym]
+	if g == nil {
+		g = &global{sym: sym, line: p.line, size: -1}
+		p.globals[sym] = g
+		p.order = append(p.order, g)
+	}
+	return g
+}
+
+// constant returns the label of read-only data holding a floating-point
+// constant, as a float32 if single is set and otherwise as a float64.
+func (p *plan9) constant(x float64, single bool) string {
+	var sym string
+	var b []byte
+	if single {
+		bits := math.Float32bits(float32(x))
+		sym, b = fmt.Sprintf("$f32.%08x", bits), binary.LittleEndian.AppendUint32(nil, bits)
+	} else {
+		bits := math.Float64bits(x)
+		sym, b = fmt.Sprintf("$f64.%016x", bits), binary.LittleEndian.AppendUint64(nil, bits)
+	}
+	g := p.global(sym)
+	g.data, g.size, g.flags = b, int64(len(b)), flagRODATA
+	return sym
+}
+
+// finish assembles the last function and the data.
+func (p *plan9) finish() error {
+	if err := p.flush(); err != nil {
+		return err
+	}
+	if len(p.pending) > 0 {
+		return p.errorf("label %s outside of TEXT", p.pending[0])
+	}
+	for _, g := range p.order {
+		if g.size < 0 {
+			p.line = g.line
+			return p.errorf("DATA for %s without GLOBL", g.sym)
+		}
+		p.a.AlignTrap(dataAlign(g.size))
+		p.a.Label(g.sym)
+		p.a.Raw(append(g.data, make([]byte, g.size-int64(len(g.data)))...))
+	}
+	return nil
+}
+
+// dataAlign returns the alignment which the go tool's linker gives data of a
+// size on amd64, the largest power of two no greater than the size, up to 32.
+func dataAlign(size int64) int {
+	n := 32
+	for n > 1 && int64(n) > size {
+		n >>= 1
+	}
+	return n
+}
+
+// flush assembles the current function.
+func (p *plan9) flush() error {
+	t := p.text
+	if t == nil {
+		return nil
+	}
+	p.text = nil
+	t.end, p.pending = p.pending, nil
+	defer func(line int) { p.line = line }(p.line)
+	labels := make(map[string]bool)
+	for i := range t.stmts {
+		for _, l := range t.stmts[i].labels {
+			if labels[l] {
+				p.line = t.stmts[i].line
+				return p.errorf("label %s redefined", l)
+			}
+			labels[l] = true
+		}
+	}
+	for _, l := range t.end {
+		if labels[l] {
+			return p.errorf("label %s redefined", l)
+		}
+		labels[l] = true
+	}
+	// Branches can target instructions by their distance, n(PC).
+	targets := make(map[int]bool)
+	for i, st := range t.stmts {
+		if n, ok := pcTarget(st.args); ok {
+			if i+n < 0 || i+n > len(t.stmts) {
+				p.line = st.line
+				return p.errorf("branch to %d(PC) is outside %s", n, t.sym)
+			}
+			targets[i+n] = true
+		}
+	}
+	f := fn{p: p, t: t, labels: labels, sp: t.frame + t.bp}
+	p.line = t.line
+	p.a.Label(t.sym)
+	if f.sp > 0 {
+		if err := f.inst("SUB", x86enc.RSP, x86enc.Imm(f.sp)); err != nil {
+			return err
+		}
+		if t.bp != 0 {
+			bp := x86enc.Mem{Base: x86enc.RSP, Disp: int32(f.sp - 8)}
+			if err := f.inst("MOV", bp, x86enc.RBP); err != nil {
+				return err
+			}
+			if err := f.inst("LEA", x86enc.RBP, bp); err != nil {
+				return err
+			}
+		}
+	}
+	for i, st := range t.stmts {
+		p.line = st.line
+		for _, l := range st.labels {
+			p.a.Label(t.sym + ":" + l)
+		}
+		if targets[i] {
+			p.a.Label(t.sym + ":" + strconv.Itoa(i))
+		}
+		if err := f.stmt(i, st); err != nil {
+			return err
+		}
+	}
+	if len(f.prefixes) > 0 {
+		return p.errorf("%v precedes no instruction", f.prefixes[len(f.prefixes)-1])
+	}
+	for _, l := range t.end {
+		p.a.Label(t.sym + ":" + l)
+	}
+	if targets[len(t.stmts)] {
+		p.a.Label(t.sym + ":" + strconv.Itoa(len(t.stmts)))
+	}
+	return nil
+}
+
+// pcTarget returns n if the last of args is n(PC).
+func pcTarget(args []string) (int, bool) {
+	if len(args) == 0 || !strings.HasSuffix(args[len(args)-1], "(PC)") {
+		return 0, false
+	}
+	n, err := eval(strings.TrimSuffix(args[len(args)-1], "(PC)"))
+	if err != nil || n != int64(int(n)) {
+		return 0, false
+	}
+	return int(n), true
+}
@@ -0,0 +1,310 @@
+package asm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// assemblePlan9 assembles Go assembly and returns the code and labels.
+func assemblePlan9(t *testing.T, src string) ([]byte, map[string]int) {
+	t.Helper()
+	a := Assembler{Encoder: anyCPU}
+	if err := a.Plan9("test.s", []byte(src)); err != nil {
+		t.Fatal(err)
+	}
+	code, labels, err := a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code, labels
+}
+
+// TestPlan9Inst tests the translation of single instructions.
+func TestPlan9Inst(t *testing.T) {
+	cases := []struct {
+		src  string
+		want string
+	}{
+		{"MOVQ AX, BX", "mov rbx, rax"},
+		{"MOVL $1, CX", "mov ecx, 0x1"},
+		{"MOVW 8(SI), DX", "mov dx, word ptr [rsi+0x8]"},
+		{"MOVB AL, (DI)(CX*1)", "mov byte ptr [rdi+rcx], al"},
+		{"MOVB $-1, R8", "mov r8b, 0xff"},
+		{"MOVQ $-1, 16(AX)", "mov qword ptr [rax+0x10], -0x1"},
+		{"MOVQ $0x123456789, R9", "mov r9, 0x123456789"},
+		{"MOVQ X1, AX", "movq rax, xmm1"},
+		{"MOVQ AX, X1", "movq xmm1, rax"},
+		{"MOVL X1, AX", "movd eax, xmm1"},
+		{"MOVQ (AX), X2", "movq xmm2, qword ptr [rax]"},
+		{"MOVBLZX (AX), CX", "movzx ecx, byte ptr [rax]"},
+		{"MOVWQSX AX, CX", "movsx rcx, ax"},
+		{"MOVLQSX (R12)(R13*4), AX", "movsxd rax, dword ptr [r12+4*r13]"},
+		{"MOVLQZX AX, AX", "mov eax, eax"},
+		{"ADDQ $8, SP", "add rsp, 0x8"},
+		{"SUBL BX, (AX)", "sub dword ptr [rax], ebx"},
+		{"LEAQ 8(AX)(BX*8), CX", "lea rcx, ptr [rax+8*rbx+0x8]"},
+		{"CMPQ AX, $10", "cmp rax, 0xa"},
+		{"CMPB (SI), $'a'", "cmp byte ptr [rsi], 0x61"},
+		{"TESTL AX, AX", "test eax, eax"},
+		{"SHLQ $3, AX", "shl rax, 0x3"},
+		{"SARL CX, DX", "sar edx, cl"},
+		{"SHRQ CX, AX, DX", "shrd rdx, rax, cl"},
+		{"IMULQ BX, AX", "imul rax, rbx"},
+		{"IMUL3Q $24, BX, AX", "imul rax, rbx, 0x18"},
+		{"CQO", "cqo"},
+		{"PUSHQ BP", "push rbp"},
+		{"PUSHFQ", "pushfq"},
+		{"SETEQ AL", "setz al"},
+		{"SETGT AX", "setnle al"},
+		{"CMOVQHI CX, DX", "cmovnbe rdx, rcx"},
+		{"XCHGL AX, (BX)", "xchg dword ptr [rbx], eax"},
+		{"POPCNTQ AX, BX", "popcnt rbx, rax"},
+		{"BSWAPL AX", "bswap eax"},
+		{"CRC32B (SI), AX", "crc32 eax, byte ptr [rsi]"},
+		{"CRC32Q AX, BX", "crc32 rbx, rax"},
+		{"MOVOU (SI), X0", "movdqu xmm0, xmmword ptr [rsi]"},
+		{"MOVOA X0, X1", "movdqa xmm1, xmm0"},
+		{"PXOR X1, X2", "pxor xmm2, xmm1"},
+		{"PSHUFL $0x1b, X1, X0", "pshufd xmm0, xmm1, 0x1b"},
+		{"PSRLO $8, X3", "psrldq xmm3, 0x8"},
+		{"PEXTRQ $1, X0, AX", "pextrq rax, xmm0, 0x1"},
+		{"PINSRD $2, CX, X1", "pinsrd xmm1, ecx, 0x2"},
+		{"PMOVMSKB X0, AX", "pmovmskb eax, xmm0"},
+		{"MOVSD X1, X0", "movsd xmm0, xmm1"},
+		{"CMPSD X1, X0, 1", "cmpsd_xmm xmm0, xmm1, 0x1"},
+		{"PSLLL $3, X1", "pslld xmm1, 0x3"},
+		{"PUNPCKLLQ X1, X0", "punpckldq xmm0, xmm1"},
+		{"ADDSD 8(SP), X0", "addsd xmm0, qword ptr [rsp+0x8]"},
+		{"CVTSQ2SD AX, X0", "cvtsi2sd xmm0, rax"},
+		{"CVTTSD2SL X0, AX", "cvttsd2si eax, xmm0"},
+		{"VPXOR Y1, Y2, Y3", "vpxor ymm3, ymm2, ymm1"},
+		{"VMOVDQU (AX), Y0", "vmovdqu ymm0, ymmword ptr [rax]"},
+		{"VADDPD Z1, Z2, K1, Z3", "vaddpd zmm3 {k1}, zmm2, zmm1"},
+		{"VADDPD.Z Z1, Z2, K1, Z3", "vaddpd zmm3 {k1} {z}, zmm2, zmm1"},
+		{"VADDPD.BCST (AX), Z2, Z3", "vaddpd zmm3, zmm2, qword ptr [rax]{1to8}"},
+		{"VADDPD.RZ_SAE Z1, Z2, Z3", "vaddpd zmm3, zmm2, zmm1, {rz-sae}"},
+		{"VPCMPEQD Z1, Z2, K1", "vpcmpeqd k1, zmm2, zmm1"},
+		{"MOVSQ", "movsq qword ptr [rdi], qword ptr [rsi]"},
+		{"STOSL", "stosd dword ptr [rdi]"},
+		{"LOCK\nXADDL AX, (BX)", "lock xadd dword ptr [rbx], eax"},
+		{"LOCK\nCMPXCHGQ CX, 8(DI)", "lock cmpxchg qword ptr [rdi+0x8], rcx"},
+		{"REP\nMOVSB", "rep movsb byte ptr [rdi], byte ptr [rsi]"},
+		{"REPN\nSCASB", "repne scasb byte ptr [rdi]"},
+		{"MOVQ -8(FS), AX", "mov rax, qword ptr fs:[0xfffffff8]"},
+		{"MOVL 0x30(GS)(CX*4), AX", "mov eax, dword ptr gs:[4*rcx+0x30]"},
+		{"CALL AX", "call rax"},
+		{"JMP (AX)(BX*8)", "jmp qword ptr [rax+8*rbx]"},
+		{"SYSCALL", "syscall"},
+		{"BYTE $0x90", "nop"},
+		{"LONG $0x90909090", "nop\nnop\nnop\nnop"},
+	}
+	for _, c := range cases {
+		t.Run(c.src, func(t *testing.T) {
+			code, labels := assemblePlan9(t, "TEXT f(SB), 4, $0\n"+c.src+"\n")
+			if labels["f"] != 0 {
+				t.Errorf("f is at %#x", labels["f"])
+			}
+			checkCode(t, code, 0, strings.Split(c.want, "\n")...)
+		})
+	}
+}
+
+// TestPlan9 tests functions, labels, frames, and data.
+func TestPlan9(t *testing.T) {
+	src := `#include "textflag.h"
+#include "funcdata.h"
+
+#define SUM(r) ADDQ (SI), r; \
+	ADDQ $8, SI
+#define N CX /* the count */
+
+// func sum(p *int, n int) int
+TEXT ·sum(SB), NOSPLIT, $0-24
+	MOVQ p+0(FP), SI
+	MOVQ n+8(FP), N
+	XORL AX, AX
+	JMP test
+loop:
+	SUM(AX)
+	DECQ N
+test:
+	TESTQ N, N
+	JNE loop
+	MOVQ AX, ret+16(FP)
+	RET
+
+// func frame(x int) int
+TEXT pkg·frame(SB), $16-16
+	NO_LOCAL_POINTERS
+	MOVQ x+0(FP), AX
+	MOVQ AX, y-8(SP)
+	PUSHQ AX
+	MOVQ x+0(FP), AX
+	POPQ AX
+	JEQ 2(PC)
+	LEAQ consts<>+8(SB), AX
+	RET
+
+DATA consts<>+0(SB)/8, $0x0102030405060708
+DATA consts<>+8(SB)/4, $"abc"
+DATA consts<>+12(SB)/4, $(1.5)
+GLOBL consts<>(SB), (NOPTR+RODATA), $24
+`
+	code, labels := assemblePlan9(t, src)
+	checkCode(t, code[:labels["pkg.frame"]], 0,
+		"mov rsi, qword ptr [rsp+0x8]",
+		"mov rcx, qword ptr [rsp+0x10]",
+		"xor eax, eax",
+		"jmp 0x1018",
+		"add rax, qword ptr [rsi]",
+		"add rsi, 0x8",
+		"dec rcx",
+		"test rcx, rcx",
+		"jnz 0x100e",
+		"mov qword ptr [rsp+0x18], rax",
+		"ret",
+	)
+	frame, consts := labels["pkg.frame"], labels["consts"]
+	// The data is aligned to 16 bytes after 11 bytes of INT3.
+	if consts%16 != 0 || !bytes.Equal(code[consts-11:consts], bytes.Repeat([]byte{0xcc}, 11)) {
+		t.Errorf("consts at %#x after %x", consts, code[frame:consts])
+	}
+	checkCode(t, code[frame:consts-11], frame,
+		"sub rsp, 0x18",
+		"mov qword ptr [rsp+0x10], rbp",
+		"lea rbp, ptr [rsp+0x10]",
+		"mov rax, qword ptr [rsp+0x20]",
+		"mov qword ptr [rsp+0x8], rax",
+		"push rax",
+		"mov rax, qword ptr [rsp+0x28]",
+		"pop rax",
+		"jz 0x104b",
+		"lea rax, ptr [rip+0x1d]",
+		"mov rbp, qword ptr [rsp+0x10]",
+		"add rsp, 0x18",
+		"ret",
+	)
+	if labels["sum"] != 0 || labels["sum:loop"] != 0xe || labels["sum:test"] != 0x18 {
+		t.Errorf("wrong labels %v", labels)
+	}
+	want := []byte{8, 7, 6, 5, 4, 3, 2, 1, 'a', 'b', 'c', 0, 0, 0, 0xc0, 0x3f, 0, 0, 0, 0, 0, 0, 0, 0}
+	if got := code[labels["consts"]:]; !bytes.Equal(got, want) {
+		t.Errorf("wrong data %x, want %x", got, want)
+	}
+}
+
+// TestPlan9PCALIGN tests padding within a function.
+func TestPlan9PCALIGN(t *testing.T) {
+	src := `TEXT f(SB), $0
+	XORL AX, AX
+	PCALIGN $16
+loop:
+	INCL AX
+	JMP loop
+`
+	code, labels := assemblePlan9(t, src)
+	checkCode(t, code, 0,
+		"xor eax, eax",
+		"nop word ptr [rax+rax], ax",
+		"nop dword ptr [rax+rax], eax",
+		"inc eax",
+		"jmp 0x1010",
+	)
+	if labels["f:loop"] != 16 {
+		t.Errorf("loop is at %d", labels["f:loop"])
+	}
+}
+
+// TestPlan9Float tests floating-point constants, which are loaded from data
+// shared among instructions.
+func TestPlan9Float(t *testing.T) {
+	src := `TEXT f(SB), $0
+	MOVSD $(-1.0), X2
+	ADDSS $1.5, X1
+	MOVSD $-1.0, X3
+	RET
+`
+	code, labels := assemblePlan9(t, src)
+	checkCode(t, code[:25], 0,
+		"movsd xmm2, qword ptr [rip+0x18]",
+		"addss xmm1, dword ptr [rip+0x18]",
+		"movsd xmm3, qword ptr [rip+0x8]",
+		"ret",
+	)
+	// Each constant is aligned to its size.
+	want := []byte{0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0, 0, 0, 0, 0, 0, 0xf0, 0xbf, 0, 0, 0xc0, 0x3f}
+	if got := code[25:]; !bytes.Equal(got, want) {
+		t.Errorf("wrong data %x, want %x", got, want)
+	}
+	if labels["$f64.bff0000000000000"] != 32 || labels["$f32.3fc00000"] != 40 {
+		t.Errorf("wrong labels %v", labels)
+	}
+}
+
+// TestPlan9Preprocessor tests conditionals and macros.
+func TestPlan9Preprocessor(t *testing.T) {
+	src := `#define ONE 1
+#define TWICE(x) ((x)*2)
+#ifdef ONE
+#ifndef GOARCH_amd64
+	bad
+#else
+#define R AX
+#endif
+#else
+	bad
+#endif
+#undef ONE
+#ifdef ONE
+	bad
+#endif
+TEXT f(SB), $0 /* comment
+	bad
+*/
+	MOVL $TWICE(3+4), R; RET // "
+`
+	code, _ := assemblePlan9(t, src)
+	checkCode(t, code, 0, "mov eax, 0xe", "ret")
+}
+
+func TestPlan9Errors(t *testing.T) {
+	cases := []struct {
+		src  string
+		want string
+	}{
+		{"TEXT f(SB), $0\n\tJMP nowhere\n", "test.s:2: undefined label nowhere"},
+		{"TEXT f(SB), $0\nx:\nx:\n\tRET\n", "test.s:4: label x redefined"},
+		{"TEXT f(SB), $0\n\tFROB AX\n", "test.s:2: unknown instruction FROB"},
+		{"TEXT f(SB), $0\n\tADDQ AX\n", "test.s:2: ADDQ: x86enc: no form"},
+		{"TEXT f(SB), $0\n\tMOVQ $g(SB), AX\n", "addresses of symbols are not supported"},
+		{"TEXT f(SB), $0\n\tMOVQ 8(FP), AX\n", "requires a name"},
+		{"TEXT f(SB), NOSPLIT, $0\n", "test.s:1: undefined NOSPLIT"},
+		{"\tRET\n", "test.s:1: RET outside of TEXT"},
+		{"TEXT f(SB), $0\nTEXT f(SB), $0\n", "test.s:2: f redefined"},
+		{"#include \"go_asm.h\"\n", "cannot include go_asm.h"},
+		{"#ifdef X\n", "missing #endif"},
+		{"DATA d+0(SB)/8, $1\n", "test.s:1: DATA for d without GLOBL"},
+		{"GLOBL d(SB), 16, $8\n", "d must be RODATA"},
+		{"DATA d+8(SB)/8, $1\nGLOBL d(SB), 8, $8\n", "test.s:2: DATA for d is outside its 8 bytes"},
+		{"TEXT f(SB), $0\n\tVADDPD.Z Z1, Z2, Z3\n", "requires a mask"},
+		{"TEXT f(SB), $0\n\tJMP 5(PC)\n", "outside f"},
+		{"TEXT f(SB), $0\n\tLOCK\n\tADDQ AX, BX\n", "test.s:3: ADDQ: x86enc: LOCK"},
+		{"TEXT f(SB), $0\n\tREP\n\tBYTE $0x90\n", "test.s:3: REP precedes BYTE"},
+		{"TEXT f(SB), $0\n\tLOCK\n", "LOCK precedes no instruction"},
+		{"TEXT f(SB), $0\n\tPCALIGN $4\n", "test.s:2: PCALIGN $4 must be a power of two from 8 to 2048"},
+		{"TEXT f(SB), $0\n\tREP\n\tPCALIGN $16\n", "REP precedes PCALIGN"},
+	}
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			a := Assembler{Encoder: anyCPU}
+			err := a.Plan9("test.s", []byte(c.src))
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("wrong error %v, want %q", err, c.want)
+			}
+			if _, _, err := a.Encode(); err == nil {
+				t.Errorf("Encode succeeded after error")
+			}
+		})
+	}
+}
@@ -0,0 +1,613 @@
+package asm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zephyrtronium/ikitai/internal/x86enc"
+)
+
+// fn is the state of assembling a function's instructions.
+type fn struct {
+	p      *plan9
+	t      *text
+	labels map[string]bool
+	sp     int64 // distance from SP to the return address
+	// prefixes are the LOCK, REP, and REPN statements before the next
+	// instruction.
+	prefixes []x86enc.Operand
+}
+
+// inst adds an instruction which refers to no labels.
+func (f *fn) inst(op string, args ...x86enc.Operand) error {
+	if err := f.p.a.inst(op, args); err != nil {
+		return f.p.errorf("%v", err)
+	}
+	return nil
+}
+
+// stmt assembles the ith statement of the function.
+func (f *fn) stmt(i int, st stmt) error {
+	op := st.op
+	var suffixes []string
+	if k := strings.IndexByte(op, '.'); k >= 0 {
+		op, suffixes = op[:k], strings.Split(op[k+1:], ".")
+	}
+	prefixes := f.prefixes
+	f.prefixes = nil
+	if len(prefixes) > 0 {
+		switch op {
+		case "PCDATA", "FUNCDATA", "NOP", "BYTE", "WORD", "LONG", "QUAD", "ADJSP", "PCALIGN":
+			return f.p.errorf("%v precedes %s", prefixes[len(prefixes)-1], op)
+		}
+	}
+	switch op {
+	case "PCDATA", "FUNCDATA", "NOP":
+		return nil
+	case "BYTE", "WORD", "LONG", "QUAD":
+		if len(st.args) != 1 || !strings.HasPrefix(st.args[0], "$") {
+			return f.p.errorf("%s requires one immediate", op)
+		}
+		v, err := eval(st.args[0][1:])
+		if err != nil {
+			return f.p.errorf("%v", err)
+		}
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(v))
+		f.p.a.Raw(b[:map[string]int{"BYTE": 1, "WORD": 2, "LONG": 4, "QUAD": 8}[op]])
+		return nil
+	case "PCALIGN":
+		if len(st.args) != 1 || !strings.HasPrefix(st.args[0], "$") {
+			return f.p.errorf("PCALIGN requires one immediate")
+		}
+		v, err := eval(st.args[0][1:])
+		if err != nil {
+			return f.p.errorf("%v", err)
+		}
+		// The go tool allows the same alignments.
+		if v < 8 || v > 2048 || v&(v-1) != 0 {
+			return f.p.errorf("PCALIGN $%d must be a power of two from 8 to 2048", v)
+		}
+		f.p.a.Align(int(v))
+		return nil
+	case "LOCK", "REP", "REPN":
+		if len(st.args) != 0 {
+			return f.p.errorf("%s takes no operands", op)
+		}
+		f.prefixes = append(prefixes, p9prefixes[op])
+		return nil
+	case "ADJSP":
+		if len(st.args) != 1 || !strings.HasPrefix(st.args[0], "$") {
+			return f.p.errorf("ADJSP requires one immediate")
+		}
+		v, err := eval(st.args[0][1:])
+		if err != nil {
+			return f.p.errorf("%v", err)
+		}
+		f.sp += v
+		return f.inst("SUB", x86enc.RSP, x86enc.Imm(v))
+	case "RET":
+		if len(st.args) == 0 && f.sp > 0 {
+			if f.t.bp != 0 {
+				sp := f.t.frame + f.t.bp
+				if err := f.inst("MOV", x86enc.RBP, x86enc.Mem{Base: x86enc.RSP, Disp: int32(sp - 8)}); err != nil {
+					return err
+				}
+			}
+			if err := f.inst("ADD", x86enc.RSP, x86enc.Imm(f.t.frame+f.t.bp)); err != nil {
+				return err
+			}
+		}
+	}
+	args := make([]p9arg, len(st.args))
+	for k, s := range st.args {
+		var err error
+		if args[k], err = f.operand(s, i, op); err != nil {
+			return err
+		}
+	}
+	if err := f.emit(op, suffixes, prefixes, args); err != nil {
+		return err
+	}
+	switch op {
+	case "PUSHQ", "PUSHFQ":
+		f.sp += 8
+	case "POPQ", "POPFQ":
+		f.sp -= 8
+	case "PUSHW", "PUSHFW":
+		f.sp += 2
+	case "POPW", "POPFW":
+		f.sp -= 2
+	}
+	return nil
+}
+
+// p9arg is an operand in Go assembly.
+type p9arg struct {
+	// reg is a register, or nil. If gpr is set, reg is a general-purpose
+	// register whose width depends on the instruction.
+	reg x86enc.Reg
+	gpr bool
+	imm *int64
+	mem *x86enc.Mem
+	// label is the label a branch target or RIP-relative operand refers to.
+	label string
+	src   string
+}
+
+// p9regs are the registers of Go assembly, excluding the general-purpose
+// registers named by their 64-bit forms.
+var p9regs = func() map[string]x86enc.Reg {
+	m := map[string]x86enc.Reg{
+		"AL": x86enc.AL, "CL": x86enc.CL, "DL": x86enc.DL, "BL": x86enc.BL,
+		"SPB": x86enc.SPL, "BPB": x86enc.BPL, "SIB": x86enc.SIL, "DIB": x86enc.DIL,
+		"AH": x86enc.AH, "CH": x86enc.CH, "DH": x86enc.DH, "BH": x86enc.GPR8(19),
+		"ES": x86enc.ES, "CS": x86enc.CS, "SS": x86enc.SS, "DS": x86enc.DS, "FS": x86enc.FS, "GS": x86enc.GS,
+	}
+	for i := 0; i < 32; i++ {
+		m[fmt.Sprintf("X%d", i)] = x86enc.XMM(i)
+		m[fmt.Sprintf("Y%d", i)] = x86enc.YMM(i)
+		m[fmt.Sprintf("Z%d", i)] = x86enc.ZMM(i)
+	}
+	for i := 0; i < 8; i++ {
+		m[fmt.Sprintf("R%dB", i+8)] = x86enc.GPR8(i + 8)
+		m[fmt.Sprintf("K%d", i)] = x86enc.K(i)
+		m[fmt.Sprintf("M%d", i)] = x86enc.MMX(i)
+		m[fmt.Sprintf("F%d", i)] = x86enc.ST(i)
+		m[fmt.Sprintf("DR%d", i)] = x86enc.DR(i)
+	}
+	for i := 0; i < 16; i++ {
+		m[fmt.Sprintf("CR%d", i)] = x86enc.CR(i)
+	}
+	return m
+}()
+
+// p9prefixes are the prefixes which Go assembly writes as instructions.
+var p9prefixes = map[string]x86enc.Operand{
+	"LOCK": x86enc.LOCK, "REP": x86enc.REP, "REPN": x86enc.REPN,
+}
+
+// p9gprs are the general-purpose registers.
+var p9gprs = map[string]x86enc.GPR64{
+	"AX": x86enc.RAX, "CX": x86enc.RCX, "DX": x86enc.RDX, "BX": x86enc.RBX,
+	"SP": x86enc.RSP, "BP": x86enc.RBP, "SI": x86enc.RSI, "DI": x86enc.RDI,
+	"R8": x86enc.R8, "R9": x86enc.R9, "R10": x86enc.R10, "R11": x86enc.R11,
+	"R12": x86enc.R12, "R13": x86enc.R13, "R14": x86enc.R14, "R15": x86enc.R15,
+}
+
+// operand parses an operand of the ith statement, whose mnemonic is op.
+func (f *fn) operand(s string, i int, op string) (p9arg, error) {
+	a := p9arg{src: s}
+	if r, ok := p9gprs[s]; ok {
+		a.reg, a.gpr = r, true
+		return a, nil
+	}
+	if r, ok := p9regs[s]; ok {
+		a.reg = r
+		return a, nil
+	}
+	if strings.HasPrefix(s, "$") {
+		if strings.HasSuffix(s, "(SB)") {
+			return a, f.p.errorf("addresses of symbols are not supported")
+		}
+		v, err := eval(s[1:])
+		if err == nil {
+			a.imm = &v
+			return a, nil
+		}
+		// Floating-point constants are loaded from memory.
+		x, ferr := strconv.ParseFloat(strings.Trim(s[1:], "()"), 64)
+		if ferr != nil {
+			return a, f.p.errorf("%v", err)
+		}
+		a.label = f.p.constant(x, strings.HasSuffix(op, "SS") || strings.HasSuffix(op, "PS"))
+		a.mem = &x86enc.Mem{Base: x86enc.RIP}
+		return a, nil
+	}
+	if v, err := eval(s); err == nil {
+		// Bare constants like the predicate of CMPSD are immediates.
+		a.imm = &v
+		return a, nil
+	}
+	if n := ident(s); n == len(s) && n > 0 {
+		if !f.labels[s] {
+			return a, f.p.errorf("undefined label %s", s)
+		}
+		a.label = f.t.sym + ":" + s
+		return a, nil
+	}
+	// Collect the base and index in parentheses at the end.
+	rest := s
+	var groups []string
+	for len(groups) < 2 && strings.HasSuffix(rest, ")") {
+		k := strings.LastIndexByte(rest, '(')
+		if k < 0 || !isRegGroup(rest[k+1:len(rest)-1]) {
+			break
+		}
+		groups = append([]string{rest[k+1 : len(rest)-1]}, groups...)
+		rest = strings.TrimSpace(rest[:k])
+	}
+	if len(groups) == 0 {
+		return a, f.p.errorf("bad operand %s", s)
+	}
+	m := &x86enc.Mem{}
+	switch groups[0] {
+	case "PC":
+		if len(groups) > 1 {
+			return a, f.p.errorf("bad operand %s", s)
+		}
+		n, _ := pcTarget([]string{s})
+		a.label = fmt.Sprintf("%s:%d", f.t.sym, i+n)
+		return a, nil
+	case "SB":
+		if len(groups) > 1 {
+			return a, f.p.errorf("%s cannot be indexed", s)
+		}
+		sym, off, err := f.p.symbol(s)
+		if err != nil {
+			return a, err
+		}
+		if off != int64(int32(off)) {
+			return a, f.p.errorf("offset of %s is out of range", s)
+		}
+		a.label = sym
+		m.Base, m.Disp = x86enc.RIP, int32(off)
+		a.mem = m
+		return a, nil
+	}
+	name, off, err := f.p.named(rest)
+	if err != nil {
+		return a, err
+	}
+	switch {
+	case groups[0] == "FP":
+		if name == "" {
+			return a, f.p.errorf("%s requires a name", s)
+		}
+		off += f.sp + 8
+		m.Base = x86enc.RSP
+	case groups[0] == "SP" && name != "":
+		// The pseudo-register SP is the top of the locals.
+		off += f.sp - f.t.bp
+		m.Base = x86enc.RSP
+	case name != "":
+		return a, f.p.errorf("bad operand %s", s)
+	case groups[0] == "FS" || groups[0] == "GS":
+		// Thread-local storage, like -8(FS).
+		m.Seg = p9regs[groups[0]]
+	case strings.Contains(groups[0], "*"):
+		// Only an index.
+		groups = append([]string{""}, groups...)
+	default:
+		m.Base = p9gprs[groups[0]]
+	}
+	if len(groups) > 1 {
+		k := strings.IndexByte(groups[1], '*')
+		if k < 0 {
+			return a, f.p.errorf("index of %s has no scale", s)
+		}
+		r, scale := groups[1][:k], groups[1][k+1:]
+		if x, ok := p9gprs[r]; ok {
+			m.Index = x
+		} else if x, ok := p9regs[r]; ok && x.Class() != x86enc.ClassGPR {
+			m.Index = x
+		} else {
+			return a, f.p.errorf("bad index in %s", s)
+		}
+		switch scale {
+		case "1", "2", "4", "8":
+			m.Scale = scale[0] - '0'
+		default:
+			return a, f.p.errorf("bad scale in %s", s)
+		}
+	}
+	if off != int64(int32(off)) {
+		return a, f.p.errorf("offset of %s is out of range", s)
+	}
+	m.Disp = int32(off)
+	a.mem = m
+	return a, nil
+}
+
+// isRegGroup returns whether s is a register or pseudo-register, optionally
+// scaled, as appears in parentheses in a memory operand.
+func isRegGroup(s string) bool {
+	if k := strings.IndexByte(s, '*'); k >= 0 {
+		s = s[:k]
+	}
+	if _, ok := p9gprs[s]; ok {
+		return true
+	}
+	switch s {
+	case "SB", "FP", "PC":
+		return true
+	}
+	r, ok := p9regs[s]
+	return ok && r.Class() != x86enc.ClassGPR
+}
+
+// p9form is a translation of a Go mnemonic to an Intel one.
+type p9form struct {
+	op string
+	// size is the width of general-purpose registers and memory, or 0 if
+	// the mnemonic does not say.
+	size int
+	// sizes, if set, are the widths of the operands in Go order.
+	sizes []int
+	// order, if set, gives the Go operand for each Intel operand, for
+	// mnemonics like CMP whose operands Go does not write in reverse.
+	order []int
+}
+
+// p9conds are the condition codes of Go's Jcc, SETcc, and CMOVcc.
+var p9conds = map[string]string{
+	"EQ": "E", "NE": "NE", "LT": "L", "LE": "LE", "GT": "G", "GE": "GE",
+	"LS": "BE", "HI": "A", "CS": "B", "LO": "B", "CC": "AE", "HS": "AE",
+	"MI": "S", "PL": "NS", "OS": "O", "OC": "NO", "PS": "P", "PC": "NP",
+	"Z": "E", "NZ": "NE",
+}
+
+// p9special are mnemonics which do not follow Go's usual conventions.
+var p9special = map[string][]p9form{
+	"MOVL":      {{op: "MOV", size: 4}, {op: "MOVD", size: 4}},
+	"MOVQ":      {{op: "MOV", size: 8}, {op: "MOVQ", size: 8}},
+	"MOVBLSX":   {{op: "MOVSX", sizes: []int{1, 4}}},
+	"MOVBLZX":   {{op: "MOVZX", sizes: []int{1, 4}}},
+	"MOVBQSX":   {{op: "MOVSX", sizes: []int{1, 8}}},
+	"MOVBQZX":   {{op: "MOVZX", sizes: []int{1, 8}}},
+	"MOVBWSX":   {{op: "MOVSX", sizes: []int{1, 2}}},
+	"MOVBWZX":   {{op: "MOVZX", sizes: []int{1, 2}}},
+	"MOVWLSX":   {{op: "MOVSX", sizes: []int{2, 4}}},
+	"MOVWLZX":   {{op: "MOVZX", sizes: []int{2, 4}}},
+	"MOVWQSX":   {{op: "MOVSX", sizes: []int{2, 8}}},
+	"MOVWQZX":   {{op: "MOVZX", sizes: []int{2, 8}}},
+	"MOVLQSX":   {{op: "MOVSXD", sizes: []int{4, 8}}},
+	"MOVLQZX":   {{op: "MOV", sizes: []int{4, 4}}},
+	"MOVOU":     {{op: "MOVDQU"}},
+	"MOVOA":     {{op: "MOVDQA"}},
+	"MOVO":      {{op: "MOVDQA"}},
+	"MOVNTO":    {{op: "MOVNTDQ"}},
+	"MOVQOZX":   {{op: "MOVQ"}},
+	"PADDL":     {{op: "PADDD"}},
+	"PSUBL":     {{op: "PSUBD"}},
+	"PSLLL":     {{op: "PSLLD"}},
+	"PSRLL":     {{op: "PSRLD"}},
+	"PSRAL":     {{op: "PSRAD"}},
+	"PCMPEQL":   {{op: "PCMPEQD"}},
+	"PCMPGTL":   {{op: "PCMPGTD"}},
+	"PMULULQ":   {{op: "PMULUDQ"}},
+	"PMADDWL":   {{op: "PMADDWD"}},
+	"PACKSSLW":  {{op: "PACKSSDW"}},
+	"PUNPCKLWL": {{op: "PUNPCKLWD"}},
+	"PUNPCKHWL": {{op: "PUNPCKHWD"}},
+	"PUNPCKLLQ": {{op: "PUNPCKLDQ"}},
+	"PUNPCKHLQ": {{op: "PUNPCKHDQ"}},
+	"PSHUFL":    {{op: "PSHUFD"}},
+	"PSLLO":     {{op: "PSLLDQ"}},
+	"PSRLO":     {{op: "PSRLDQ"}},
+	"CVTSL2SD":  {{op: "CVTSI2SD", sizes: []int{4, 0}}},
+	"CVTSQ2SD":  {{op: "CVTSI2SD", sizes: []int{8, 0}}},
+	"CVTSL2SS":  {{op: "CVTSI2SS", sizes: []int{4, 0}}},
+	"CVTSQ2SS":  {{op: "CVTSI2SS", sizes: []int{8, 0}}},
+	"CVTSD2SL":  {{op: "CVTSD2SI", sizes: []int{0, 4}}},
+	"CVTSD2SQ":  {{op: "CVTSD2SI", sizes: []int{0, 8}}},
+	"CVTSS2SL":  {{op: "CVTSS2SI", sizes: []int{0, 4}}},
+	"CVTSS2SQ":  {{op: "CVTSS2SI", sizes: []int{0, 8}}},
+	"CVTTSD2SL": {{op: "CVTTSD2SI", sizes: []int{0, 4}}},
+	"CVTTSD2SQ": {{op: "CVTTSD2SI", sizes: []int{0, 8}}},
+	"CVTTSS2SL": {{op: "CVTTSS2SI", sizes: []int{0, 4}}},
+	"CVTTSS2SQ": {{op: "CVTTSS2SI", sizes: []int{0, 8}}},
+	"CRC32B":    {{op: "CRC32", sizes: []int{1, 4}}},
+	"CRC32W":    {{op: "CRC32", sizes: []int{2, 4}}},
+	"CRC32L":    {{op: "CRC32", sizes: []int{4, 4}}},
+	"CRC32Q":    {{op: "CRC32", sizes: []int{8, 8}}},
+	"IMUL3W":    {{op: "IMUL", size: 2}},
+	"IMUL3L":    {{op: "IMUL", size: 4}},
+	"IMUL3Q":    {{op: "IMUL", size: 8}},
+	"MOVSD":     {{op: "MOVSD_XMM"}},
+	"CMPSD":     {{op: "CMPSD_XMM", order: []int{1, 0, 2}}},
+	"CMPSS":     {{op: "CMPSS", order: []int{1, 0, 2}}},
+	"CMPPD":     {{op: "CMPPD", order: []int{1, 0, 2}}},
+	"CMPPS":     {{op: "CMPPS", order: []int{1, 0, 2}}},
+	"UNDEF":     {{op: "UD2"}},
+	"MOVSL":     {{op: "MOVSD"}},
+	"STOSL":     {{op: "STOSD"}},
+	"LODSL":     {{op: "LODSD"}},
+	"SCASL":     {{op: "SCASD"}},
+	"CMPSL":     {{op: "CMPSD"}},
+	"JCXZL":     {{op: "JECXZ"}},
+	"JCXZQ":     {{op: "JRCXZ"}},
+}
+
+// p9sizes are the widths named by Go's suffixes.
+var p9sizes = map[byte]int{'B': 1, 'W': 2, 'L': 4, 'Q': 8}
+
+// forms returns the Intel forms which a Go mnemonic may mean, in order of
+// preference.
+func forms(op string, n int) []p9form {
+	if f, ok := p9special[op]; ok {
+		return f
+	}
+	for _, p := range []string{"J", "SET"} {
+		if c, ok := p9conds[strings.TrimPrefix(op, p)]; ok && strings.HasPrefix(op, p) {
+			return []p9form{{op: p + c}}
+		}
+	}
+	if len(op) > 5 && strings.HasPrefix(op, "CMOV") {
+		if c, ok := p9conds[op[5:]]; ok && p9sizes[op[4]] > 1 {
+			return []p9form{{op: "CMOV" + c, size: p9sizes[op[4]]}}
+		}
+	}
+	var fs []p9form
+	if n == 0 && x86enc.Known(op) {
+		// Suffixes like that of PUSHFQ only name the instruction.
+		return append(fs, p9form{op: op})
+	}
+	if size := p9sizes[op[len(op)-1]]; size != 0 && len(op) > 1 {
+		base := op[:len(op)-1]
+		f := p9form{op: base, size: size}
+		if base == "CMP" {
+			f.order = []int{0, 1}
+		}
+		switch base {
+		case "SHL", "SHR", "SAL", "SAR", "ROL", "ROR", "RCL", "RCR":
+			// A count in a register is CL.
+			switch n {
+			case 2:
+				f.sizes = []int{1, size}
+			case 3:
+				if base == "SHL" || base == "SHR" {
+					f.op, f.sizes = base+"D", []int{1, size, size}
+				}
+			}
+		}
+		if x86enc.Known(f.op) {
+			fs = append(fs, f)
+		}
+	}
+	if x86enc.Known(op) {
+		fs = append(fs, p9form{op: op})
+	}
+	return fs
+}
+
+// emit encodes an instruction and adds it to the program.
+func (f *fn) emit(op string, suffixes []string, prefixes []x86enc.Operand, args []p9arg) error {
+	fs := forms(op, len(args))
+	if len(fs) == 0 {
+		return f.p.errorf("unknown instruction %s", op)
+	}
+	// AVX-512 instructions write a mask before the destination.
+	var mask *p9arg
+	if n := len(args); n >= 3 && strings.HasPrefix(op, "V") {
+		if _, ok := args[n-2].reg.(x86enc.K); ok {
+			mask = &args[n-2]
+			args = append(args[:n-2:n-2], args[n-1])
+		}
+	}
+	var zero, bcst bool
+	var rc x86enc.Rounding
+	for _, s := range suffixes {
+		switch s {
+		case "Z":
+			zero = true
+		case "BCST":
+			bcst = true
+		case "SAE":
+			rc = x86enc.SAE
+		case "RN_SAE":
+			rc = x86enc.RoundNearest
+		case "RD_SAE":
+			rc = x86enc.RoundDown
+		case "RU_SAE":
+			rc = x86enc.RoundUp
+		case "RZ_SAE":
+			rc = x86enc.RoundZero
+		default:
+			return f.p.errorf("unknown suffix .%s", s)
+		}
+	}
+	if zero && mask == nil {
+		return f.p.errorf("%s.Z requires a mask", op)
+	}
+	gprs := false
+	for _, a := range args {
+		gprs = gprs || a.gpr
+	}
+	var first error
+	for _, form := range fs {
+		sizes := []int{form.size}
+		if form.size == 0 && form.sizes == nil && gprs {
+			// Without a suffix, the instruction's operands determine the
+			// width of the registers.
+			sizes = []int{8, 4, 2, 1}
+		}
+		for _, size := range sizes {
+			ops, label := form.operands(args, size)
+			if bcst {
+				for i, o := range ops {
+					if m, ok := o.(x86enc.Mem); ok {
+						m.Broadcast = true
+						ops[i] = m
+					}
+				}
+			}
+			if mask != nil {
+				ops[0] = x86enc.Masked{Op: ops[0], Mask: mask.reg.(x86enc.K), Zero: zero}
+			}
+			if rc != 0 {
+				ops = append(ops, rc)
+			}
+			ops = append(prefixes[:len(prefixes):len(prefixes)], ops...)
+			_, err := f.p.a.encode(form.op, ops)
+			if err == nil {
+				if label != "" {
+					err = f.p.a.ref(label, form.op, ops)
+				} else {
+					err = f.p.a.inst(form.op, ops)
+				}
+				if err != nil {
+					return f.p.errorf("%v", err)
+				}
+				return nil
+			}
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return f.p.errorf("%s: %v", op, first)
+}
+
+// operands converts operands in Go order to Intel order for a form, with
+// size as the width of general-purpose registers and memory not otherwise
+// given. It also returns the label the operands refer to, if any.
+func (form *p9form) operands(args []p9arg, size int) ([]x86enc.Operand, string) {
+	ops := make([]x86enc.Operand, len(args))
+	label := ""
+	for i, a := range args {
+		sz := size
+		if form.sizes != nil && i < len(form.sizes) {
+			sz = form.sizes[i]
+		}
+		var o x86enc.Operand
+		switch {
+		case a.gpr:
+			r := a.reg.Num()
+			switch sz {
+			case 1:
+				o = x86enc.GPR8(r)
+			case 2:
+				o = x86enc.GPR16(r)
+			case 4:
+				o = x86enc.GPR32(r)
+			default:
+				o = x86enc.GPR64(r)
+			}
+		case a.reg != nil:
+			o = a.reg
+		case a.imm != nil:
+			o = x86enc.Imm(*a.imm)
+		case a.mem != nil:
+			m := *a.mem
+			m.Size = sz
+			o = m
+		default:
+			o = x86enc.Rel(0)
+		}
+		if a.label != "" {
+			label = a.label
+		}
+		ops[len(args)-1-i] = o
+	}
+	if form.order != nil && len(form.order) == len(ops) {
+		r := make([]x86enc.Operand, len(ops))
+		for i, k := range form.order {
+			r[i] = ops[len(ops)-1-k]
+		}
+		ops = r
+	}
+	return ops, label
+}
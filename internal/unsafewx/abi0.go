@@ -0,0 +1,124 @@
+package unsafewx
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// FuncABI0 is like Func, but the code at addr follows Go's stack-based ABI0,
+// as functions written in Go assembly do: it finds its arguments at 0(FP),
+// that is, just above its return address, and stores its results after them.
+// The returned function copies its arguments into such a frame and calls the
+// code through a trampoline which restores the registers the internal ABI
+// reserves once the code returns.
+//
+// Like functions written in Go assembly with the NOSPLIT flag, the code does
+// not check for stack overflow. The returned function guarantees it 16 KB of
+// stack, including the frame, which is limited to 4 KB. Panics under the same
+// conditions as Func, if the frame is too large, or if ABI0 functions are not
+// supported on the current architecture, which is the case except on amd64.
+func (b *Block) FuncABI0(addr uintptr, typ reflect.Type) interface{} {
+	fn := b.entry(addr)
+	if abi0Code == nil {
+		panic("wx: ABI0 functions are not supported on this architecture")
+	}
+	in, out, size := abi0Frame(typ)
+	if size > abi0MaxFrame {
+		panic(fmt.Errorf("wx: ABI0 frame of %v is %d bytes, more than the limit of %d", typ, size, abi0MaxFrame))
+	}
+	call := abi0Trampoline()
+	return reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		// The frame is not scanned by the garbage collector. Pointers in the
+		// arguments are kept alive by args.
+		frame := make([]uintptr, max(size/ptrSize, 1))
+		base := unsafe.Pointer(&frame[0])
+		for i, v := range args {
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			n := v.Type().Size()
+			copy(unsafe.Slice((*byte)(unsafe.Add(base, in[i])), n), unsafe.Slice((*byte)(p.UnsafePointer()), n))
+		}
+		growStack()
+		call(fn, uintptr(base), size)
+		runtime.KeepAlive(args)
+		r := make([]reflect.Value, len(out))
+		for i, off := range out {
+			t := typ.Out(i)
+			r[i] = reflect.New(t).Elem()
+			r[i].Set(reflect.NewAt(t, unsafe.Add(base, off)).Elem())
+		}
+		runtime.KeepAlive(frame)
+		return r
+	}).Interface()
+}
+
+const (
+	ptrSize = unsafe.Sizeof(uintptr(0))
+
+	// abi0MaxFrame is the largest ABI0 frame FuncABI0 accepts.
+	abi0MaxFrame = 4 << 10
+	// abi0Stack is the stack space reserved for ABI0 functions.
+	abi0Stack = 16 << 10
+)
+
+// abi0Frame returns the offsets of the arguments and results of a function of
+// type typ in its ABI0 frame and the size of the frame. Each value is aligned
+// to its type, and the results begin and the frame ends at a multiple of the
+// pointer size.
+func abi0Frame(typ reflect.Type) (in, out []uintptr, size uintptr) {
+	in = make([]uintptr, typ.NumIn())
+	for i := range in {
+		t := typ.In(i)
+		size = alignUp(size, uintptr(t.Align()))
+		in[i] = size
+		size += t.Size()
+	}
+	size = alignUp(size, ptrSize)
+	out = make([]uintptr, typ.NumOut())
+	for i := range out {
+		t := typ.Out(i)
+		size = alignUp(size, uintptr(t.Align()))
+		out[i] = size
+		size += t.Size()
+	}
+	return in, out, alignUp(size, ptrSize)
+}
+
+func alignUp(n, a uintptr) uintptr {
+	return (n + a - 1) &^ (a - 1)
+}
+
+// growStack ensures that at least abi0Stack bytes of stack are available to
+// its caller's callees.
+//
+//go:noinline
+func growStack() {
+	var room [abi0Stack]byte
+	touch(room[:])
+}
+
+//go:noinline
+func touch(p []byte) {}
+
+var (
+	abi0Once sync.Once
+	abi0Call func(fn, frame, size uintptr)
+)
+
+// abi0Trampoline returns the trampoline which calls fn with a copy of the size
+// bytes at frame on the stack and copies the frame back once fn returns. The
+// trampoline lives in its own block, which is never closed.
+func abi0Trampoline() func(fn, frame, size uintptr) {
+	abi0Once.Do(func() {
+		b := MustAlloc(len(abi0Code))
+		b.Write(abi0Code)
+		if err := b.Exec(); err != nil {
+			panic(err)
+		}
+		abi0Call = b.Func(0, reflect.TypeOf(abi0Call)).(func(fn, frame, size uintptr))
+	})
+	return abi0Call
+}
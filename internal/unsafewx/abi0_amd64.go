@@ -0,0 +1,29 @@
+package unsafewx
+
+// abi0Code is the trampoline for ABI0 functions. The internal ABI passes fn,
+// frame, and size in AX, BX, and CX. ABI0 code may clobber every register, so
+// the trampoline keeps what it needs in its own frame and afterward restores
+// R14, which holds the current goroutine, and zeroes X15, as the internal ABI
+// requires.
+var abi0Code = []byte{
+	0x55,             // PUSHQ BP
+	0x48, 0x89, 0xe5, // MOVQ SP, BP
+	0x41, 0x56, // PUSHQ R14
+	0x53,             // PUSHQ BX
+	0x51,             // PUSHQ CX
+	0x48, 0x29, 0xcc, // SUBQ CX, SP
+	0x48, 0x83, 0xe4, 0xf0, // ANDQ $~15, SP
+	0x48, 0x89, 0xe7, // MOVQ SP, DI
+	0x48, 0x89, 0xde, // MOVQ BX, SI
+	0xf3, 0xa4, // REP; MOVSB
+	0xff, 0xd0, // CALL AX
+	0x48, 0x89, 0xe6, // MOVQ SP, SI
+	0x48, 0x8b, 0x7d, 0xf0, // MOVQ -16(BP), DI
+	0x48, 0x8b, 0x4d, 0xe8, // MOVQ -24(BP), CX
+	0xf3, 0xa4, // REP; MOVSB
+	0x4c, 0x8b, 0x75, 0xf8, // MOVQ -8(BP), R14
+	0x45, 0x0f, 0x57, 0xff, // XORPS X15, X15
+	0x48, 0x89, 0xec, // MOVQ BP, SP
+	0x5d, // POPQ BP
+	0xc3, // RET
+}
@@ -0,0 +1,75 @@
+package unsafewx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestFuncABI0 tests calling code which takes its arguments and returns its
+// results on the stack.
+func TestFuncABI0(t *testing.T) {
+	code := []byte{
+		// func(a int8, b int64, c int32) (int64, bool) {
+		// 	s := int64(a) + b + int64(c)
+		// 	return s, s > 0
+		// }
+		// ABI0 places a, b, and c at 8(SP), 16(SP), and 24(SP) and the
+		// results at 32(SP) and 40(SP). The code also clobbers R14 and X15,
+		// which the internal ABI reserves.
+		0x48, 0x0f, 0xbe, 0x44, 0x24, 0x08, // MOVBQSX 8(SP), AX
+		0x48, 0x03, 0x44, 0x24, 0x10, // ADDQ 16(SP), AX
+		0x48, 0x63, 0x4c, 0x24, 0x18, // MOVLQSX 24(SP), CX
+		0x48, 0x01, 0xc8, // ADDQ CX, AX
+		0x48, 0x89, 0x44, 0x24, 0x20, // MOVQ AX, 32(SP)
+		0x48, 0x85, 0xc0, // TESTQ AX, AX
+		0x0f, 0x9f, 0x44, 0x24, 0x28, // SETGT 40(SP)
+		0x45, 0x31, 0xf6, // XORL R14, R14
+		0x66, 0x45, 0x0f, 0x74, 0xff, // PCMPEQB X15, X15
+		0xc3, // RET
+	}
+	b := MustAlloc(len(code))
+	defer b.Close()
+	b.Write(code)
+	if err := b.Exec(); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	var f func(int8, int64, int32) (int64, bool)
+	f = b.FuncABI0(0, reflect.TypeOf(f)).(func(int8, int64, int32) (int64, bool))
+	cases := []struct {
+		a   int8
+		b   int64
+		c   int32
+		s   int64
+		pos bool
+	}{
+		{-3, 10, 4, 11, true},
+		{1, -10, 2, -7, false},
+		{-128, 1 << 40, -1, 1<<40 - 129, true},
+	}
+	for _, c := range cases {
+		s, pos := f(c.a, c.b, c.c)
+		if s != c.s || pos != c.pos {
+			t.Errorf("wrong result for (%d, %d, %d): wanted %d, %t; have %d, %t", c.a, c.b, c.c, c.s, c.pos, s, pos)
+		}
+	}
+}
+
+// TestFuncABI0TooLarge tests that FuncABI0 rejects frames over the limit.
+func TestFuncABI0TooLarge(t *testing.T) {
+	b := MustAlloc(1)
+	defer b.Close()
+	b.Write([]byte{0xc3})
+	if err := b.Exec(); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	defer func() {
+		r := recover()
+		err, _ := r.(error)
+		if err == nil || !strings.Contains(err.Error(), "more than the limit") {
+			t.Errorf("wrong panic: %v", r)
+		}
+	}()
+	var f func([abi0MaxFrame + 1]byte)
+	b.FuncABI0(0, reflect.TypeOf(f))
+}
@@ -0,0 +1,6 @@
+// +build !amd64
+
+package unsafewx
+
+// abi0Code is nil to indicate that ABI0 functions are unsupported.
+var abi0Code []byte
@@ -0,0 +1,36 @@
+package unsafewx
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// TestABI0Frame tests the layout of arguments and results in ABI0 frames.
+func TestABI0Frame(t *testing.T) {
+	p := ptrSize
+	q := unsafe.Alignof(int64(0)) // 4 on 386
+	r := alignUp(q+12, p)
+	cases := []struct {
+		name    string
+		f       interface{}
+		in, out []uintptr
+		size    uintptr
+	}{
+		{"empty", func() {}, []uintptr{}, []uintptr{}, 0},
+		{"words", func(int, int) int { return 0 }, []uintptr{0, p}, []uintptr{2 * p}, 3 * p},
+		{"packed", func(int8, int8, int16, int32) {}, []uintptr{0, 1, 2, 4}, []uintptr{}, 8},
+		{"aligned", func(int8, int64, int32) (int64, bool) { return 0, false }, []uintptr{0, q, q + 8}, []uintptr{r, r + 8}, alignUp(r+9, p)},
+		{"results", func(bool) (bool, string) { return false, "" }, []uintptr{0}, []uintptr{p, 2 * p}, 4 * p},
+		{"string", func(string, byte) {}, []uintptr{0, 2 * p}, []uintptr{}, 3 * p},
+		{"float", func(float32, float64) float32 { return 0 }, []uintptr{0, q}, []uintptr{q + 8}, alignUp(q+12, p)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			in, out, size := abi0Frame(reflect.TypeOf(c.f))
+			if !reflect.DeepEqual(in, c.in) || !reflect.DeepEqual(out, c.out) || size != c.size {
+				t.Errorf("wrong frame: wanted %v %v %d, have %v %v %d", c.in, c.out, c.size, in, out, size)
+			}
+		})
+	}
+}
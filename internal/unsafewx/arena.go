@@ -0,0 +1,393 @@
+package unsafewx
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// A CodeArena manages code for many functions spread across blocks, reclaiming
+// memory from functions that are no longer used. It is intended for programs
+// that repeatedly load new versions of functions, where old versions would
+// otherwise accumulate without bound.
+//
+// Code is added to writeable blocks until Exec makes them executable. Because
+// executable memory never becomes writeable again, space freed in executable
+// blocks cannot be reused; instead, if the backend is a Decommitter, the
+// memory of pages in them which hold no code is returned to the system, and a
+// block is unmapped once all code in it has been freed. Space freed in blocks that are still writeable is returned
+// to a free list and reused by later additions.
+//
+// A CodeArena is safe for concurrent use by multiple goroutines.
+type CodeArena struct {
+	mu     sync.Mutex
+	be     Backend
+	size   int
+	page   uintptr // pages to decommit, or 0 if the backend cannot
+	chunks []*chunk
+	// released is the total number of bytes unmapped or decommitted over the
+	// arena's life.
+	released int
+}
+
+// chunk is a block in a CodeArena along with its bookkeeping.
+type chunk struct {
+	b    *Block
+	free []span // unused ranges in offset order, reusable only while writeable
+	live int    // number of Codes not yet reclaimed
+	gone []bool // decommitted pages, once executable
+	// used, pending, and stranded are byte counts of code not yet freed, of
+	// code freed but still referenced, and of space left unusable by the
+	// chunk becoming executable. decommitted is the part of stranded in
+	// decommitted pages.
+	used, pending, stranded, decommitted int
+}
+
+// span is a range of offsets within a chunk.
+type span struct {
+	off, n uintptr
+}
+
+// A Code is a range of code in a CodeArena, typically a single function.
+type Code struct {
+	a      *CodeArena
+	c      *chunk
+	off, n uintptr
+	refs   int
+	freed  bool
+}
+
+// ArenaStats describes the occupancy of a CodeArena. Mapped is always equal to
+// the sum of Live, Pending, Free, and Stranded.
+type ArenaStats struct {
+	// Blocks is the number of blocks currently mapped.
+	Blocks int
+	// Mapped is the number of bytes currently mapped, excluding decommitted
+	// pages.
+	Mapped int
+	// Live is the number of bytes of code that have not been freed.
+	Live int
+	// Pending is the number of bytes of code that have been freed but are
+	// still referenced.
+	Pending int
+	// Free is the number of bytes in writeable blocks available for new code.
+	Free int
+	// Stranded is the number of bytes in executable blocks which contain no
+	// code but cannot be reused, either because the code there was reclaimed
+	// or because nothing was added there before the block became executable.
+	// If the backend is a Decommitter, whole pages of stranded space are
+	// decommitted as soon as they are stranded and are not counted; the rest
+	// is unmapped along with its block.
+	Stranded int
+	// Released is the total number of bytes unmapped or decommitted over the
+	// arena's life.
+	Released int
+}
+
+// codeAlign is the alignment of code in an arena.
+const codeAlign = 16
+
+// NewCodeArena creates an arena which allocates blocks of at least size bytes
+// from the given backend. If size is not positive, the page size is used.
+func NewCodeArena(be Backend, size int) *CodeArena {
+	if size <= 0 {
+		size = os.Getpagesize()
+	}
+	a := &CodeArena{be: be, size: size}
+	if d, ok := be.(Decommitter); ok {
+		a.page = uintptr(d.DecommitSize())
+	}
+	return a
+}
+
+// Add copies code into a writeable block in the arena. The code cannot be
+// executed until after a call to Exec.
+func (a *CodeArena) Add(code []byte) (*Code, error) {
+	if len(code) == 0 {
+		return nil, errors.New("wx: cannot add empty code")
+	}
+	n := (uintptr(len(code)) + codeAlign - 1) &^ (codeAlign - 1)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, c := range a.chunks {
+		if c.b.x {
+			continue
+		}
+		if off, ok := c.take(n); ok {
+			return a.place(c, code, off, n), nil
+		}
+	}
+	sz := a.size
+	if int(n) > sz {
+		sz = int(n)
+	}
+	b, err := AllocFrom(a.be, sz)
+	if err != nil {
+		return nil, err
+	}
+	c := &chunk{b: b, free: []span{{0, uintptr(len(b.mem))}}}
+	a.chunks = append(a.chunks, c)
+	off, _ := c.take(n)
+	return a.place(c, code, off, n), nil
+}
+
+// place writes code into c at off, returning its Code.
+func (a *CodeArena) place(c *chunk, code []byte, off, n uintptr) *Code {
+	c.b.writeAt(code, off)
+	c.live++
+	c.used += int(n)
+	return &Code{a: a, c: c, off: off, n: n}
+}
+
+// take removes n bytes from the first free span that can hold them.
+func (c *chunk) take(n uintptr) (uintptr, bool) {
+	for i, s := range c.free {
+		if s.n < n {
+			continue
+		}
+		if s.n == n {
+			c.free = append(c.free[:i], c.free[i+1:]...)
+		} else {
+			c.free[i] = span{s.off + n, s.n - n}
+		}
+		return s.off, true
+	}
+	return 0, false
+}
+
+// give returns a span to the free list, coalescing it with its neighbors.
+func (c *chunk) give(s span) {
+	i := sort.Search(len(c.free), func(i int) bool { return c.free[i].off > s.off })
+	c.free = append(c.free, span{})
+	copy(c.free[i+1:], c.free[i:])
+	c.free[i] = s
+	if i+1 < len(c.free) && c.free[i].off+c.free[i].n == c.free[i+1].off {
+		c.free[i].n += c.free[i+1].n
+		c.free = append(c.free[:i+1], c.free[i+2:]...)
+	}
+	if i > 0 && c.free[i-1].off+c.free[i-1].n == c.free[i].off {
+		c.free[i-1].n += c.free[i].n
+		c.free = append(c.free[:i], c.free[i+1:]...)
+	}
+}
+
+// Exec makes all writeable blocks in the arena executable, so that all code
+// added so far may be executed. Subsequent additions go to new blocks.
+func (a *CodeArena) Exec() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, c := range a.chunks {
+		if c.b.x {
+			continue
+		}
+		if err := c.b.Exec(); err != nil {
+			return err
+		}
+		for _, f := range c.free {
+			c.stranded += int(f.n)
+		}
+		for _, f := range c.free {
+			if err := a.decommit(c, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decommit returns the memory of the whole pages in a free span of an
+// executable chunk to the system.
+func (a *CodeArena) decommit(c *chunk, s span) error {
+	ps := a.page
+	if ps == 0 {
+		return nil
+	}
+	if c.gone == nil {
+		c.gone = make([]bool, (uintptr(len(c.b.mem))+ps-1)/ps)
+	}
+	lo := (s.off + ps - 1) / ps
+	hi := (s.off + s.n) / ps
+	for p := lo; p < hi; {
+		if c.gone[p] {
+			p++
+			continue
+		}
+		q := p
+		for q < hi && !c.gone[q] {
+			q++
+		}
+		if err := c.b.decommit(p*ps, (q-p)*ps); err != nil {
+			return err
+		}
+		for ; p < q; p++ {
+			c.gone[p] = true
+			c.decommitted += int(ps)
+			a.released += int(ps)
+		}
+	}
+	return nil
+}
+
+// Stats returns the arena's current occupancy.
+func (a *CodeArena) Stats() ArenaStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := ArenaStats{Blocks: len(a.chunks), Released: a.released}
+	for _, c := range a.chunks {
+		s.Mapped += len(c.b.mem) - c.decommitted
+		s.Live += c.used
+		s.Pending += c.pending
+		s.Stranded += c.stranded - c.decommitted
+		if c.b.x {
+			continue
+		}
+		for _, f := range c.free {
+			s.Free += int(f.n)
+		}
+	}
+	return s
+}
+
+// Close unmaps all blocks in the arena, regardless of whether their code is
+// still in use. The arena must not be used after Close.
+func (a *CodeArena) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var err error
+	l := a.chunks[:0]
+	for _, c := range a.chunks {
+		n := len(c.b.mem) - c.decommitted
+		if e := c.b.Close(); e != nil {
+			err = e
+			l = append(l, c)
+			continue
+		}
+		a.released += n
+	}
+	a.chunks = l
+	return err
+}
+
+// reclaim returns a freed, unreferenced code range to its chunk, unmapping
+// the chunk if nothing in it remains or decommitting the pages it frees if the
+// chunk is executable.
+func (a *CodeArena) reclaim(x *Code) error {
+	c := x.c
+	c.pending -= int(x.n)
+	c.live--
+	if c.live == 0 {
+		n := len(c.b.mem) - c.decommitted
+		logv("arena releasing block of", n, "bytes")
+		if err := c.b.Close(); err != nil {
+			return err
+		}
+		a.remove(c)
+		a.released += n
+		return nil
+	}
+	c.give(span{x.off, x.n})
+	if !c.b.x {
+		return nil
+	}
+	c.stranded += int(x.n)
+	for _, f := range c.free {
+		if f.off <= x.off && x.off < f.off+f.n {
+			return a.decommit(c, f)
+		}
+	}
+	return nil
+}
+
+// remove removes c from the arena's list of chunks.
+func (a *CodeArena) remove(c *chunk) {
+	for i, d := range a.chunks {
+		if d == c {
+			a.chunks = append(a.chunks[:i], a.chunks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Addr returns the absolute address of the code.
+func (x *Code) Addr() uintptr {
+	return x.c.b.Addr(x.off)
+}
+
+// Len returns the size of the code's range, which may exceed the length of
+// the code originally added due to alignment.
+func (x *Code) Len() int {
+	return int(x.n)
+}
+
+// Func returns a function of type typ which executes the code. The caller
+// should hold a reference to the code, as obtained from Ref, for as long as
+// the function may be executing. Panics under the same conditions as
+// Block.Func, or if the code has been reclaimed.
+func (x *Code) Func(typ reflect.Type) interface{} {
+	x.a.mu.Lock()
+	defer x.a.mu.Unlock()
+	if x.freed && x.refs == 0 {
+		panic("wx: use of reclaimed code")
+	}
+	return x.c.b.Func(x.off, typ)
+}
+
+// FuncABI0 is like Func, but the code follows ABI0, as described for
+// Block.FuncABI0.
+func (x *Code) FuncABI0(typ reflect.Type) interface{} {
+	x.a.mu.Lock()
+	defer x.a.mu.Unlock()
+	if x.freed && x.refs == 0 {
+		panic("wx: use of reclaimed code")
+	}
+	return x.c.b.FuncABI0(x.off, typ)
+}
+
+// Ref adds a reference to the code. A code range is never reused or unmapped
+// while it has references. Panics if the code has been reclaimed.
+func (x *Code) Ref() {
+	x.a.mu.Lock()
+	defer x.a.mu.Unlock()
+	if x.freed && x.refs == 0 {
+		panic("wx: use of reclaimed code")
+	}
+	x.refs++
+}
+
+// Unref removes a reference added by Ref. If the code has been freed and this
+// was its last reference, its range is reclaimed, and the error from
+// unmapping its block, if any, is returned. Panics if the code has no
+// references.
+func (x *Code) Unref() error {
+	x.a.mu.Lock()
+	defer x.a.mu.Unlock()
+	if x.refs <= 0 {
+		panic("wx: unref of unreferenced code")
+	}
+	x.refs--
+	if x.freed && x.refs == 0 {
+		return x.a.reclaim(x)
+	}
+	return nil
+}
+
+// Free marks the code as no longer needed. Its range is reclaimed as soon as
+// it has no references, possibly immediately, in which case the error from
+// unmapping its block, if any, is returned. Panics if the code was already
+// freed.
+func (x *Code) Free() error {
+	x.a.mu.Lock()
+	defer x.a.mu.Unlock()
+	if x.freed {
+		panic("wx: double free of code")
+	}
+	x.freed = true
+	x.c.used -= int(x.n)
+	x.c.pending += int(x.n)
+	if x.refs == 0 {
+		return x.a.reclaim(x)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package unsafewx
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// checkStats checks that the arena's statistics are self-consistent and agree
+// with the backend.
+func checkStats(t *testing.T, a *CodeArena, f *FakeBackend) ArenaStats {
+	t.Helper()
+	s := a.Stats()
+	if sum := s.Live + s.Pending + s.Free + s.Stranded; s.Mapped != sum {
+		t.Errorf("mapped %d bytes, but components sum to %d: %+v", s.Mapped, sum, s)
+	}
+	if s.Mapped != f.Reserved() {
+		t.Errorf("arena has %d bytes mapped, but backend has %d reserved", s.Mapped, f.Reserved())
+	}
+	return s
+}
+
+// TestArenaReload tests that repeatedly replacing functions does not grow the
+// arena without bound.
+func TestArenaReload(t *testing.T) {
+	f := FakeBackend{PageSize: 4096}
+	a := NewCodeArena(&f, 4096)
+	defer a.Close()
+	code := make([]byte, 1000)
+	var old []*Code
+	for i := 0; i < 5000; i++ {
+		// Load a new version of three functions, then free the previous.
+		var cur []*Code
+		for j := 0; j < 3; j++ {
+			x, err := a.Add(code)
+			if err != nil {
+				t.Fatalf("add failed on reload %d: %v", i, err)
+			}
+			cur = append(cur, x)
+		}
+		if err := a.Exec(); err != nil {
+			t.Fatalf("exec failed on reload %d: %v", i, err)
+		}
+		for _, x := range old {
+			if err := x.Free(); err != nil {
+				t.Fatalf("free failed on reload %d: %v", i, err)
+			}
+		}
+		old = cur
+	}
+	s := checkStats(t, a, &f)
+	if s.Blocks > 2 {
+		t.Errorf("too many blocks after reloads: %+v", s)
+	}
+	if s.Live != 3*1008 {
+		t.Errorf("wrong live size: wanted %d, have %d", 3*1008, s.Live)
+	}
+	if s.Released < 4999*4096 {
+		t.Errorf("too few bytes released: %+v", s)
+	}
+}
+
+// TestArenaReuse tests that freed ranges in writeable blocks are reused only
+// once they are unreferenced, and that adjacent free ranges coalesce.
+func TestArenaReuse(t *testing.T) {
+	f := FakeBackend{PageSize: 4096}
+	a := NewCodeArena(&f, 4096)
+	defer a.Close()
+	x, _ := a.Add(make([]byte, 32))
+	y, _ := a.Add(make([]byte, 32))
+	z, _ := a.Add(make([]byte, 32))
+	if x.Len() != 32 || y.Addr() != x.Addr()+32 || z.Addr() != y.Addr()+32 {
+		t.Fatalf("unexpected layout: %#x+%d %#x %#x", x.Addr(), x.Len(), y.Addr(), z.Addr())
+	}
+	xa, ya := x.Addr(), y.Addr()
+	x.Ref()
+	x.Free()
+	y.Free()
+	s := checkStats(t, a, &f)
+	if s.Pending != 32 || s.Live != 32 {
+		t.Errorf("wrong stats with referenced freed code: %+v", s)
+	}
+	// y is reusable, but x is still referenced.
+	w, _ := a.Add(make([]byte, 32))
+	if w.Addr() != ya {
+		t.Errorf("freed range not reused: wanted %#x, have %#x", ya, w.Addr())
+	}
+	v, _ := a.Add(make([]byte, 32))
+	if v.Addr() == xa {
+		t.Fatal("referenced range reused")
+	}
+	v.Free()
+	w.Free()
+	x.Unref()
+	// x and w now coalesce into a single 64-byte range.
+	u, _ := a.Add(make([]byte, 64))
+	if u.Addr() != xa {
+		t.Errorf("coalesced range not reused: wanted %#x, have %#x", xa, u.Addr())
+	}
+	checkStats(t, a, &f)
+}
+
+// TestArenaRelease tests that blocks are unmapped once all code in them is
+// reclaimed, and not before.
+func TestArenaRelease(t *testing.T) {
+	f := FakeBackend{PageSize: 4096}
+	a := NewCodeArena(&f, 4096)
+	defer a.Close()
+	x, _ := a.Add(make([]byte, 100))
+	y, _ := a.Add(make([]byte, 5000))
+	if err := a.Exec(); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	s := checkStats(t, a, &f)
+	if s.Blocks != 2 || s.Mapped != 4096+8192 || s.Stranded != 4096-112+8192-5008 {
+		t.Errorf("wrong stats after exec: %+v", s)
+	}
+	y.Ref()
+	if err := y.Free(); err != nil {
+		t.Errorf("free failed: %v", err)
+	}
+	if s := checkStats(t, a, &f); s.Blocks != 2 || s.Pending != 5008 {
+		t.Errorf("referenced block released: %+v", s)
+	}
+	if err := y.Unref(); err != nil {
+		t.Errorf("unref failed: %v", err)
+	}
+	if s := checkStats(t, a, &f); s.Blocks != 1 || s.Released != 8192 {
+		t.Errorf("unreferenced block not released: %+v", s)
+	}
+	if err := x.Free(); err != nil {
+		t.Errorf("free failed: %v", err)
+	}
+	if s := checkStats(t, a, &f); s.Blocks != 0 || s.Released != 4096+8192 {
+		t.Errorf("empty block not released: %+v", s)
+	}
+}
+
+// TestArenaDecommit tests that whole pages of freed space in executable blocks
+// are returned to the system while code beside them stays live.
+func TestArenaDecommit(t *testing.T) {
+	// Pages larger than the system's check that the arena decommits in the
+	// backend's pages.
+	ps := 4 * os.Getpagesize()
+	f := FakeBackend{PageSize: ps}
+	a := NewCodeArena(&f, 4*ps)
+	defer a.Close()
+	x, _ := a.Add(make([]byte, ps))
+	y, _ := a.Add(make([]byte, ps))
+	z, _ := a.Add(make([]byte, 100))
+	if err := a.Exec(); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	// The last page held nothing when the block became executable.
+	if s := checkStats(t, a, &f); s.Mapped != 3*ps || s.Released != ps || s.Stranded != ps-112 {
+		t.Errorf("wrong stats after exec: %+v", s)
+	}
+	if err := y.Free(); err != nil {
+		t.Errorf("free failed: %v", err)
+	}
+	if s := checkStats(t, a, &f); s.Blocks != 1 || s.Mapped != 2*ps || s.Live != ps+112 || s.Released != 2*ps {
+		t.Errorf("freed page not decommitted: %+v", s)
+	}
+	if p, ok := f.Prot(x.c.b.mem); !ok || p != ProtRX {
+		t.Errorf("block changed by decommit: %v %v", p, ok)
+	}
+	// Freeing a neighbor decommits only the pages not yet decommitted.
+	x.Ref()
+	if err := x.Free(); err != nil {
+		t.Errorf("free failed: %v", err)
+	}
+	if s := checkStats(t, a, &f); s.Mapped != 2*ps || s.Pending != ps {
+		t.Errorf("referenced page decommitted: %+v", s)
+	}
+	if err := x.Unref(); err != nil {
+		t.Errorf("unref failed: %v", err)
+	}
+	if s := checkStats(t, a, &f); s.Mapped != ps || s.Released != 3*ps || s.Live != 112 {
+		t.Errorf("wrong stats after freeing neighbor: %+v", s)
+	}
+	if err := z.Free(); err != nil {
+		t.Errorf("free failed: %v", err)
+	}
+	if s := checkStats(t, a, &f); s.Blocks != 0 || s.Released != 4*ps {
+		t.Errorf("empty block not released: %+v", s)
+	}
+}
+
+// TestArenaNoDecommit tests that an arena keeps freed pages of executable
+// blocks mapped if its backend is not a Decommitter.
+func TestArenaNoDecommit(t *testing.T) {
+	f := FakeBackend{PageSize: 4096}
+	a := NewCodeArena(struct{ Backend }{&f}, 3*4096)
+	defer a.Close()
+	x, _ := a.Add(make([]byte, 4096))
+	y, _ := a.Add(make([]byte, 100))
+	if err := a.Exec(); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	if err := x.Free(); err != nil {
+		t.Errorf("free failed: %v", err)
+	}
+	if s := checkStats(t, a, &f); s.Mapped != 3*4096 || s.Stranded != 2*4096+4096-112 || s.Released != 0 {
+		t.Errorf("wrong stats after free: %+v", s)
+	}
+	if err := y.Free(); err != nil {
+		t.Errorf("free failed: %v", err)
+	}
+	if s := checkStats(t, a, &f); s.Blocks != 0 || s.Released != 3*4096 {
+		t.Errorf("empty block not released: %+v", s)
+	}
+}
+
+// TestArenaExecFail tests that an arena remains usable after an injected
+// protection failure.
+func TestArenaExecFail(t *testing.T) {
+	eperm := errors.New("EPERM")
+	var fail bool
+	f := FakeBackend{PageSize: 4096, Fail: func(op string) error {
+		if fail && op == "protect" {
+			return eperm
+		}
+		return nil
+	}}
+	a := NewCodeArena(&f, 4096)
+	defer a.Close()
+	x, _ := a.Add(make([]byte, 16))
+	fail = true
+	if err := a.Exec(); err != eperm {
+		t.Errorf("wrong error: wanted %v, have %v", eperm, err)
+	}
+	fail = false
+	y, _ := a.Add(make([]byte, 16))
+	if y.Addr() != x.Addr()+16 {
+		t.Errorf("block not writeable after failed exec")
+	}
+	if err := a.Exec(); err != nil {
+		t.Errorf("exec failed: %v", err)
+	}
+	checkStats(t, a, &f)
+}
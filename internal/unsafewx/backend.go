@@ -0,0 +1,226 @@
+package unsafewx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"unsafe"
+)
+
+// A Backend provides the memory underlying blocks. Alloc uses OSBackend, but
+// AllocFrom accepts any implementation, which allows higher layers to use
+// alternative mappings or to be tested without executing anything.
+//
+// Memory returned by Reserve must remain valid and must not move until it is
+// passed to Release. The slices passed to Protect and Release are always
+// exactly those returned by Reserve.
+type Backend interface {
+	// Reserve maps at least n bytes of readable and writeable memory. The
+	// length of the returned slice is the actual amount of memory mapped,
+	// which is typically a multiple of the page size and always at least 1.
+	Reserve(n int) ([]byte, error)
+	// Protect changes the protection of memory obtained from Reserve.
+	Protect(mem []byte, p Prot) error
+	// Release unmaps memory obtained from Reserve.
+	Release(mem []byte) error
+}
+
+// A Decommitter is a Backend which can return the physical memory of pages to
+// the system without releasing their reservation. CodeArena uses it, if its
+// backend implements it, to free the pages of executable blocks which no
+// longer hold code. All backends in this package implement it.
+type Decommitter interface {
+	Backend
+	// Decommit returns the physical memory of a range of whole pages within
+	// memory obtained from Reserve to the system. The range remains reserved
+	// until Release, but its contents are lost, and it is never accessed
+	// again.
+	Decommit(mem []byte) error
+	// DecommitSize returns the size of the pages Decommit works in. The
+	// ranges passed to Decommit begin at offsets within their reservations
+	// which are multiples of it and have lengths which are multiples of it.
+	DecommitSize() int
+}
+
+// Prot is a memory protection mode.
+type Prot int
+
+const (
+	// ProtRW is readable and writeable memory.
+	ProtRW Prot = iota
+	// ProtRX is readable and executable memory.
+	ProtRX
+)
+
+func (p Prot) String() string {
+	switch p {
+	case ProtRW:
+		return "RW"
+	case ProtRX:
+		return "RX"
+	default:
+		return fmt.Sprintf("Prot(%d)", int(p))
+	}
+}
+
+// roundPage rounds n up to a positive multiple of the page size ps.
+func roundPage(n, ps int) int {
+	c := (n + ps - 1) / ps * ps
+	if c == 0 {
+		c = ps
+	}
+	return c
+}
+
+// FakeBackend is a Backend that allocates ordinary Go memory and only records
+// protection changes, so nothing it provides ever becomes executable. It is
+// intended for deterministic tests of code that manages blocks, including
+// failure injection through Fail. Functions obtained from blocks allocated
+// from a FakeBackend must not be called.
+//
+// The zero value is ready to use. A FakeBackend is safe for concurrent use,
+// but its fields must not be modified while it is in use.
+type FakeBackend struct {
+	// PageSize is the granularity of reservations. If it is not positive,
+	// os.Getpagesize is used.
+	PageSize int
+	// Fail, if not nil, is called before every operation with the name of
+	// the operation ("reserve", "protect", "release", or "decommit"). If it
+	// returns a non-nil error, the operation fails with that error and has no
+	// effect.
+	Fail func(op string) error
+
+	mu   sync.Mutex
+	live map[*byte]*reservation
+	n    int
+}
+
+// reservation is the state of memory reserved from a FakeBackend.
+type reservation struct {
+	mem  []byte
+	prot Prot
+	ps   int
+	gone []bool // decommitted pages
+}
+
+// ErrNotReserved is the error returned by FakeBackend when asked to protect,
+// release, or decommit memory it does not own.
+var ErrNotReserved = errors.New("wx: memory not reserved by this backend")
+
+func (f *FakeBackend) fail(op string) error {
+	if f.Fail == nil {
+		return nil
+	}
+	return f.Fail(op)
+}
+
+// Reserve allocates memory from the Go heap.
+func (f *FakeBackend) Reserve(n int) ([]byte, error) {
+	if err := f.fail("reserve"); err != nil {
+		return nil, err
+	}
+	ps := f.DecommitSize()
+	mem := make([]byte, roundPage(n, ps))
+	f.mu.Lock()
+	if f.live == nil {
+		f.live = make(map[*byte]*reservation)
+	}
+	f.live[&mem[0]] = &reservation{mem: mem, prot: ProtRW, ps: ps, gone: make([]bool, len(mem)/ps)}
+	f.n += len(mem)
+	f.mu.Unlock()
+	return mem, nil
+}
+
+// Protect records the new protection of mem.
+func (f *FakeBackend) Protect(mem []byte, p Prot) error {
+	if err := f.fail("protect"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.live[&mem[0]]
+	if !ok {
+		return ErrNotReserved
+	}
+	r.prot = p
+	return nil
+}
+
+// Release forgets mem.
+func (f *FakeBackend) Release(mem []byte) error {
+	if err := f.fail("release"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.live[&mem[0]]
+	if !ok {
+		return ErrNotReserved
+	}
+	delete(f.live, &mem[0])
+	for _, g := range r.gone {
+		if !g {
+			f.n -= r.ps
+		}
+	}
+	return nil
+}
+
+// DecommitSize returns PageSize, or the system's page size if PageSize is not
+// positive.
+func (f *FakeBackend) DecommitSize() int {
+	if f.PageSize <= 0 {
+		return os.Getpagesize()
+	}
+	return f.PageSize
+}
+
+// Decommit records that the pages of mem are no longer committed. mem must
+// be whole pages within a reservation.
+func (f *FakeBackend) Decommit(mem []byte) error {
+	if err := f.fail("decommit"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := uintptr(unsafe.Pointer(&mem[0]))
+	for _, r := range f.live {
+		base := uintptr(unsafe.Pointer(&r.mem[0]))
+		if p < base || p+uintptr(len(mem)) > base+uintptr(len(r.mem)) {
+			continue
+		}
+		off := int(p - base)
+		if off%r.ps != 0 || len(mem)%r.ps != 0 {
+			return fmt.Errorf("wx: decommit of %d bytes at offset %d is not whole pages", len(mem), off)
+		}
+		for i := off / r.ps; i < (off+len(mem))/r.ps; i++ {
+			if !r.gone[i] {
+				r.gone[i] = true
+				f.n -= r.ps
+			}
+		}
+		return nil
+	}
+	return ErrNotReserved
+}
+
+// Prot returns the protection most recently recorded for mem and whether mem
+// is currently reserved.
+func (f *FakeBackend) Prot(mem []byte) (Prot, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.live[&mem[0]]
+	if !ok {
+		return 0, false
+	}
+	return r.prot, true
+}
+
+// Reserved returns the total number of bytes currently reserved, excluding
+// decommitted pages.
+func (f *FakeBackend) Reserved() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.n
+}
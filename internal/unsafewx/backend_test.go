@@ -0,0 +1,100 @@
+package unsafewx
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestFakeBackend tests that blocks allocated from a FakeBackend record their
+// protections and are released on close.
+func TestFakeBackend(t *testing.T) {
+	var f FakeBackend
+	b, err := AllocFrom(&f, 1)
+	if err != nil {
+		t.Fatalf("alloc failed: %v", err)
+	}
+	mem := b.mem
+	if p, ok := f.Prot(mem); !ok || p != ProtRW {
+		t.Errorf("wrong protection after alloc: wanted RW true, have %v %v", p, ok)
+	}
+	if f.Reserved() != len(mem) {
+		t.Errorf("wrong reserved size: wanted %d, have %d", len(mem), f.Reserved())
+	}
+	if _, err := b.Write([]byte{0xc3}); err != nil {
+		t.Errorf("write failed: %v", err)
+	}
+	if err := b.Exec(); err != nil {
+		t.Errorf("exec failed: %v", err)
+	}
+	if p, ok := f.Prot(mem); !ok || p != ProtRX {
+		t.Errorf("wrong protection after exec: wanted RX true, have %v %v", p, ok)
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("close failed: %v", err)
+	}
+	if _, ok := f.Prot(mem); ok {
+		t.Error("memory still reserved after close")
+	}
+	if f.Reserved() != 0 {
+		t.Errorf("wrong reserved size after close: wanted 0, have %d", f.Reserved())
+	}
+}
+
+// TestFakeBackendFail tests that errors injected through FakeBackend.Fail
+// surface from the corresponding block operations without effect.
+func TestFakeBackendFail(t *testing.T) {
+	enomem := errors.New("ENOMEM")
+	eperm := errors.New("EPERM")
+	cases := []struct {
+		op  string
+		err error
+	}{
+		{"reserve", enomem},
+		{"protect", eperm},
+		{"release", enomem},
+	}
+	for _, c := range cases {
+		t.Run(c.op, func(t *testing.T) {
+			f := FakeBackend{Fail: func(op string) error {
+				if op == c.op {
+					return c.err
+				}
+				return nil
+			}}
+			b, err := AllocFrom(&f, 1)
+			if c.op == "reserve" {
+				if err != c.err {
+					t.Errorf("wrong alloc error: wanted %v, have %v", c.err, err)
+				}
+				if f.Reserved() != 0 {
+					t.Errorf("failed reserve left %d bytes reserved", f.Reserved())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("alloc failed: %v", err)
+			}
+			err = b.Exec()
+			if c.op == "protect" {
+				if err != c.err {
+					t.Errorf("wrong exec error: wanted %v, have %v", c.err, err)
+				}
+				if b.x {
+					t.Error("block marked executable after failed exec")
+				}
+				if _, err := b.Write([]byte{0xc3}); err != nil {
+					t.Errorf("write after failed exec failed: %v", err)
+				}
+			}
+			err = b.Close()
+			if c.op == "release" {
+				if err != c.err {
+					t.Errorf("wrong close error: wanted %v, have %v", c.err, err)
+				}
+				if !b.IsValid() {
+					t.Error("block invalid after failed close")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package unsafewx
+
+import "golang.org/x/sys/unix"
+
+// madvDiscard frees the storage of pages of a shared mapping.
+const madvDiscard = unix.MADV_REMOVE
+
+func openBacking(dir string) (int, error) {
+	if dir != "" {
+		return tempBacking(dir)
+	}
+	return unix.MemfdCreate("unsafewx", unix.MFD_CLOEXEC)
+}
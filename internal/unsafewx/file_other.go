@@ -0,0 +1,13 @@
+// +build aix darwin dragonfly freebsd netbsd openbsd solaris
+
+package unsafewx
+
+import "golang.org/x/sys/unix"
+
+// madvDiscard drops pages of a shared mapping from memory. There is no
+// portable way to free their storage in the file.
+const madvDiscard = unix.MADV_DONTNEED
+
+func openBacking(dir string) (int, error) {
+	return tempBacking(dir)
+}
@@ -0,0 +1,74 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package unsafewx
+
+import (
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// FileBackend is a Backend that maps shared memory backed by a file rather
+// than anonymous memory. On Linux, the file is created with memfd_create
+// unless Dir is set. Otherwise, it is a temporary file in Dir, or in
+// os.TempDir if Dir is empty, which is unlinked immediately after creation.
+//
+// Executing file-backed memory requires that the file system not be mounted
+// noexec.
+type FileBackend struct {
+	// Dir is the directory in which to create backing files.
+	Dir string
+}
+
+// Reserve creates a file of n bytes rounded up to a multiple of the page size
+// and maps it.
+func (f FileBackend) Reserve(n int) ([]byte, error) {
+	c := roundPage(n, unix.Getpagesize())
+	fd, err := openBacking(f.Dir)
+	if err != nil {
+		return nil, err
+	}
+	// The mapping holds its own reference to the file, so we can close our
+	// descriptor as soon as the mapping exists.
+	defer unix.Close(fd)
+	if err := unix.Ftruncate(fd, int64(c)); err != nil {
+		return nil, err
+	}
+	return unix.Mmap(fd, 0, c, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+}
+
+// Protect calls mprotect.
+func (FileBackend) Protect(mem []byte, p Prot) error {
+	return unix.Mprotect(mem, unixProt(p))
+}
+
+// Release calls munmap.
+func (FileBackend) Release(mem []byte) error {
+	return unix.Munmap(mem)
+}
+
+// Decommit calls madvise to discard the pages. On Linux, this frees their
+// storage in the file; elsewhere, the file keeps it until Release.
+func (FileBackend) Decommit(mem []byte) error {
+	return unix.Madvise(mem, madvDiscard)
+}
+
+// DecommitSize returns the system's page size.
+func (FileBackend) DecommitSize() int {
+	return unix.Getpagesize()
+}
+
+// tempBacking creates and unlinks a temporary file in dir.
+func tempBacking(dir string) (int, error) {
+	f, err := ioutil.TempFile(dir, "unsafewx")
+	if err != nil {
+		return -1, err
+	}
+	defer f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		return -1, err
+	}
+	// Duplicate the descriptor so that closing f doesn't invalidate it.
+	return unix.Dup(int(f.Fd()))
+}
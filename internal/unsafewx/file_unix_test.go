@@ -0,0 +1,33 @@
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package unsafewx
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestFileBackend tests that file-backed blocks can be written, made
+// executable, and closed.
+func TestFileBackend(t *testing.T) {
+	dirs := map[string]string{"default": "", "tempdir": t.TempDir()}
+	for name, dir := range dirs {
+		t.Run(name, func(t *testing.T) {
+			b, err := AllocFrom(FileBackend{Dir: dir}, 1)
+			if err != nil {
+				t.Fatalf("alloc failed: %v", err)
+			}
+			defer b.Close()
+			if _, err := b.Write([]byte{0xc3}); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if err := b.Exec(); err != nil {
+				t.Skipf("exec failed, file system may be noexec: %v", err)
+			}
+			var f func()
+			if b.Func(0, reflect.TypeOf(f)).(func()) == nil {
+				t.Error("creating func() gave nil function")
+			}
+		})
+	}
+}
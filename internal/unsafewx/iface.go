@@ -0,0 +1,128 @@
+package unsafewx
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Interface returns an interface value of type iface whose methods execute
+// code in the block. The offset of the code for each of the interface's
+// methods is given by name in methods. The value's dynamic type and data word
+// are taken from recv: if the dynamic type of recv is pointer-shaped, such as a
+// pointer, map, channel, or function, then the receiver passed to each method
+// is the value itself, and otherwise it is a pointer to a copy of the value.
+// The caller is responsible for ensuring that the code for each method is
+// ABI-compatible with the method's type with the receiver word prepended.
+//
+// The result is an addressable reflect.Value of kind Interface. Its methods
+// can be called through reflect, and the interface value itself can be
+// obtained through its address, e.g. for io.Reader:
+//
+//	v, err := b.Interface(reflect.TypeOf((*io.Reader)(nil)).Elem(), recv, methods)
+//	r := *v.Addr().Interface().(*io.Reader)
+//
+// Note that v.Interface() converts to interface{}, which discards the block's
+// methods; asserting the result back to iface then fails.
+//
+// Interface returns an error if iface is not an interface type, if methods
+// does not name exactly the methods of iface, or if an offset is outside the
+// block. Panics if the block is invalid or has not been marked executable, or
+// if Supported returns an error.
+func (b *Block) Interface(iface reflect.Type, recv interface{}, methods map[string]uintptr) (reflect.Value, error) {
+	if !b.IsValid() {
+		panic("wx: attempted to create interface without committed memory")
+	}
+	if !b.x {
+		panic("wx: attempted to create interface in writeable memory")
+	}
+	if supportErr != nil {
+		panic(supportErr)
+	}
+	if iface.Kind() != reflect.Interface {
+		return reflect.Value{}, fmt.Errorf("wx: %v is not an interface type", iface)
+	}
+	if recv == nil {
+		return reflect.Value{}, fmt.Errorf("wx: nil receiver for %v", iface)
+	}
+	if len(methods) != iface.NumMethod() {
+		return reflect.Value{}, fmt.Errorf("wx: %d methods given for %v, which has %d", len(methods), iface, iface.NumMethod())
+	}
+	// The runtime's itab is laid out as itab, with fun extending as far as
+	// needed for all of the interface's methods. We allocate it as a struct
+	// of that layout with a longer fun, so that the garbage collector keeps
+	// the types alive but does not scan the hash or the function pointers,
+	// which point outside the Go heap. The itab itself is kept alive by the
+	// interface value.
+	n := iface.NumMethod()
+	tabType := reflect.StructOf([]reflect.StructField{
+		{Name: "Inter", Type: unsafePointerType},
+		{Name: "Type", Type: unsafePointerType},
+		{Name: "Hash", Type: reflect.TypeOf(uint32(0))},
+		{Name: "Fun", Type: reflect.ArrayOf(max(n, 1), reflect.TypeOf(uintptr(0)))},
+	})
+	tab := (*itab)(reflect.New(tabType).UnsafePointer())
+	tab.inter = efaceOf(iface).data
+	e := efaceOf(recv)
+	tab.typ = e.typ
+	tab.hash = typeHash(e.typ)
+	fun := unsafe.Slice(&tab.fun[0], n)
+	for i := 0; i < n; i++ {
+		m := iface.Method(i)
+		off, ok := methods[m.Name]
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("wx: no code given for method %s of %v", m.Name, iface)
+		}
+		if off >= b.n {
+			return reflect.Value{}, fmt.Errorf("wx: code for method %s of %v out of bounds", m.Name, iface)
+		}
+		fun[i] = uintptr(unsafe.Pointer(&b.mem[off]))
+	}
+	v := reflect.New(iface).Elem()
+	i := (*ifaceWords)(unsafe.Pointer(v.UnsafeAddr()))
+	i.tab = unsafe.Pointer(tab)
+	i.data = e.data
+	return v, nil
+}
+
+// itab is the layout of the runtime's itab with one word of fun, which
+// continues past the end of the struct for interfaces with more methods.
+// KEEP IN SYNC WITH internal/abi.ITab:
+// https://github.com/golang/go/blob/master/src/internal/abi/iface.go
+type itab struct {
+	inter, typ unsafe.Pointer
+	hash       uint32
+	fun        [1]uintptr
+}
+
+var unsafePointerType = reflect.TypeOf(unsafe.Pointer(nil))
+
+// eface is the layout of interface{}.
+// KEEP IN SYNC WITH runtime.eface:
+// https://github.com/golang/go/blob/master/src/runtime/runtime2.go
+type eface struct {
+	typ, data unsafe.Pointer
+}
+
+// ifaceWords is the layout of non-empty interfaces.
+// KEEP IN SYNC WITH runtime.iface:
+// https://github.com/golang/go/blob/master/src/runtime/runtime2.go
+type ifaceWords struct {
+	tab, data unsafe.Pointer
+}
+
+func efaceOf(x interface{}) eface {
+	return *(*eface)(unsafe.Pointer(&x))
+}
+
+// typeHash returns the hash of a runtime type descriptor, which follows its
+// size and pointer data size.
+// KEEP IN SYNC WITH internal/abi.Type:
+// https://github.com/golang/go/blob/master/src/internal/abi/type.go
+func typeHash(t unsafe.Pointer) uint32 {
+	type rtype struct {
+		size, ptrdata uintptr
+		hash          uint32
+	}
+	return (*rtype)(t).hash
+}
@@ -0,0 +1,140 @@
+package unsafewx
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+type accumulator interface {
+	Add(int) int
+	Get() int
+}
+
+// accumulatorBlock returns a block implementing accumulator for a *int
+// receiver, with the offsets of its methods.
+func accumulatorBlock(t *testing.T) (*Block, map[string]uintptr) {
+	code := []byte{
+		// func (p *int) Add(x int) int {
+		// 	return *p + x
+		// }
+		0x48, 0x8b, 0x00, // MOVQ (AX), AX
+		0x48, 0x01, 0xd8, // ADDQ BX, AX
+		0xc3, // RET
+		// func (p *int) Get() int {
+		// 	return *p
+		// }
+		0x48, 0x8b, 0x00, // MOVQ (AX), AX
+		0xc3, // RET
+	}
+	b := MustAlloc(len(code))
+	b.Write(code)
+	if err := b.Exec(); err != nil {
+		b.Close()
+		t.Fatalf("exec failed: %v", err)
+	}
+	return b, map[string]uintptr{"Add": 0, "Get": 7}
+}
+
+// TestInterfaceCall tests calling block methods through an interface value.
+func TestInterfaceCall(t *testing.T) {
+	b, m := accumulatorBlock(t)
+	defer b.Close()
+	x := 40
+	v, err := b.Interface(reflect.TypeOf((*accumulator)(nil)).Elem(), &x, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := *v.Addr().Interface().(*accumulator)
+	if r := a.Get(); r != 40 {
+		t.Errorf("wrong result from Get: wanted 40, have %d", r)
+	}
+	x = 1
+	if r := a.Add(2); r != 3 {
+		t.Errorf("wrong result from Add: wanted 3, have %d", r)
+	}
+	// The dynamic type is still that of the receiver.
+	if p, ok := interface{}(a).(*int); !ok || p != &x {
+		t.Errorf("wrong dynamic value: wanted %p, have %v", &x, a)
+	}
+	if _, ok := interface{}(a).(io.Reader); ok {
+		t.Error("*int asserted to io.Reader")
+	}
+}
+
+// TestInterfaceReflect tests calling block methods through reflect.
+func TestInterfaceReflect(t *testing.T) {
+	b, m := accumulatorBlock(t)
+	defer b.Close()
+	x := 5
+	v, err := b.Interface(reflect.TypeOf((*accumulator)(nil)).Elem(), &x, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := v.MethodByName("Add").Call([]reflect.Value{reflect.ValueOf(6)})
+	if len(r) != 1 || r[0].Int() != 11 {
+		t.Errorf("wrong result from Add: wanted [11], have %v", r)
+	}
+	r = v.Method(1).Call(nil)
+	if len(r) != 1 || r[0].Int() != 5 {
+		t.Errorf("wrong result from Get: wanted [5], have %v", r)
+	}
+}
+
+// TestInterfaceReader tests implementing a standard library interface with
+// multiple results.
+func TestInterfaceReader(t *testing.T) {
+	code := []byte{
+		// func (struct{}) Read(p []byte) (int, error) {
+		// 	return len(p), nil
+		// }
+		0x48, 0x89, 0xc8, // MOVQ CX, AX
+		0x31, 0xdb, // XORL BX, BX
+		0x31, 0xc9, // XORL CX, CX
+		0xc3, // RET
+	}
+	b := MustAlloc(len(code))
+	defer b.Close()
+	b.Write(code)
+	if err := b.Exec(); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	v, err := b.Interface(reflect.TypeOf((*io.Reader)(nil)).Elem(), struct{}{}, map[string]uintptr{"Read": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := *v.Addr().Interface().(*io.Reader)
+	n, err := r.Read(make([]byte, 7))
+	if n != 7 || err != nil {
+		t.Errorf("wrong results from Read: wanted 7 <nil>, have %d %v", n, err)
+	}
+	n, err = io.ReadFull(r, make([]byte, 100))
+	if n != 100 || err != nil {
+		t.Errorf("wrong results from ReadFull: wanted 100 <nil>, have %d %v", n, err)
+	}
+}
+
+// TestInterfaceErrors tests that mismatched methods are rejected.
+func TestInterfaceErrors(t *testing.T) {
+	b, m := accumulatorBlock(t)
+	defer b.Close()
+	typ := reflect.TypeOf((*accumulator)(nil)).Elem()
+	cases := map[string]struct {
+		typ     reflect.Type
+		recv    interface{}
+		methods map[string]uintptr
+	}{
+		"not interface": {reflect.TypeOf(0), new(int), m},
+		"nil receiver":  {typ, nil, m},
+		"missing":       {typ, new(int), map[string]uintptr{"Add": 0}},
+		"wrong name":    {typ, new(int), map[string]uintptr{"Add": 0, "Set": 7}},
+		"out of bounds": {typ, new(int), map[string]uintptr{"Add": 0, "Get": 100}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := b.Interface(c.typ, c.recv, c.methods); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
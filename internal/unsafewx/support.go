@@ -0,0 +1,141 @@
+package unsafewx
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Supported returns a non-nil error describing why unsafewx cannot work with
+// the current toolchain, or nil if it can. Func and Interface depend on the
+// private layouts of reflect.Value, function values, and the runtime's
+// interface tables. Supported reports whether the program was built with a
+// compiler and Go version for which those layouts are understood and
+// verifies them at initialization, so that an unsupported toolchain produces
+// a clear error rather than a crash on the first call. Func and Interface
+// panic with this error if it is not nil.
+//
+// Supported does not check the calling convention of code in blocks. Since
+// Go 1.17, gc on amd64 passes arguments and results in registers according to
+// its internal ABI, and code obtained through Func or Interface must follow
+// it. Code obtained through FuncABI0 follows the stack-based ABI0 instead.
+func Supported() error {
+	return supportErr
+}
+
+// minVersion is the earliest Go minor version known to work. It is the
+// version go.mod requires, since golang.org/x/arch requires it.
+const minVersion = 25
+
+var supportErr = checkSupport()
+
+func checkSupport() error {
+	if err := checkCompiler(runtime.Compiler); err != nil {
+		return err
+	}
+	if err := checkVersion(runtime.Version()); err != nil {
+		return err
+	}
+	if err := checkValueLayout(); err != nil {
+		return err
+	}
+	if err := checkFuncLayout(); err != nil {
+		return err
+	}
+	return checkItabLayout()
+}
+
+// checkCompiler checks that the program was compiled with gc. gccgo uses
+// different representations for functions and interfaces.
+func checkCompiler(c string) error {
+	if c != "gc" {
+		return fmt.Errorf("wx: unsupported compiler %s: only gc is supported", c)
+	}
+	return nil
+}
+
+// checkVersion checks that a Go version string is at least go1.25.
+// Development versions are accepted; their layouts are checked directly.
+func checkVersion(v string) error {
+	if strings.HasPrefix(v, "devel") {
+		return nil
+	}
+	if !strings.HasPrefix(v, "go1.") {
+		return fmt.Errorf("wx: unrecognized Go version %q", v)
+	}
+	s := v[len("go1."):]
+	if i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		s = s[:i]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("wx: unrecognized Go version %q", v)
+	}
+	if n < minVersion {
+		return fmt.Errorf("wx: unsupported Go version %s: go1.%d or later is required", v, minVersion)
+	}
+	return nil
+}
+
+// rvalue is the layout of reflect.Value.
+// KEEP IN SYNC WITH reflect.Value:
+// https://github.com/golang/go/blob/master/src/reflect/value.go#L36
+type rvalue struct {
+	rtype unsafe.Pointer
+	ptr   unsafe.Pointer
+	flag  uintptr
+}
+
+// checkValueLayout checks that reflect.Value matches rvalue.
+func checkValueLayout() error {
+	if unsafe.Sizeof(reflect.Value{}) != unsafe.Sizeof(rvalue{}) {
+		return fmt.Errorf("wx: unsupported reflect.Value layout: size is %d, not %d", unsafe.Sizeof(reflect.Value{}), unsafe.Sizeof(rvalue{}))
+	}
+	x := new(int)
+	v := reflect.ValueOf(x)
+	r := (*rvalue)(unsafe.Pointer(&v))
+	if r.rtype != efaceOf(x).typ || r.ptr != unsafe.Pointer(x) {
+		return fmt.Errorf("wx: unsupported reflect.Value layout: fields are not type and pointer")
+	}
+	return nil
+}
+
+// checkFuncLayout checks that function values are pointers to closures whose
+// first word is the code pointer, as described in https://golang.org/s/go11func.
+func checkFuncLayout() error {
+	f := checkFuncLayout
+	return checkEntry(**(**uintptr)(unsafe.Pointer(&f)), "checkFuncLayout")
+}
+
+// checkEntry checks that the runtime's symbol table gives pc as the entry of
+// the function of this package with the given name.
+func checkEntry(pc uintptr, name string) error {
+	fn := runtime.FuncForPC(pc)
+	if fn == nil || fn.Entry() != pc || !strings.HasSuffix(fn.Name(), "/unsafewx."+name) {
+		return fmt.Errorf("wx: unsupported function value layout: closure has %#x, not the entry of %s", pc, name)
+	}
+	return nil
+}
+
+// checkItabLayout checks that interface values and their tables match
+// ifaceWords and the layout documented in Block.Interface.
+func checkItabLayout() error {
+	var buf bytes.Buffer
+	var r io.Reader = &buf
+	i := (*ifaceWords)(unsafe.Pointer(&r))
+	tab := (*itab)(i.tab)
+	inter := efaceOf(reflect.TypeOf(&r).Elem()).data
+	typ := efaceOf(&buf).typ
+	if i.data != unsafe.Pointer(&buf) || tab.inter != inter || tab.typ != typ {
+		return fmt.Errorf("wx: unsupported interface layout")
+	}
+	if tab.hash != typeHash(typ) {
+		return fmt.Errorf("wx: unsupported itab or type hash layout")
+	}
+	return nil
+}
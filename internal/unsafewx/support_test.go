@@ -0,0 +1,68 @@
+package unsafewx
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSupported tests that the toolchain running the tests is supported.
+func TestSupported(t *testing.T) {
+	if err := Supported(); err != nil {
+		t.Errorf("unsupported toolchain: %v", err)
+	}
+}
+
+// TestCheckCompiler tests that only gc is supported.
+func TestCheckCompiler(t *testing.T) {
+	if err := checkCompiler("gc"); err != nil {
+		t.Errorf("gc unsupported: %v", err)
+	}
+	if err := checkCompiler("gccgo"); err == nil {
+		t.Error("gccgo supported")
+	}
+}
+
+// TestCheckVersion tests recognition of Go versions.
+func TestCheckVersion(t *testing.T) {
+	cases := []struct {
+		v  string
+		ok bool
+	}{
+		{"go1.25", true},
+		{"go1.25.0", true},
+		{"go1.26rc1", true},
+		{"go1.27.1", true},
+		{"devel go1.28-abcdef Sun Oct 18 12:00:00 2026 +0000", true},
+		{"go1.24.9", false},
+		{"go1.12", false},
+		{"go1.4beta1", false},
+		{"go1", false},
+		{"go2.0", false},
+		{"gccgo", false},
+	}
+	for _, c := range cases {
+		err := checkVersion(c.v)
+		if (err == nil) != c.ok {
+			t.Errorf("wrong result for %q: wanted ok=%v, have %v", c.v, c.ok, err)
+		}
+	}
+}
+
+// TestCheckEntry tests that checkEntry detects code pointers which are not the
+// entry of the named function.
+func TestCheckEntry(t *testing.T) {
+	f := checkEntry
+	pc := reflect.ValueOf(f).Pointer()
+	if err := checkEntry(pc, "checkEntry"); err != nil {
+		t.Errorf("entry of checkEntry rejected: %v", err)
+	}
+	if err := checkEntry(pc+1, "checkEntry"); err == nil {
+		t.Error("pc within checkEntry accepted")
+	}
+	if err := checkEntry(pc, "checkFuncLayout"); err == nil {
+		t.Error("entry of checkEntry accepted as checkFuncLayout")
+	}
+	if err := checkEntry(0, "checkEntry"); err == nil {
+		t.Error("nil pc accepted")
+	}
+}
@@ -0,0 +1,84 @@
+package unsafewx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ResolveRel32 sets the 32-bit pc-relative displacement at offset off in the
+// block to refer to the absolute address target. The displacement is taken to
+// be relative to the end of the four bytes at off, as for x86 CALL, JMP, and
+// Jcc instructions with rel32 operands. If target is out of range of the
+// displacement, ResolveRel32 instead redirects it through a veneer, a small
+// stub in the block's veneer area which jumps to target. Veneers are reused
+// for every reference to the same target in the block. The veneer area is
+// allocated from the end of the block's capacity, so it reduces Available.
+//
+// Veneers are only supported on amd64. If target is out of range on other
+// architectures, or if there is insufficient space for a new veneer,
+// ResolveRel32 returns an error and leaves the block unchanged. Panics if the
+// block is not valid, if b.Exec has succeeded, or if the displacement is not
+// within the written portion of the block.
+func (b *Block) ResolveRel32(off, target uintptr) error {
+	if b.x {
+		panic("wx: attempted to write to executable memory")
+	}
+	if off+4 > uintptr(b.Len()) {
+		panic("wx: displacement out of bounds")
+	}
+	next := b.Addr(off + 4)
+	if d, ok := rel32(next, target); ok {
+		binary.LittleEndian.PutUint32(b.mem[off:], uint32(d))
+		return nil
+	}
+	v, err := b.veneer(target)
+	if err != nil {
+		return err
+	}
+	d, ok := rel32(next, b.Addr(v))
+	if !ok {
+		// Only possible if the block is at least 2 GB.
+		return ErrOutOfRange
+	}
+	binary.LittleEndian.PutUint32(b.mem[off:], uint32(d))
+	return nil
+}
+
+// Veneers returns the number of veneers the block has needed to reach far
+// targets.
+func (b *Block) Veneers() int {
+	return len(b.vs)
+}
+
+// veneer returns the offset of a veneer to target, creating it if needed.
+func (b *Block) veneer(target uintptr) (uintptr, error) {
+	if v, ok := b.vs[target]; ok {
+		return v, nil
+	}
+	if veneerSize == 0 {
+		return 0, ErrOutOfRange
+	}
+	if b.Available() < veneerSize {
+		return 0, ErrCapacityExceeded
+	}
+	b.top -= veneerSize
+	putVeneer(b.mem[b.top:b.top+veneerSize], target)
+	if b.vs == nil {
+		b.vs = make(map[uintptr]uintptr)
+	}
+	b.vs[target] = b.top
+	logv("created veneer to", fmt.Sprintf("%#x", target), "at offset", b.top)
+	return b.top, nil
+}
+
+// rel32 computes the displacement from next to target and whether it fits in
+// a signed 32-bit integer.
+func rel32(next, target uintptr) (int32, bool) {
+	d := target - next
+	return int32(d), uintptr(int32(d)) == d
+}
+
+// ErrOutOfRange is the error returned when a pc-relative reference cannot
+// reach its target.
+var ErrOutOfRange = errors.New("wx: target out of range")
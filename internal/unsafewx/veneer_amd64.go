@@ -0,0 +1,20 @@
+package unsafewx
+
+import "encoding/binary"
+
+// veneerSize is the size of a veneer, padded to keep veneers aligned.
+const veneerSize = 16
+
+// putVeneer writes a veneer to target into p. The veneer clobbers R12, which
+// is a scratch register in Go's internal ABI that is never used to pass
+// arguments. Note that this is not safe for targets using the System V ABI,
+// where R12 is callee-saved.
+func putVeneer(p []byte, target uintptr) {
+	// MOVQ $target, R12
+	p[0], p[1] = 0x49, 0xbc
+	binary.LittleEndian.PutUint64(p[2:], uint64(target))
+	// JMP R12
+	p[10], p[11], p[12] = 0x41, 0xff, 0xe4
+	// INT3 padding
+	p[13], p[14], p[15] = 0xcc, 0xcc, 0xcc
+}
@@ -0,0 +1,108 @@
+package unsafewx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// TestResolveRel32Near tests that targets within range are referenced
+// directly.
+func TestResolveRel32Near(t *testing.T) {
+	b := MustAlloc(64)
+	defer b.Close()
+	// CALL rel32
+	b.Write([]byte{0xe8, 0, 0, 0, 0})
+	if err := b.ResolveRel32(1, b.Addr(32)); err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if d := int32(binary.LittleEndian.Uint32(b.mem[1:])); d != 32-5 {
+		t.Errorf("wrong displacement: wanted %d, have %d", 32-5, d)
+	}
+	if b.Veneers() != 0 {
+		t.Errorf("near call needed %d veneers", b.Veneers())
+	}
+}
+
+// TestResolveRel32Far tests that far targets are reached through veneers,
+// which are reused for the same target.
+func TestResolveRel32Far(t *testing.T) {
+	b := MustAlloc(64)
+	defer b.Close()
+	c := b.Available()
+	// CALL rel32; CALL rel32; JMP rel32
+	b.Write([]byte{0xe8, 0, 0, 0, 0, 0xe8, 0, 0, 0, 0, 0xe9, 0, 0, 0, 0})
+	far := b.Addr(0) + 1<<40
+	targets := []uintptr{far, far, far + 1}
+	for i, target := range targets {
+		if err := b.ResolveRel32(uintptr(5*i+1), target); err != nil {
+			t.Fatalf("resolve %d failed: %v", i, err)
+		}
+	}
+	if b.Veneers() != 2 {
+		t.Errorf("wrong number of veneers: wanted 2, have %d", b.Veneers())
+	}
+	if b.Available() != c-15-2*veneerSize {
+		t.Errorf("wrong space available: wanted %d, have %d", c-15-2*veneerSize, b.Available())
+	}
+	for i, target := range targets {
+		off := 5*i + 1
+		v := off + 4 + int(int32(binary.LittleEndian.Uint32(b.mem[off:])))
+		if v < int(b.top) || v+veneerSize > len(b.mem) {
+			t.Errorf("reference %d resolved to %d, outside veneer area", i, v)
+			continue
+		}
+		want := make([]byte, 10)
+		want[0], want[1] = 0x49, 0xbc
+		binary.LittleEndian.PutUint64(want[2:], uint64(target))
+		if !bytes.Equal(b.mem[v:v+10], want) {
+			t.Errorf("wrong veneer for reference %d: wanted %x, have %x", i, want, b.mem[v:v+10])
+		}
+	}
+}
+
+// TestResolveRel32Full tests that a veneer which does not fit is an error.
+func TestResolveRel32Full(t *testing.T) {
+	b := MustAlloc(64)
+	defer b.Close()
+	p := make([]byte, b.Available())
+	p[0] = 0xe8
+	b.Write(p)
+	if err := b.ResolveRel32(1, b.Addr(0)+1<<40); err != ErrCapacityExceeded {
+		t.Errorf("wrong error: wanted %v, have %v", ErrCapacityExceeded, err)
+	}
+	if b.Veneers() != 0 {
+		t.Errorf("failed resolve created %d veneers", b.Veneers())
+	}
+}
+
+// TestVeneerExec tests that code can jump through a veneer to another block.
+func TestVeneerExec(t *testing.T) {
+	tb := MustAlloc(6)
+	defer tb.Close()
+	// MOVL $42, AX; RET
+	tb.Write([]byte{0xb8, 42, 0, 0, 0, 0xc3})
+	if err := tb.Exec(); err != nil {
+		t.Fatalf("exec of target failed: %v", err)
+	}
+	b := MustAlloc(5)
+	defer b.Close()
+	// JMP rel32
+	b.Write([]byte{0xe9, 0, 0, 0, 0})
+	// Force the jump through a veneer even though the target is likely near.
+	v, err := b.veneer(tb.Addr(0))
+	if err != nil {
+		t.Fatalf("creating veneer failed: %v", err)
+	}
+	d, _ := rel32(b.Addr(5), b.Addr(v))
+	binary.LittleEndian.PutUint32(b.mem[1:], uint32(d))
+	if err := b.Exec(); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	var f func() int
+	f = b.Func(0, reflect.TypeOf(f)).(func() int)
+	if r := f(); r != 42 {
+		t.Errorf("wrong result through veneer: wanted 42, have %d", r)
+	}
+}
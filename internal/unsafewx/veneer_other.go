@@ -0,0 +1,10 @@
+// +build !amd64
+
+package unsafewx
+
+// veneerSize is zero to indicate that veneers are unsupported.
+const veneerSize = 0
+
+func putVeneer(p []byte, target uintptr) {
+	panic("wx: veneers are not supported on this architecture")
+}
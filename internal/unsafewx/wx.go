@@ -15,6 +15,7 @@ package unsafewx
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"reflect"
@@ -23,9 +24,34 @@ import (
 
 // A Block represents a block of writeable or executable memory, or W^X.
 type Block struct {
-	v    uintptr // pointer to data
-	n, c uintptr // len and cap
-	x    bool    // executable flag
+	mem []byte  // mapped memory; len(mem) is the block's capacity
+	n   uintptr // len
+	x   bool    // executable flag
+	be  Backend // source of mem
+
+	top uintptr             // start of the veneer area at the end of mem
+	vs  map[uintptr]uintptr // veneer offsets by target address
+}
+
+// Alloc allocates a block of W^X memory from OSBackend. Panics if n < 0.
+func Alloc(n int) (*Block, error) {
+	return AllocFrom(OSBackend{}, n)
+}
+
+// AllocFrom allocates a block of W^X memory from the given backend. Panics if
+// n < 0.
+func AllocFrom(be Backend, n int) (*Block, error) {
+	if n < 0 {
+		panic(fmt.Errorf("wx: cannot allocate %d bytes: negative values are illegal", n))
+	}
+	logv("allocating", n, "bytes")
+	mem, err := be.Reserve(n)
+	if err != nil {
+		logv("error during alloc:", err)
+		return nil, err
+	}
+	logv("obtained", len(mem), "bytes at", fmt.Sprintf("%p", &mem[0]))
+	return &Block{mem: mem, be: be, top: uintptr(len(mem))}, nil
 }
 
 // MustAlloc is like Alloc but panics if the block could not be allocated.
@@ -39,16 +65,16 @@ func MustAlloc(n int) *Block {
 
 // IsValid returns true if the block refers to committed memory.
 func (b *Block) IsValid() bool {
-	return b != nil && b.v != 0
+	return b != nil && b.mem != nil
 }
 
-// Available returns the number of unwritten bytes in the block. Panics if the
-// block is not valid.
+// Available returns the number of unwritten bytes in the block, excluding any
+// space used by veneers. Panics if the block is not valid.
 func (b *Block) Available() int {
 	if !b.IsValid() {
 		panic("wx: use of invalid block")
 	}
-	return int(b.c - b.n)
+	return int(b.top - b.n)
 }
 
 // Len returns the number of bytes written in the block. Panics if the block is
@@ -70,6 +96,18 @@ func (b *Block) Cursor() uintptr {
 	return b.n
 }
 
+// Addr returns the absolute address of the given offset in the block. Panics
+// if the block is not valid or if off is outside the block's capacity.
+func (b *Block) Addr(off uintptr) uintptr {
+	if !b.IsValid() {
+		panic("wx: use of invalid block")
+	}
+	if off > uintptr(len(b.mem)) {
+		panic("wx: address out of bounds")
+	}
+	return uintptr(unsafe.Pointer(&b.mem[0])) + off
+}
+
 // Write writes bytes into the block. If the number of bytes to write exceeds
 // the capacity of the block, Write ignores the excess and returns
 // ErrCapacityExceeded. Panics if the block is not valid or if b.Exec has
@@ -87,28 +125,38 @@ func (b *Block) Write(p []byte) (n int, err error) {
 		err = ErrCapacityExceeded
 		n = c
 	}
-	memmove(unsafe.Pointer(b.v+b.n), unsafe.Pointer(&p[0]), uintptr(n))
+	copy(b.mem[b.n:], p[:n])
 	b.n += uintptr(n)
 	return
 }
 
+// writeAt writes p at offset off in the block, extending the written length
+// if needed. The caller must ensure that the block is writeable and that p
+// fits below the veneer area.
+func (b *Block) writeAt(p []byte, off uintptr) {
+	copy(b.mem[off:], p)
+	if e := off + uintptr(len(p)); e > b.n {
+		b.n = e
+	}
+}
+
 // WriteTo copies out the written contents of the block. This may call w.Write
 // multiple times. Panics if the block is not valid.
 func (b *Block) WriteTo(w io.Writer) (n int64, err error) {
 	const ps = 4096
-	bn := uintptr(b.Len())
+	bn := b.Len()
 	if bn <= ps {
 		// Avoid wasteful allocation when writing a small block.
 		p := make([]byte, bn)
-		memmove(unsafe.Pointer(&p[0]), unsafe.Pointer(b.v), bn)
+		copy(p, b.mem)
 		wn, err := w.Write(p)
 		return int64(wn), err
 	}
 	p := make([]byte, ps)
-	var o uintptr
+	var o int
 	var wn int
 	for bn-o > ps {
-		memmove(unsafe.Pointer(&p[0]), unsafe.Pointer(b.v+o), ps)
+		copy(p, b.mem[o:])
 		o += ps
 		wn, err = w.Write(p)
 		n += int64(wn)
@@ -116,7 +164,7 @@ func (b *Block) WriteTo(w io.Writer) (n int64, err error) {
 			return
 		}
 	}
-	memmove(unsafe.Pointer(&p[0]), unsafe.Pointer(b.v+o), bn-o)
+	copy(p, b.mem[o:bn])
 	wn, err = w.Write(p[:bn-o])
 	n += int64(wn)
 	return
@@ -129,25 +177,11 @@ func (b *Block) WriteTo(w io.Writer) (n int64, err error) {
 // not closed while the function is executing. Panics if the block is invalid,
 // has not been marked executable, or if addr is outside the block's bounds
 // (but not if the function leaves the block's bounds; that will result in an
-// unrecoverable panic).
+// unrecoverable panic). Also panics if Supported returns an error.
 func (b *Block) Func(addr uintptr, typ reflect.Type) interface{} {
-	if !b.IsValid() {
-		panic("wx: attempted to create function without committed memory")
-	}
-	if !b.x {
-		panic("wx: attempted to create function in writeable memory")
-	}
-	if addr >= b.n {
-		panic("wx: function pointer out of bounds")
-	}
+	x := b.entry(addr)
 	// Create a zero value of the function type, then set its pointer unsafely.
-	// KEEP IN SYNC WITH reflect.Value:
-	// https://github.com/golang/go/blob/master/src/reflect/value.go#L36
-	type rvalue struct {
-		rtype unsafe.Pointer
-		ptr   unsafe.Pointer
-		flag  uintptr
-	}
+	// See rvalue for the layout of reflect.Value.
 	z := reflect.Zero(typ)
 	// z.Interface() dereferences the function pointer we use here because in
 	// gc, function values (i.e., uses of functions other than by static,
@@ -155,11 +189,68 @@ func (b *Block) Func(addr uintptr, typ reflect.Type) interface{} {
 	// https://golang.org/s/go11func. It might be necessary to have a separate
 	// implementation for gccgo, but I'm not sure and can't test that easily.
 	// Wasm might also be different.
-	x := b.v + addr
 	(*rvalue)(unsafe.Pointer(&z)).ptr = unsafe.Pointer(&x)
 	return z.Interface()
 }
 
+// entry returns the absolute address of a function at addr in the block,
+// panicking under the conditions described by Func.
+func (b *Block) entry(addr uintptr) uintptr {
+	if !b.IsValid() {
+		panic("wx: attempted to create function without committed memory")
+	}
+	if !b.x {
+		panic("wx: attempted to create function in writeable memory")
+	}
+	if addr >= b.n {
+		panic("wx: function pointer out of bounds")
+	}
+	if supportErr != nil {
+		panic(supportErr)
+	}
+	return uintptr(unsafe.Pointer(&b.mem[addr]))
+}
+
+// Exec marks the block as executable. Following this, any write operations
+// panic, and functions assembled within may be called.
+func (b *Block) Exec() error {
+	if !b.IsValid() {
+		panic("wx: use of invalid block")
+	}
+	logv("marking data at", fmt.Sprintf("%p", &b.mem[0]), "with len", b.n, "cap", len(b.mem), "executable")
+	if err := b.be.Protect(b.mem, ProtRX); err != nil {
+		logv("error during protect:", err)
+		return err
+	}
+	b.x = true
+	return nil
+}
+
+// Close releases the block's memory. Following this, b.IsValid returns false.
+func (b *Block) Close() error {
+	if !b.IsValid() {
+		return ErrInvalidClose
+	}
+	logv("freeing data at", fmt.Sprintf("%p", &b.mem[0]), "with len", b.n, "cap", len(b.mem))
+	if err := b.be.Release(b.mem); err != nil {
+		logv("error during free:", err)
+		return err
+	}
+	b.mem = nil
+	return nil
+}
+
+// decommit returns the memory of whole pages of the block, starting at off, to
+// the system. The block's backend must be a Decommitter.
+func (b *Block) decommit(off, n uintptr) error {
+	logv("decommitting", n, "bytes at", fmt.Sprintf("%p", &b.mem[off]))
+	if err := b.be.(Decommitter).Decommit(b.mem[off : off+n]); err != nil {
+		logv("error during decommit:", err)
+		return err
+	}
+	return nil
+}
+
 // ErrCapacityExceeded is the error returned when attempting to write more data
 // than a block can hold.
 var ErrCapacityExceeded = errors.New("wx: write exceeded block availability")
@@ -176,9 +267,3 @@ func logv(args ...interface{}) {
 		Verbose.Println(args...)
 	}
 }
-
-// memmove is the internal implementation of the copy builtin.
-// KEEP IN SYNC WITH runtime.memmove:
-// https://github.com/golang/go/blob/master/src/runtime/stubs.go#L88
-//go:linkname memmove runtime.memmove
-func memmove(to, from unsafe.Pointer, n uintptr)
@@ -10,10 +10,8 @@ func Example() {
 		// func(x, y int) (int, int) {
 		// 	return x, y
 		// }
-		0x48, 0x8b, 0x44, 0x24, 0x08, // MOVQ 0x08(SP), AX
-		0x48, 0x89, 0x44, 0x24, 0x18, // MOVQ AX, 0x18(SP)
-		0x48, 0x8b, 0x44, 0x24, 0x10, // MOVQ 0x10(SP), AX
-		0x48, 0x89, 0x44, 0x24, 0x20, // MOVQ AX, 0x20(SP)
+		// Go's internal ABI passes x and y in AX and BX and expects the
+		// results in AX and BX, so there is nothing to move.
 		0xc3, // RET
 	}
 	b := MustAlloc(len(code))
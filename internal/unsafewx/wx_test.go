@@ -6,7 +6,6 @@ import (
 	"math/rand"
 	"reflect"
 	"testing"
-	"unsafe"
 )
 
 // TestClose tests that allocated blocks can be closed exactly once.
@@ -42,7 +41,7 @@ func TestWrite(t *testing.T) {
 				t.Errorf("wrote wrong number of bytes: wanted %d, have %d", c, n)
 			}
 			for i := 0; i < n; i++ {
-				x := *(*byte)(unsafe.Pointer(b.v + uintptr(i)))
+				x := b.mem[i]
 				if x != a[i] {
 					t.Errorf("wrong value written at position %d: wanted %d, have %d", i, a[i], x)
 				}
@@ -108,7 +107,7 @@ func TestWriteTooMuch(t *testing.T) {
 				}
 			}
 			for i := 0; i < n; i++ {
-				x := *(*byte)(unsafe.Pointer(b.v + uintptr(i)))
+				x := b.mem[i]
 				if x != a[i] {
 					t.Errorf("wrong value written at position %d: wanted %d, have %d", i, a[i], x)
 				}
@@ -154,7 +153,7 @@ func TestWriteTo(t *testing.T) {
 				t.Errorf("wrote wrong number of bytes: wanted %d, have %d", c, n)
 			}
 			for i := 0; i < int(n); i++ {
-				x := *(*byte)(unsafe.Pointer(b.v + uintptr(i)))
+				x := b.mem[i]
 				if x != a[i] {
 					t.Errorf("wrong value written at position %d: wanted %d, have %d", i, x, a[i])
 				}
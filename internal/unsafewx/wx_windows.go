@@ -1,55 +1,66 @@
 package unsafewx
 
 import (
-	"fmt"
+	"reflect"
+	"unsafe"
 
 	"golang.org/x/sys/windows"
 )
 
-// Alloc allocates a block of W^X memory. Panics if n < 0.
-func Alloc(n int) (*Block, error) {
-	if n < 0 {
-		panic(fmt.Errorf("wx: cannot allocate %d bytes: negative values are illegal", n))
-	}
-	ps := windows.Getpagesize()
-	c := (n + ps - 1) / ps * ps
-	logv("allocating", n, "bytes rounded up to", c)
+// OSBackend is the default Backend, which uses VirtualAlloc, VirtualProtect,
+// and VirtualFree.
+type OSBackend struct{}
+
+// Reserve reserves and commits n bytes rounded up to a multiple of the page
+// size.
+func (OSBackend) Reserve(n int) ([]byte, error) {
+	c := roundPage(n, windows.Getpagesize())
 	p, err := windows.VirtualAlloc(0, uintptr(c), windows.MEM_RESERVE|windows.MEM_COMMIT, windows.PAGE_READWRITE)
 	if err != nil {
-		logv("error during alloc:", err)
 		return nil, err
 	}
-	logv("obtained", c, "bytes at", fmt.Sprintf("%#x", p))
-	b := &Block{v: p, c: uintptr(c)}
-	return b, nil
+	// While converting from pointer-to-slice to pointer-to-reflect.SliceHeader
+	// is among the valid use cases for unsafe.Pointer, the documentation for
+	// unsafe says not to create SliceHeader values. Oh well.
+	var mem []byte
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&mem))
+	h.Data, h.Len, h.Cap = p, c, c
+	return mem, nil
 }
 
-// Exec marks the block as executable. Following this, any write operations
-// panic, and functions assembled within may be called.
-func (b *Block) Exec() error {
-	logv("marking data at", fmt.Sprintf("%#x", b.v), "with len", b.n, "cap", b.c, "executable")
-	var x uint32
-	if err := windows.VirtualProtect(b.v, b.c, windows.PAGE_EXECUTE_READ, &x); err != nil {
-		logv("error during protect:", err)
-		return err
+// Protect calls VirtualProtect.
+func (OSBackend) Protect(mem []byte, p Prot) error {
+	var prot uint32
+	switch p {
+	case ProtRW:
+		prot = windows.PAGE_READWRITE
+	case ProtRX:
+		prot = windows.PAGE_EXECUTE_READ
+	default:
+		panic("wx: invalid protection " + p.String())
 	}
-	b.x = true
+	var x uint32
 	// MSDN says we should call FlushInstructionCache to ensure that the CPU
 	// sees the new executable memory, but sys/windows doesn't provide that
 	// function, and I don't see other JIT examples using it.
-	return nil
+	return windows.VirtualProtect(addr(mem), uintptr(len(mem)), prot, &x)
 }
 
-// Close releases the block's memory. Following this, b.IsValid returns false.
-func (b *Block) Close() error {
-	if !b.IsValid() {
-		return ErrInvalidClose
-	}
-	logv("freeing data at", fmt.Sprintf("%#x", b.v), "with len", b.n, "cap", b.c)
-	if err := windows.VirtualFree(b.v, 0, windows.MEM_RELEASE); err != nil {
-		logv("error during free:", err)
-		return err
-	}
-	b.v = 0
-	return nil
+// Release calls VirtualFree.
+func (OSBackend) Release(mem []byte) error {
+	return windows.VirtualFree(addr(mem), 0, windows.MEM_RELEASE)
+}
+
+// Decommit calls VirtualFree with MEM_DECOMMIT.
+func (OSBackend) Decommit(mem []byte) error {
+	return windows.VirtualFree(addr(mem), uintptr(len(mem)), windows.MEM_DECOMMIT)
+}
+
+// DecommitSize returns the system's page size.
+func (OSBackend) DecommitSize() int {
+	return windows.Getpagesize()
+}
+
+func addr(mem []byte) uintptr {
+	return uintptr(unsafe.Pointer(&mem[0]))
 }
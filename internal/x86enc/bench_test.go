@@ -0,0 +1,36 @@
+package x86enc
+
+import "testing"
+
+// BenchmarkLookup measures finding the forms of every mnemonic in the table.
+func BenchmarkLookup(b *testing.B) {
+	var ops []string
+	for m := 0; m < len(tableIdcs)-1; m++ {
+		ops = append(ops, mnemonic(m).String())
+	}
+	lookup(ops[0])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if lookup(ops[i%len(ops)]) == nil {
+			b.Fatal("missing", ops[i%len(ops)])
+		}
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	b.Run("ADD", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Encode("ADD", Mem{Base: RSP, Disp: 8}, R9)
+		}
+	})
+	b.Run("MOV", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Encode("MOV", RAX, Imm(-1<<40))
+		}
+	})
+	b.Run("PSHUFD", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Encode("PSHUFD", X0, X1, Imm(0x1b))
+		}
+	})
+}
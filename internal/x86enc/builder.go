@@ -0,0 +1,109 @@
+package x86enc
+
+import "fmt"
+
+// A Builder appends instructions to a buffer through methods named for each
+// mnemonic and signature of operand types, like ADD_r64_imm8 or
+// VFMADD231PS_ymm_ymm_m256, so that operands of the wrong kind do not
+// compile. mkenc generates the methods from the table, with documentation
+// giving the forms each one encodes, their encodings, and the features they
+// require. Arguments which accept either a register or memory, like r/m64,
+// have separate methods for each.
+//
+// A method encodes the shortest of the forms of its signature which match
+// the operands and which the target supports. Like EncodeForm, it does not
+// substitute forms with other immediate widths. The first error stops the
+// Builder, and Code reports it.
+//
+// The zero Builder encodes for 64-bit mode on the processor running the
+// program, like the package's functions.
+type Builder struct {
+	// Encoder is the target. If it is nil, the Builder encodes like the
+	// package's functions.
+	Encoder *Encoder
+
+	code []byte
+	err  error
+	// prefixes, mask, zero, and rc apply to the next instruction.
+	prefixes []Operand
+	mask     K
+	zero     bool
+	rc       Rounding
+}
+
+// Code returns the encoded instructions and the first error in encoding them.
+func (b *Builder) Code() ([]byte, error) {
+	return b.code, b.err
+}
+
+// Reset discards the instructions and error of the Builder.
+func (b *Builder) Reset() {
+	b.code, b.err = b.code[:0], nil
+	b.prefixes, b.mask, b.zero, b.rc = b.prefixes[:0], K0, false, 0
+}
+
+// Prefix gives prefixes to the next instruction and returns b. It is an
+// error if the next instruction does not allow them.
+func (b *Builder) Prefix(ps ...Prefix) *Builder {
+	for _, p := range ps {
+		b.prefixes = append(b.prefixes, p)
+	}
+	return b
+}
+
+// Mask applies an opmask to the destination of the next instruction, with
+// zeroing if zero is true, and returns b. It is an error if the next
+// instruction does not allow the mask.
+func (b *Builder) Mask(k K, zero bool) *Builder {
+	b.mask, b.zero = k, zero
+	return b
+}
+
+// Round gives a rounding control to the next instruction and returns b. It
+// is an error if the next instruction does not allow the rounding control.
+func (b *Builder) Round(rc Rounding) *Builder {
+	b.rc = rc
+	return b
+}
+
+// form encodes an instruction using the shortest of the given rows of the
+// table which matches the operands.
+func (b *Builder) form(args []Operand, rows ...int) {
+	prefixes, mask, zero, rc := b.prefixes, b.mask, b.zero, b.rc
+	b.prefixes, b.mask, b.zero, b.rc = b.prefixes[:0], K0, false, 0
+	if b.err != nil {
+		return
+	}
+	if mask != K0 || zero {
+		args[0] = Masked{Op: args[0], Mask: mask, Zero: zero}
+	}
+	if rc != 0 {
+		args = append(args, rc)
+	}
+	if len(prefixes) > 0 {
+		args = append(prefixes[:len(prefixes):len(prefixes)], args...)
+	}
+	e := b.Encoder
+	if e == nil {
+		e = hostCPU
+	}
+	op := table[rows[0]].op
+	in := func(r *instruction) bool {
+		for _, i := range rows {
+			if r == &table[i] {
+				return true
+			}
+		}
+		return false
+	}
+	p, r, err := e.shortest(op.rows(), args, in, 0)
+	if err == nil && !in(r) {
+		// shortest falls back to the other forms of the mnemonic.
+		err = fmt.Errorf("x86enc: no form of %s matches operands %v", table[rows[0]].String(), args)
+	}
+	if err != nil {
+		b.err = err
+		return
+	}
+	b.code = append(b.code, p...)
+}
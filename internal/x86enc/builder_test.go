@@ -0,0 +1,91 @@
+package x86enc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestBuilder checks instructions appended through generated methods.
+func TestBuilder(t *testing.T) {
+	var b Builder
+	b.ADD_r64_imm8(RAX, Imm(1))
+	b.ADD_r64_imm32(RAX, Imm(1))
+	b.ADD_r64_r64(RCX, RDX)
+	b.MOV_r32_m32(EAX, Mem{Base: RSP, Disp: 8})
+	b.SHL_r32_1(EBX)
+	b.ADD_AL_imm8(Imm(2))
+	b.Mask(K1, true).VADDPS_zmm_zmm_zmm(Z0, Z1, Z2)
+	b.Round(RoundZero).VADDPD_zmm_zmm_zmm(Z3, Z4, Z5)
+	b.VFMADD231PS_ymm_ymm_ymm(Y1, Y2, Y3)
+	b.VFMADD231PS_ymm_ymm_ymm(Y17, Y2, Y3)
+	b.Prefix(LOCK).XADD_m32_r32(Mem{Base: RSI, Seg: GS}, EAX)
+	b.Prefix(REP).MOVSB()
+	b.RET()
+	code, err := b.Code()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"add rax, 0x1",
+		"add rax, 0x1",
+		"add rcx, rdx",
+		"mov eax, dword ptr [rsp+0x8]",
+		"shl ebx, 0x1",
+		"add al, 0x2",
+		"vaddps zmm0 {k1} {z}, zmm1, zmm2",
+		"vaddpd zmm3, zmm4, zmm5, {rz-sae}",
+		"vfmadd231ps ymm1, ymm2, ymm3",
+		"vfmadd231ps ymm17, ymm2, ymm3",
+		"lock xadd dword ptr gs:[rsi], eax",
+		"rep movsb byte ptr [rdi], byte ptr [rsi]",
+		"ret",
+	}
+	lens := []int{4, 7, 3, 4, 2, 2, 6, 6, 5, 6, 5, 2, 1}
+	for i, n := range lens {
+		checkDecode(t, code[:n], 64, want[i])
+		code = code[n:]
+	}
+	if len(code) != 0 {
+		t.Errorf("%x left over", code)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		build func(*Builder)
+		want  string
+	}{
+		{"immediate range", func(b *Builder) { b.ADD_r64_imm8(RAX, Imm(0x1000)) }, "no form of ADD r/m64, imm8"},
+		{"mask", func(b *Builder) { b.Mask(K1, false).ADD_r64_r64(RAX, RBX) }, "x86enc:"},
+		{"prefix", func(b *Builder) { b.Prefix(LOCK).ADD_r64_r64(RAX, RBX) }, "x86enc: LOCK"},
+		{"features", func(b *Builder) {
+			b.Encoder = &Encoder{}
+			b.VADDPS_xmm_xmm_xmm(X0, X1, X2)
+		}, "requires AVX"},
+		{"mode", func(b *Builder) {
+			b.Encoder = &Encoder{Mode: 32}
+			b.PUSH_r64(RAX)
+		}, "x86enc:"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var b Builder
+			c.build(&b)
+			b.RET()
+			code, err := b.Code()
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("wrong error %v, want %q", err, c.want)
+			}
+			if len(code) != 0 {
+				t.Errorf("encoded %x after error", code)
+			}
+			b.Reset()
+			b.RET()
+			if code, err := b.Code(); err != nil || !bytes.Equal(code, []byte{0xc3}) {
+				t.Errorf("after Reset: %x, %v", code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package x86enc
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// conformanceSkips lists the mnemonics and forms whose rows TestConformance
+// does not check, with the reasons.
+var conformanceSkips = map[string]string{
+	"ANDN":       "x86asm does not decode BMI instructions",
+	"BEXTR":      "x86asm does not decode BMI instructions",
+	"BLSI":       "x86asm does not decode BMI instructions",
+	"BLSMSK":     "x86asm does not decode BMI instructions",
+	"BLSR":       "x86asm does not decode BMI instructions",
+	"BZHI":       "x86asm does not decode BMI instructions",
+	"MULX":       "x86asm does not decode BMI instructions",
+	"PDEP":       "x86asm does not decode BMI instructions",
+	"PEXT":       "x86asm does not decode BMI instructions",
+	"RORX":       "x86asm does not decode BMI instructions",
+	"SARX":       "x86asm does not decode BMI instructions",
+	"SHLX":       "x86asm does not decode BMI instructions",
+	"SHRX":       "x86asm does not decode BMI instructions",
+	"RDRAND r64": "x86asm drops the operand when REX.W is set",
+	"UD1":        "x86.csv has no ModRM byte for UD1",
+
+	"VCVTDQ2PD xmm1{k1}{z}, xmm2/m64/m32bcst":    "x86asm shows the half-width operand as YMM",
+	"VCVTPD2DQ xmm1{k1}{z}, xmm2/m128/m64bcst":   "x86asm shows the half-width operand as YMM",
+	"VCVTPD2PS xmm1{k1}{z}, xmm2/m128/m64bcst":   "x86asm shows the half-width operand as YMM",
+	"VCVTPD2UDQ xmm1{k1}{z}, xmm2/m128/m64bcst":  "x86asm shows the half-width operand as YMM",
+	"VCVTPH2PS xmm1{k1}{z}, xmm2/m64":            "x86asm shows the half-width operand as YMM",
+	"VCVTPS2PD xmm1{k1}{z}, xmm2/m64/m32bcst":    "x86asm shows the half-width operand as YMM",
+	"VCVTPS2PH xmm1/m64{k1}{z}, xmm2, imm8u":     "x86asm shows the half-width operand as YMM",
+	"VCVTTPD2DQ xmm1{k1}{z}, xmm2/m128/m64bcst":  "x86asm shows the half-width operand as YMM",
+	"VCVTTPD2UDQ xmm1{k1}{z}, xmm2/m128/m64bcst": "x86asm shows the half-width operand as YMM",
+	"VCVTUDQ2PD xmm1{k1}{z}, xmm2/m64/m32bcst":   "x86asm shows the half-width operand as YMM",
+}
+
+// TestConformance encodes representative operands for every row of the table
+// valid in 64-bit mode, decodes the results with x86asm, and checks that the
+// mnemonic and operands survive. Run with -v for the coverage of each row and
+// the rows skipped.
+func TestConformance(t *testing.T) {
+	testConformance(t, 64)
+}
+
+// TestConformance32 is TestConformance for the rows valid in 32-bit mode,
+// using the operands which do not need 64-bit mode with 32-bit addresses.
+func TestConformance32(t *testing.T) {
+	testConformance(t, 32)
+}
+
+func testConformance(t *testing.T, mode int) {
+	var checked, vectors int
+	skipped := make(map[string]int)
+	for i := range table {
+		r := &table[i]
+		if !r.valid(mode) {
+			continue
+		}
+		op := r.op.String()
+		if _, ok := conformanceSkips[r.String()]; ok {
+			op = r.String()
+		}
+		if _, ok := conformanceSkips[op]; ok {
+			skipped[op]++
+			continue
+		}
+		checked++
+		vs := conformanceVectors(r)
+		if mode == 32 {
+			vs = vectors32(vs)
+		}
+		for _, args := range vs {
+			if err := conform(r, args, mode); err != nil {
+				t.Errorf("%v with %v: %v", r, args, err)
+				break
+			}
+		}
+		vectors += len(vs)
+		t.Logf("%v: %d sets of operands", r, len(vs))
+	}
+	var ops []string
+	n := 0
+	for op, c := range skipped {
+		ops = append(ops, op)
+		n += c
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		t.Logf("skipped %d rows of %s: %s", skipped[op], op, conformanceSkips[op])
+	}
+	t.Logf("checked %d rows with %d sets of operands; skipped %d rows", checked, vectors, n)
+}
+
+// conform encodes operands with a row in a mode and checks the decoded
+// instruction.
+func conform(r *instruction, args []Operand, mode int) error {
+	ops, rc := splitRounding(args)
+	if !r.matches(ops) || !r.rounds(rc) {
+		return fmt.Errorf("operands do not match")
+	}
+	b, err := encode(r, ops, rc, mode)
+	if err != nil {
+		return err
+	}
+	inst, err := x86asm.Decode(b, mode)
+	if err != nil {
+		return fmt.Errorf("%x does not decode: %v", b, err)
+	}
+	if inst.Len != len(b) {
+		return fmt.Errorf("%x decodes as %d bytes", b, inst.Len)
+	}
+	if got, want := inst.Op.String(), r.op.String(); got != want {
+		return fmt.Errorf("%x decodes as %v", b, x86asm.IntelSyntax(inst, 0, nil))
+	}
+	if r.nargs() == 0 && stringOps[r.op.String()] {
+		// x86asm shows the implicit operands of string instructions.
+		return nil
+	}
+	cands, err := instArgs(&inst)
+	if err != nil {
+		return err
+	}
+	for _, c := range cands {
+		if sameOperands(r, args, c) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%x decodes as %v", b, x86asm.IntelSyntax(inst, 0, nil))
+}
+
+// sameOperands returns whether the operands decoded from an instruction are
+// those it was encoded with.
+func sameOperands(r *instruction, want, got []Operand) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if !sameOperand(kindAt(r, i), want[i], got[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func kindAt(r *instruction, i int) *kind {
+	if i < len(r.args) {
+		return &kindTable[r.args[i]]
+	}
+	return &kindTable[0]
+}
+
+func sameOperand(k *kind, want, got Operand) bool {
+	switch w := want.(type) {
+	case Masked:
+		g, ok := got.(Masked)
+		return ok && w.Mask == g.Mask && w.Zero == g.Zero && sameOperand(k, w.Op, g.Op)
+	case Imm:
+		g, ok := got.(Imm)
+		if !ok {
+			return false
+		}
+		if k.imm == 0 || k.imm == 8 {
+			return w == g
+		}
+		m := uint64(1)<<(8*uint(k.imm)) - 1
+		return uint64(w)&m == uint64(g)&m
+	case Mem:
+		g, ok := got.(Mem)
+		if !ok || w.Base != g.Base || w.Index != g.Index || w.Disp != g.Disp || w.Broadcast != g.Broadcast {
+			return false
+		}
+		return w.Index == nil || w.Scale == g.Scale
+	}
+	return want == got
+}
+
+// conformanceVectors returns sets of operands for a row. Each set takes the
+// samples for each argument at the same index, so that every sample is used
+// and registers which need REX prefixes meet ones which forbid them only in
+// sets where that is valid.
+func conformanceVectors(r *instruction) [][]Operand {
+	n := r.nargs()
+	samples := make([][]Operand, n)
+	most := 0
+	for i := range samples {
+		samples[i] = conformanceSamples(r, &kindTable[r.args[i]])
+		if len(samples[i]) > most {
+			most = len(samples[i])
+		}
+	}
+	// EVEX-encoded gathers and scatters always need opmasks.
+	gather := false
+	for i := 0; i < n; i++ {
+		switch kindTable[r.args[i]].mem {
+		case memVSIBX, memVSIBY, memVSIBZ:
+			gather = r.enc.flags&encEVEX != 0
+		}
+	}
+	var vs [][]Operand
+	for j := 0; j < most; j++ {
+		v := make([]Operand, n)
+		for i, s := range samples {
+			v[i] = s[j%len(s)]
+			if gather && kindTable[r.args[i]].mask != maskNone {
+				v[i] = Masked{Op: v[i], Mask: K1 + K(j%7)}
+			}
+		}
+		if r.enc.nopcode == 1 && r.enc.opcode[0] == 0x90 && n == 2 && v[0] == v[1] {
+			// Exchanging the accumulator with itself is NOP.
+			continue
+		}
+		vs = append(vs, v)
+	}
+	for i := 0; i < n && !gather; i++ {
+		k := &kindTable[r.args[i]]
+		if k.bcst != 0 {
+			v := append([]Operand(nil), vs[0]...)
+			v[i] = Mem{Base: RCX, Disp: int32(k.bcst), Broadcast: true}
+			vs = append(vs, v)
+		}
+		if k.mask != maskNone {
+			v := append([]Operand(nil), vs[1%len(vs)]...)
+			v[i] = Masked{Op: v[i], Mask: K1}
+			vs = append(vs, v)
+			if _, mem := vs[0][i].(Mem); k.mask == maskZero && !mem {
+				v := append([]Operand(nil), vs[0]...)
+				v[i] = Masked{Op: v[i], Mask: K7, Zero: true}
+				vs = append(vs, v)
+			}
+		}
+		if k.round != roundNone {
+			// Rounding applies to register forms, and the first set uses
+			// registers for every argument which allows them.
+			v := append([]Operand(nil), vs[0]...)
+			if k.round == roundER {
+				v = append(v, RoundZero)
+			} else {
+				v = append(v, SAE)
+			}
+			vs = append(vs, v)
+		}
+	}
+	if len(vs) == 0 {
+		vs = append(vs, nil)
+	}
+	return vs
+}
+
+// vectors32 returns the sets of operands which are valid in 32-bit mode, with
+// 64-bit address registers replaced by their 32-bit halves.
+func vectors32(vs [][]Operand) [][]Operand {
+	var r [][]Operand
+next:
+	for _, v := range vs {
+		w := make([]Operand, len(v))
+		for i, a := range v {
+			m, masked := a.(Masked)
+			if masked {
+				a = m.Op
+			}
+			if mem, ok := a.(Mem); ok {
+				mem.Base, mem.Index = reg32(mem.Base), reg32(mem.Index)
+				a = mem
+			}
+			if needs64(a) {
+				continue next
+			}
+			if masked {
+				m.Op = a
+				a = m
+			}
+			w[i] = a
+		}
+		r = append(r, w)
+	}
+	return r
+}
+
+// reg32 returns the 32-bit half of a 64-bit general-purpose register.
+func reg32(r Reg) Reg {
+	if q, ok := r.(GPR64); ok {
+		return EAX + GPR32(q.Num())
+	}
+	return r
+}
+
+// conformanceSamples returns representative operands for a kind. Register
+// samples come first, so that the first set of operands for a form which
+// allows registers uses them.
+func conformanceSamples(r *instruction, k *kind) []Operand {
+	if k.fixed != nil {
+		return []Operand{k.fixed}
+	}
+	evex := r.enc.flags&encEVEX != 0
+	var s []Operand
+	if k.rm && r.flags&tagMemOnly != 0 {
+		k = &kind{mem: k.mem}
+	}
+	switch k.reg {
+	case ClassGPR:
+		switch k.regSize {
+		case 1:
+			s = []Operand{AL, R9B, SIL, AH}
+			if r.enc.flags&encREXW != 0 {
+				// AH cannot be encoded with any REX prefix.
+				s[3] = BL
+			}
+		case 2:
+			s = []Operand{AX, R10W, SP, BX}
+		case 4:
+			s = []Operand{EAX, R11D, EBP, ECX}
+		case 8:
+			s = []Operand{RAX, R13, RSP, RDX}
+		}
+	case ClassXMM:
+		s = []Operand{X0, X9, X15, X3}
+		if evex {
+			s = append(s, X17, X31)
+		}
+	case ClassYMM:
+		s = []Operand{Y0, Y9, Y15, Y3}
+		if evex {
+			s = append(s, Y17, Y31)
+		}
+	case ClassZMM:
+		s = []Operand{Z0, Z9, Z15, Z3, Z17, Z31}
+	case ClassK:
+		s = []Operand{K1, K6, K7, K0}
+	case ClassMMX:
+		s = []Operand{M0, M5, M7, M2}
+	case ClassSeg:
+		s = []Operand{ES, FS, GS, DS}
+	case ClassX87:
+		s = []Operand{ST0, ST5, ST7, ST1}
+	case ClassCR:
+		s = []Operand{CR(0), CR(8), CR(4), CR(3)}
+	case ClassDR:
+		s = []Operand{DR(0), DR(7), DR(6), DR(1)}
+	case ClassTR:
+		s = []Operand{TR(3), TR(7), TR(6), TR(4)}
+	}
+	mem := k.mem
+	if k.rm && r.flags&tagRegOnly != 0 {
+		mem = memNone
+	}
+	switch mem {
+	case memModRM:
+		s = append(s,
+			Mem{Base: RAX},
+			Mem{Base: R12, Index: R13, Scale: 4, Disp: 0x40},
+			Mem{Base: RBP},
+			Mem{Base: RSP, Disp: -0x80},
+			Mem{Base: RBX, Index: RCX, Scale: 8, Disp: -0x12345},
+			Mem{Disp: 0x1000},
+		)
+		if r.enc.flags&(encVEX|encEVEX) == 0 {
+			// x86asm drops the base of RIP-relative operands of VEX- and
+			// EVEX-encoded instructions, leaving them the same as absolute
+			// ones. They use the same ModRM encoding as legacy forms.
+			s = append(s, Mem{Base: RIP, Disp: 0x12345678})
+		}
+	case memOffset:
+		s = append(s, Mem{Disp: 0x1000}, Mem{Disp: -1})
+	case memVSIBX, memVSIBY, memVSIBZ:
+		var idx []Reg
+		switch mem {
+		case memVSIBX:
+			idx = []Reg{X1, X12, X7}
+			if evex {
+				idx = append(idx, X20)
+			}
+		case memVSIBY:
+			idx = []Reg{Y1, Y12, Y7}
+			if evex {
+				idx = append(idx, Y20)
+			}
+		case memVSIBZ:
+			idx = []Reg{Z1, Z12, Z7, Z20}
+		}
+		for i, x := range idx {
+			s = append(s, Mem{Base: []Reg{RAX, R13, RSP, RBP}[i], Index: x, Scale: []uint8{8, 2, 1, 4}[i], Disp: int32(i) * 0x40})
+		}
+	}
+	if k.imm != 0 {
+		bits := 8 * uint(k.imm)
+		if bits == 64 {
+			s = append(s, Imm(0), Imm(1<<63-1), Imm(-1<<63))
+		} else {
+			s = append(s, Imm(0), Imm(1<<(bits-1)-1), Imm(-1<<(bits-1)))
+			if k.unsigned || k.imm == r.immSize() {
+				s = append(s, Imm(1<<bits-1))
+			}
+		}
+	}
+	if k.rel != 0 {
+		bits := 8 * uint(k.rel)
+		s = append(s, Rel(0), Rel(1<<(bits-1)-1), Rel(-1<<(bits-1)))
+	}
+	if k.far != 0 {
+		s = append(s, FarPtr{Seg: 8, Off: 0x1234}, FarPtr{Seg: 0xffff, Off: 0})
+	}
+	return s
+}
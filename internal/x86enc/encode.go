@@ -0,0 +1,933 @@
+// Package x86enc encodes x86 instructions to machine code.
+//
+// Instruction forms are described by a table generated by mkenc from a copy
+// of the x86.csv file in golang.org/x/arch and the AVX-512 forms in evex.csv.
+// mkenc interprets each form's argument kinds and encoding ahead of time.
+// Encode finds the forms of a mnemonic matching the kinds of the given
+// operands and emits the shortest of their encodings, while EncodeForm uses
+// one form named by the caller.
+//
+// Legacy, REX, VEX, and EVEX encodings are supported. VEX forms use the
+// two-byte prefix whenever it can express the instruction; forms whose vector
+// length or W bit is ignored (LIG, WIG) are encoded with the bit clear.
+//
+// Instructions take the legacy prefixes LOCK, REP, REPN, and ADDRSIZE as
+// leading Prefix operands, which Encode checks against the instructions they
+// apply to. Memory operands may override their segments, as for thread-local
+// storage through FS or GS, and string instructions take a SegPrefix to
+// override the segment of their source.
+//
+// EVEX-encoded AVX-512 forms take a Masked destination to apply an opmask, a
+// Mem with Broadcast set for embedded broadcast, and a trailing Rounding
+// operand for static rounding or suppressing exceptions. Displacements are
+// compressed to 8 bits when they are a multiple of the size of the memory
+// operand.
+//
+// The package's functions encode for 64-bit mode on the processor running the
+// program, with the features HostFeatures reports. An Encoder encodes for a
+// target with any set of features, such as AllFeatures, and refuses forms
+// which need features the target lacks. Requires and RequiresCode report the
+// features which instructions need.
+//
+// When no form encodes an instruction's operands, the error is a
+// *MismatchError, which lists each form of the mnemonic with the reason it was
+// rejected, like an operand of the wrong width or an immediate out of range.
+//
+// A Builder has a method for each mnemonic and signature of operand types,
+// generated by mkenc alongside the table, for building code whose operands are
+// checked by the compiler.
+//
+// Describe and DescribeForm report what an instruction does besides its
+// encoding: how it accesses its operands and memory, the registers and flags
+// it reads and writes implicitly, like RDX:RAX for DIV, and a coarse class of
+// its latency. mkenc takes this metadata from meta.csv.
+//
+// AppendNOP pads code with the multi-byte NOPs which the Intel manual
+// recommends, as for aligning the heads of loops.
+//
+// Code is encoded for 64-bit mode unless an Encoder's Mode is 32, which
+// selects 32-bit protected mode, as used by programs built with GOARCH=386.
+// 32-bit mode uses the forms valid in it, which include short forms like
+// INC r32 that 64-bit mode lacks. Operations default to 32-bit operands and
+// addresses, and there is no REX prefix, so 64-bit registers, R8 through R15,
+// XMM8 and above, SPL, BPL, SIL, DIL, and RIP-relative addressing are
+// unavailable.
+package x86enc
+
+//go:generate go run ./mkevex
+//go:generate go run ./mkenc
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// An Encoder encodes instructions for a target processor, refusing forms
+// which require features the target lacks. The zero Encoder targets a
+// processor in 64-bit mode with none of the features, not even SSE2; the
+// package's functions target the processor running the program.
+type Encoder struct {
+	// Features is the set of features of the target.
+	Features Feature
+	// Mode is the processor mode of the target, 32 for protected mode or 64
+	// for 64-bit mode. 0 means 64.
+	Mode int
+}
+
+// NewEncoder returns an Encoder for the processor and mode running the
+// program.
+func NewEncoder() *Encoder {
+	e := &Encoder{Features: HostFeatures(), Mode: 64}
+	if runtime.GOARCH == "386" {
+		e.Mode = 32
+	}
+	return e
+}
+
+// mode returns the processor mode of the target.
+func (e *Encoder) mode() (int, error) {
+	switch e.Mode {
+	case 0, 64:
+		return 64, nil
+	case 32:
+		return 32, nil
+	}
+	return 0, fmt.Errorf("x86enc: unsupported mode %d", e.Mode)
+}
+
+// hostCPU is the Encoder used by the package's functions, and anyCPU is the
+// Encoder RequiresCode uses to report features the host may lack.
+var (
+	hostCPU = &Encoder{Features: HostFeatures()}
+	anyCPU  = &Encoder{Features: AllFeatures}
+)
+
+// Encode encodes an instruction for 64-bit mode on the processor running the
+// program. op is the instruction's mnemonic as it appears in the Intel
+// manual, e.g. "ADD" or "MOVDQU", and args are its operands in Intel order,
+// destination first. Encode uses the shortest encoding among the forms which
+// match the operands, preferring the first form in the table among encodings
+// of the same length. If forms accessing memory of different widths match a
+// memory operand without a Size, as with INC [RAX], Encode returns an error
+// rather than choosing a width.
+func Encode(op string, args ...Operand) ([]byte, error) {
+	return hostCPU.Encode(op, args...)
+}
+
+// Encode encodes an instruction like the package's Encode function, using
+// only forms which the target supports in its mode.
+func (e *Encoder) Encode(op string, args ...Operand) ([]byte, error) {
+	b, _, err := e.choose(op, args)
+	return b, err
+}
+
+// Requires returns the features required by the form Encode uses for an
+// instruction. A few forms, like XTEST, require any one of several features;
+// Requires includes those which the target has.
+func (e *Encoder) Requires(op string, args ...Operand) (Feature, error) {
+	_, r, err := e.choose(op, args)
+	if err != nil {
+		return 0, err
+	}
+	return e.requires(r), nil
+}
+
+// Requires returns the features required by the form Encode uses for an
+// instruction, including every alternative for forms like XTEST which
+// require any one of several features.
+func Requires(op string, args ...Operand) (Feature, error) {
+	return hostCPU.Requires(op, args...)
+}
+
+// Known reports whether op is the mnemonic of any instruction form in the
+// table.
+func Known(op string) bool {
+	return lookup(op) != nil
+}
+
+// requires returns the features of the target which a row uses.
+func (e *Encoder) requires(r *instruction) Feature {
+	f := r.features()
+	return f.all | f.any&e.Features
+}
+
+// choose encodes an instruction and returns the row it used.
+func (e *Encoder) choose(op string, args []Operand) ([]byte, *instruction, error) {
+	rows := lookup(op)
+	if rows == nil {
+		return nil, nil, fmt.Errorf("x86enc: unknown instruction %s", op)
+	}
+	return e.shortest(rows, args, nil, 0)
+}
+
+// shortest returns the shortest encoding of the operands among the rows which
+// the target supports, and the row it used, considering only rows for which
+// prefer returns true if there are any which match. If end is not 0, relative
+// operands are relative to end bytes from the start of the instruction
+// instead of its end, whatever its length. If no row encodes the operands,
+// shortest returns a *MismatchError.
+func (e *Encoder) shortest(rows []instruction, args []Operand, prefer func(*instruction) bool, end int) ([]byte, *instruction, error) {
+	mode, err := e.mode()
+	if err != nil {
+		return nil, nil, err
+	}
+	ops, ps := splitPrefixes(args)
+	ops, rc := splitRounding(ops)
+	if err := checkPrefixes(rows[0].op.String(), ps, ops); err != nil {
+		return nil, nil, err
+	}
+	var best []byte
+	var row *instruction
+	for pass := 0; pass < 2 && best == nil; pass++ {
+		// Rows which access memory of different widths through an operand
+		// without a Size are different operations, not longer and shorter
+		// encodings of one.
+		var first, other *instruction
+		width := 0
+		for i := range rows {
+			r := &rows[i]
+			if !r.valid(mode) || !r.matches(ops) || !r.rounds(rc) {
+				continue
+			}
+			if prefer != nil && prefer(r) != (pass == 0) {
+				continue
+			}
+			b, err := encode(r, ops, rc, mode)
+			if err == nil && end != 0 && pcrel(ops) {
+				// The length of a form does not depend on the values of
+				// its relative operands, but whether they fit does.
+				moved := shiftRel(ops, end-len(b))
+				if !r.matches(moved) {
+					continue
+				}
+				b, err = encode(r, moved, rc, mode)
+			}
+			if err != nil || e.supports(r) != nil {
+				continue
+			}
+			if w := r.memWidth(ops); first == nil {
+				first, width = r, w
+			} else if w != width {
+				other = r
+			}
+			if best == nil || len(b) < len(best) {
+				best, row = b, r
+			}
+		}
+		if other != nil {
+			return nil, nil, fmt.Errorf("x86enc: width of memory operand of %s %v is ambiguous: it matches %v and %v", first.op, args, first, other)
+		}
+		if prefer == nil {
+			break
+		}
+	}
+	if best == nil {
+		return nil, nil, e.mismatch(rows, args, end, mode)
+	}
+	return append(prefixBytes(ps), best...), row, nil
+}
+
+// memWidth returns the width in bytes of the memory which the row accesses
+// through the first memory operand without a Size, 0 if the row's argument
+// does not specify the width, or -1 if there is no such operand.
+func (r *instruction) memWidth(ops []Operand) int {
+	for i, o := range ops {
+		if m, ok := o.(Masked); ok {
+			o = m.Op
+		}
+		m, ok := o.(Mem)
+		if !ok || m.Size != 0 {
+			continue
+		}
+		k := &kindTable[r.args[i]]
+		if m.Broadcast {
+			return k.bcst
+		}
+		return k.memSize
+	}
+	return -1
+}
+
+// supports returns an error naming the features of a row the target lacks.
+func (e *Encoder) supports(r *instruction) error {
+	if f := r.features(); !f.in(e.Features) {
+		return fmt.Errorf("x86enc: %v requires %s", r, f.missing(e.Features))
+	}
+	return nil
+}
+
+// EncodeForm encodes an instruction for 64-bit mode on the processor running
+// the program using a particular form, named by its mnemonic and argument
+// kinds as in x86.csv, e.g. "ADD r/m64, imm32". Whereas Encode may choose a
+// shorter form depending on the operands' values, the immediates and opcode of
+// the form EncodeForm uses are fixed, so that sequences which are patched
+// later keep their sizes. Prefixes and ModRM still depend on the registers and
+// memory operands.
+func EncodeForm(form string, args ...Operand) ([]byte, error) {
+	return hostCPU.EncodeForm(form, args...)
+}
+
+// EncodeForm encodes an instruction like the package's EncodeForm function,
+// failing if the target does not support the form in its mode.
+func (e *Encoder) EncodeForm(form string, args ...Operand) ([]byte, error) {
+	mode, err := e.mode()
+	if err != nil {
+		return nil, err
+	}
+	f := strings.FieldsFunc(form, func(c rune) bool { return c == ' ' || c == ',' })
+	if len(f) == 0 {
+		return nil, fmt.Errorf("x86enc: empty form")
+	}
+	rows := lookup(f[0])
+	if rows == nil {
+		return nil, fmt.Errorf("x86enc: unknown instruction %s", f[0])
+	}
+	args, ps := splitPrefixes(args)
+	ops, rc := splitRounding(args)
+	if err := checkPrefixes(f[0], ps, ops); err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		r := &rows[i]
+		if !r.valid(mode) || !r.is(f[1:]) {
+			continue
+		}
+		if !r.matches(ops) || !r.rounds(rc) {
+			return nil, fmt.Errorf("x86enc: form %s does not match operands %v", r, args)
+		}
+		if err := e.supports(r); err != nil {
+			return nil, err
+		}
+		b, err := encode(r, ops, rc, mode)
+		if err != nil {
+			return nil, err
+		}
+		return append(prefixBytes(ps), b...), nil
+	}
+	return nil, fmt.Errorf("x86enc: no form %s valid in %d-bit mode", form, mode)
+}
+
+// is returns whether the row's argument kinds are named by names.
+func (r *instruction) is(names []string) bool {
+	if r.nargs() != len(names) {
+		return false
+	}
+	for i, s := range names {
+		if kindTable[r.args[i]].name != s {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the row's form as it appears in x86.csv.
+func (r *instruction) String() string {
+	s := r.op.String()
+	for i, a := range r.args[:r.nargs()] {
+		if i == 0 {
+			s += " "
+		} else {
+			s += ", "
+		}
+		s += kindTable[a].name
+	}
+	return s
+}
+
+// nargs returns the number of arguments of the row.
+func (r *instruction) nargs() int {
+	n := 0
+	for n < len(r.args) && r.args[n] != 0 {
+		n++
+	}
+	return n
+}
+
+// valid returns whether the row is a real instruction form valid in the given
+// mode, 32 or 64.
+func (r *instruction) valid(mode int) bool {
+	if mode == 64 {
+		return r.flags&(valid64|tagPseudo|tagPseudo64) == valid64
+	}
+	if r.flags&(valid32|tagPseudo) != valid32 {
+		return false
+	}
+	if r.enc.flags&encREXW != 0 || r.flags&(tagOperand16|tagOperand32|tagOperand64) == tagOperand64 {
+		// x86.csv marks a few rows which need REX.W as valid.
+		return false
+	}
+	for _, a := range r.args {
+		if kindTable[a].rel == 2 {
+			// Branches to 16-bit targets truncate EIP.
+			return false
+		}
+	}
+	return true
+}
+
+// size returns the operand size of the row, determined by its first
+// general-purpose register or memory argument with a width.
+func (r *instruction) size() int {
+	for _, a := range r.args {
+		if n := kindTable[a].gprSize(); n != 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// immSize returns the operand size which determines whether the row's
+// immediates may be given as unsigned values. The 8-bit immediates of VEX- and
+// EVEX-encoded rows, like the selector of VPERMQ, are never sign-extended, so
+// they accept any byte.
+func (r *instruction) immSize() int {
+	if r.enc.flags&(encVEX|encEVEX) != 0 {
+		return 1
+	}
+	return r.size()
+}
+
+// matches returns whether the operands satisfy the row's argument kinds.
+func (r *instruction) matches(args []Operand) bool {
+	if n := r.nargs(); n != len(args) {
+		if n != len(args)+1 || !kindTable[r.args[n-1]].optional {
+			return false
+		}
+	}
+	size := r.immSize()
+	for i, a := range args {
+		k := &kindTable[r.args[i]]
+		if !k.accepts(a, size) {
+			return false
+		}
+		if k.rm && r.flags&(tagRegOnly|tagMemOnly) != 0 {
+			_, mem := a.(Mem)
+			if mem == (r.flags&tagRegOnly != 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// splitRounding separates a trailing rounding control from the operands. The
+// rounding control is 0 if there is none.
+func splitRounding(args []Operand) ([]Operand, Rounding) {
+	if n := len(args); n > 0 {
+		if rc, ok := args[n-1].(Rounding); ok {
+			return args[:n-1], rc
+		}
+	}
+	return args, 0
+}
+
+// splitPrefixes separates leading prefixes from the operands.
+func splitPrefixes(args []Operand) ([]Operand, []Prefix) {
+	var ps []Prefix
+	for len(args) > 0 {
+		p, ok := args[0].(Prefix)
+		if !ok {
+			break
+		}
+		ps = append(ps, p)
+		args = args[1:]
+	}
+	return args, ps
+}
+
+// lockable are the instructions which allow LOCK when their destination is
+// memory. XCHG with memory is atomic even without it.
+var lockable = map[string]bool{
+	"ADC": true, "ADD": true, "AND": true, "BTC": true, "BTR": true, "BTS": true,
+	"CMPXCHG": true, "CMPXCHG8B": true, "CMPXCHG16B": true, "DEC": true,
+	"INC": true, "NEG": true, "NOT": true, "OR": true, "SBB": true, "SUB": true,
+	"XADD": true, "XCHG": true, "XOR": true,
+}
+
+// checkPrefixes returns an error if an instruction does not allow its
+// prefixes.
+func checkPrefixes(op string, ps []Prefix, args []Operand) error {
+	str := stringOps[op] && len(args) == 0
+	for i, p := range ps {
+		_, seg := p.seg()
+		for _, q := range ps[:i] {
+			_, qseg := q.seg()
+			if q == p || (q == REP || q == REPN) && (p == REP || p == REPN) || seg && qseg {
+				return fmt.Errorf("x86enc: %v cannot be used with %v", p, q)
+			}
+		}
+		var ok bool
+		switch p {
+		case LOCK:
+			ok = lockable[op] && len(args) > 0 && isMem(args[0])
+		case REP:
+			ok = str && op != "XLATB"
+		case REPN:
+			ok = str && (strings.HasPrefix(op, "CMPS") || strings.HasPrefix(op, "SCAS"))
+		case ADDRSIZE:
+			ok = str
+		default:
+			if !seg {
+				return fmt.Errorf("x86enc: unknown prefix %v", p)
+			}
+			// STOS, SCAS, and INS write or compare through ES, which
+			// cannot be overridden.
+			ok = str && !strings.HasPrefix(op, "STOS") && !strings.HasPrefix(op, "SCAS") && !strings.HasPrefix(op, "INS")
+		}
+		if !ok {
+			return fmt.Errorf("x86enc: %v cannot be used with %s %v", p, op, args)
+		}
+	}
+	return nil
+}
+
+// isMem returns whether an operand is a memory operand, possibly masked.
+func isMem(o Operand) bool {
+	if m, ok := o.(Masked); ok {
+		o = m.Op
+	}
+	_, ok := o.(Mem)
+	return ok
+}
+
+// prefixBytes returns the encoding of prefixes.
+func prefixBytes(ps []Prefix) []byte {
+	if len(ps) == 0 {
+		return nil
+	}
+	b := make([]byte, len(ps))
+	for i, p := range ps {
+		b[i] = byte(p)
+	}
+	return b
+}
+
+// segPrefixes are the segment override prefixes of the segment registers.
+var segPrefixes = [...]byte{ES: 0x26, CS: 0x2e, SS: 0x36, DS: 0x3e, FS: 0x64, GS: 0x65}
+
+// rounds returns whether the row allows a rounding control, which is 0 if
+// there is none.
+func (r *instruction) rounds(rc Rounding) bool {
+	if rc == 0 {
+		return true
+	}
+	for _, a := range r.args {
+		switch kindTable[a].round {
+		case roundER:
+			return RoundNearest <= rc && rc <= RoundZero
+		case roundSAE:
+			return rc == SAE
+		}
+	}
+	return false
+}
+
+// encode emits an instruction using a matching row in the given mode. rc is
+// the rounding control, or 0 if there is none.
+func encode(r *instruction, args []Operand, rc Rounding, mode int) ([]byte, error) {
+	e := &r.enc
+	evex := e.flags&encEVEX != 0
+	var (
+		rex       byte
+		reg       = -1
+		rm        Operand
+		vvvv, is4 int
+		mask      K
+		zero      bool
+		bcst      int
+		imms      []Operand
+	)
+	if e.flags&encREXW != 0 || r.flags&(tagOperand16|tagOperand32|tagOperand64) == tagOperand64 {
+		// A few rows like LAR r64 are only tagged with their operand size.
+		rex |= 0x48
+	}
+	needREX, noREX := e.flags&encREX != 0, false
+	opcode := append([]byte(nil), e.opcode[:e.nopcode]...)
+	var seg []byte
+	for i, a := range args {
+		if m, ok := a.(Masked); ok {
+			if m.Mask == K0 {
+				return nil, fmt.Errorf("x86enc: K0 cannot be used as an opmask")
+			}
+			if _, ok := m.Op.(Mem); ok && m.Zero {
+				return nil, fmt.Errorf("x86enc: zeroing cannot be used with a memory destination")
+			}
+			a, mask, zero = m.Op, m.Mask, m.Zero
+		}
+		if m, ok := a.(Mem); ok && m.Seg != nil {
+			s, ok := m.Seg.(Seg)
+			if !ok || int(s) >= len(segPrefixes) {
+				return nil, fmt.Errorf("x86enc: invalid segment register %v", m.Seg)
+			}
+			seg = append(seg[:0], segPrefixes[s])
+		}
+		if mode == 32 && needs64(a) {
+			return nil, fmt.Errorf("x86enc: %v requires 64-bit mode", a)
+		}
+		if !evex && highReg(a) {
+			return nil, fmt.Errorf("x86enc: %v requires an EVEX-encoded form of %v", a, r.op)
+		}
+		if r8, ok := a.(GPR8); ok {
+			needREX = needREX || (SPL <= r8 && r8 <= R15B)
+			noREX = noREX || r8 >= AH
+		}
+		switch r.roles[i] {
+		case roleReg:
+			reg = a.(Reg).Num()
+		case roleRM:
+			rm = a
+			if m, ok := a.(Mem); ok && m.Broadcast {
+				bcst = kindTable[r.args[i]].bcst
+			}
+		case roleOpcode:
+			n := a.(Reg).Num()
+			if e.opcode[0] == 0x90 && n == 0 && r.flags&tagOperand32 != 0 {
+				// 90 is NOP, which does not clear the upper half of RAX.
+				return nil, fmt.Errorf("x86enc: %v cannot exchange EAX with itself", r)
+			}
+			opcode[len(opcode)-1] += byte(n & 7)
+			rex |= rexBit(n, 0x41)
+		case roleVVVV:
+			vvvv = a.(Reg).Num()
+		case roleIs4:
+			is4 = a.(Reg).Num()
+		case roleImm:
+			imms = append(imms, a)
+		}
+	}
+	if e.modrm >= 0 && e.modrm < modrmR {
+		reg = int(e.modrm)
+	}
+	var p3 byte // EVEX z, L'L, b, and aaa
+	if evex {
+		p3 = byte(mask)
+		if zero {
+			p3 |= 0x80
+		}
+		if e.flags&vexL != 0 {
+			p3 |= 0x20
+		}
+		if e.flags&evexL2 != 0 {
+			p3 |= 0x40
+		}
+		if m, ok := rm.(Mem); ok {
+			if rc != 0 {
+				return nil, fmt.Errorf("x86enc: %v cannot be used with a memory operand", rc)
+			}
+			if m.Index != nil && m.Index.Class() != ClassGPR {
+				if mask == K0 {
+					return nil, fmt.Errorf("x86enc: %v requires an opmask", r.op)
+				}
+				// Gathers and scatters have no vvvv operand. EVEX.V' holds
+				// the high bit of the index instead.
+				vvvv = m.Index.Num() & 16
+			}
+		}
+		switch {
+		case bcst != 0:
+			p3 |= 0x10
+		case RoundNearest <= rc && rc <= RoundZero:
+			// Static rounding replaces the vector length.
+			p3 = p3&^0x60 | byte(rc-RoundNearest)<<5 | 0x10
+		case rc == SAE:
+			p3 |= 0x10
+		}
+	}
+	var modrm []byte
+	var addr32 bool
+	if e.modrm != modrmNone {
+		rex |= rexBit(reg, 0x44)
+		var x byte
+		var err error
+		scale := uint(0)
+		if evex {
+			scale = uint(e.disp8)
+			if bcst != 0 {
+				scale = log2(bcst)
+			}
+		}
+		modrm, x, addr32, err = encodeRM(reg, rm, scale, mode)
+		if err != nil {
+			return nil, err
+		}
+		rex |= x
+	}
+	if mode == 32 && (rex != 0 || needREX) {
+		return nil, fmt.Errorf("x86enc: %v requires a REX prefix, which 32-bit mode lacks", r)
+	}
+	b := seg
+	// The address size prefix selects the size which is not the mode's
+	// default.
+	switch r.flags & (tagAddress16 | tagAddress32 | tagAddress64) {
+	case tagAddress32:
+		addr32 = addr32 || mode == 64
+	case tagAddress16:
+		addr32 = addr32 || mode == 32
+	}
+	if addr32 {
+		b = append(b, 0x67)
+	}
+	switch {
+	case evex:
+		b = appendEVEX(b, e, rex, reg, vvvv, p3)
+	case e.flags&encVEX != 0:
+		b = appendVEX(b, e, rex, vvvv)
+	default:
+		if rex != 0 || needREX {
+			if noREX {
+				return nil, fmt.Errorf("x86enc: %v cannot be encoded with AH, BH, CH, or DH", r.op)
+			}
+			rex |= 0x40
+		}
+		prefix := e.prefix[:e.nprefix]
+		if r.flags&(tagOperand16|tagOperand32|tagOperand64) == tagOperand16 && !hasByte(prefix, 0x66) {
+			b = append(b, 0x66)
+		}
+		b = append(b, prefix...)
+		if rex != 0 {
+			b = append(b, rex)
+		}
+	}
+	b = append(b, opcode...)
+	b = append(b, modrm...)
+	if e.flags&encIs4 != 0 {
+		b = append(b, byte(is4<<4))
+	}
+	for i, a := range imms {
+		switch a := a.(type) {
+		case Imm:
+			b = appendLE(b, int64(a), int(e.imm[i]))
+		case Rel:
+			b = appendLE(b, int64(a), int(e.imm[i]))
+		case Mem:
+			// Memory offsets are absolute addresses as wide as the mode's
+			// addresses.
+			n := int(e.imm[i])
+			if mode == 32 {
+				n = 4
+			}
+			b = appendLE(b, int64(a.Disp), n)
+		case FarPtr:
+			// The offset precedes the segment selector.
+			b = appendLE(b, int64(a.Off), int(e.imm[i])-2)
+			b = appendLE(b, int64(a.Seg), 2)
+		}
+	}
+	return b, nil
+}
+
+// appendVEX appends the VEX prefix of an encoding. rex holds the REX.R, REX.X,
+// and REX.B bits required by the operands, and vvvv is the register number
+// for VEX.vvvv, or 0 if it encodes no operand. The two-byte form is used when
+// it can express the prefix.
+func appendVEX(b []byte, e *encoding, rex byte, vvvv int) []byte {
+	// R, X, B, and vvvv are stored inverted.
+	lpp := e.pp | byte(vvvv&15^15)<<3
+	if e.flags&vexL != 0 {
+		lpp |= 4
+	}
+	w := e.flags&vexW != 0
+	if rex&3 == 0 && !w && e.mmmmm == 1 {
+		return append(b, 0xc5, (rex&4^4)<<5|lpp)
+	}
+	if w {
+		lpp |= 0x80
+	}
+	return append(b, 0xc4, (rex&7^7)<<5|e.mmmmm, lpp)
+}
+
+// appendEVEX appends the EVEX prefix of an encoding. rex holds the REX.R,
+// REX.X, and REX.B bits required by the operands, reg and vvvv are the
+// register numbers for ModRM.reg and EVEX.vvvv including their high bits,
+// and p3 holds the final byte of the prefix apart from EVEX.V'.
+func appendEVEX(b []byte, e *encoding, rex byte, reg, vvvv int, p3 byte) []byte {
+	// R, X, B, R', vvvv, and V' are stored inverted.
+	p1 := (rex&7^7)<<5 | e.mmmmm
+	if reg&16 == 0 {
+		p1 |= 0x10
+	}
+	p2 := byte(vvvv&15^15)<<3 | 4 | e.pp
+	if e.flags&vexW != 0 {
+		p2 |= 0x80
+	}
+	if vvvv&16 == 0 {
+		p3 |= 8
+	}
+	return append(b, 0x62, p1, p2, p3)
+}
+
+// highReg returns whether an operand is or uses one of the vector registers
+// 16 through 31, which only EVEX can encode.
+func highReg(o Operand) bool {
+	switch o := o.(type) {
+	case XMM, YMM, ZMM:
+		return o.(Reg).Num() >= 16
+	case Mem:
+		return o.Index != nil && o.Index.Num() >= 16
+	}
+	return false
+}
+
+// needs64 returns whether an operand is or uses a register which only 64-bit
+// mode has, or is RIP-relative.
+func needs64(o Operand) bool {
+	switch o := o.(type) {
+	case GPR8:
+		return SPL <= o && o <= R15B
+	case GPR64, IP:
+		return true
+	case Reg:
+		return o.Num() >= 8
+	case Mem:
+		return o.Base != nil && needs64(o.Base) || o.Index != nil && needs64(o.Index)
+	}
+	return false
+}
+
+// rexBit returns bit if n requires a REX extension bit.
+func rexBit(n int, bit byte) byte {
+	if n&8 != 0 {
+		return bit
+	}
+	return 0
+}
+
+// log2 returns the base 2 logarithm of a power of two.
+func log2(n int) uint {
+	k := uint(0)
+	for n > 1 {
+		n >>= 1
+		k++
+	}
+	return k
+}
+
+func hasByte(p []byte, c byte) bool {
+	for _, b := range p {
+		if b == c {
+			return true
+		}
+	}
+	return false
+}
+
+// appendLE appends the low n bytes of v in little-endian order.
+func appendLE(b []byte, v int64, n int) []byte {
+	for i := 0; i < n; i++ {
+		b = append(b, byte(v>>(8*uint(i))))
+	}
+	return b
+}
+
+// encodeRM produces the ModRM byte, SIB byte, and displacement for an rm
+// operand with the given reg field in the given mode. It also returns the
+// REX.X and REX.B bits needed and whether an address size prefix is needed.
+// 8-bit displacements are scaled by 1<<scale, as EVEX compresses them.
+func encodeRM(reg int, rm Operand, scale uint, mode int) (b []byte, rex byte, addr32 bool, err error) {
+	modrm := byte(reg&7) << 3
+	if r, ok := rm.(Reg); ok {
+		// EVEX uses X to extend a register in ModRM.rm to 32 registers.
+		rex = rexBit(r.Num(), 0x41) | rexBit(r.Num()>>1, 0x42)
+		return []byte{0xc0 | modrm | byte(r.Num()&7)}, rex, false, nil
+	}
+	m, ok := rm.(Mem)
+	if !ok {
+		return nil, 0, false, fmt.Errorf("x86enc: invalid rm operand %v", rm)
+	}
+	vsib := false
+	if m.Index != nil {
+		switch m.Index.Class() {
+		case ClassXMM, ClassYMM, ClassZMM:
+			vsib = true
+		}
+	}
+	if m.Base != nil && m.Index != nil && !vsib && m.Base.Size() != m.Index.Size() {
+		return nil, 0, false, fmt.Errorf("x86enc: mismatched address registers in %v", m)
+	}
+	if ip, ok := m.Base.(IP); ok {
+		// mod=00 rm=101 is disp32 relative to the end of the instruction,
+		// which cannot have an index.
+		if m.Index != nil {
+			return nil, 0, false, fmt.Errorf("x86enc: %v cannot have an index register", ip)
+		}
+		b = append(b, modrm|5)
+		return appendLE(b, int64(m.Disp), 4), 0, ip == EIP, nil
+	}
+	index := m.Index
+	if vsib {
+		index = nil
+	}
+	for _, r := range []Reg{m.Base, index} {
+		switch r.(type) {
+		case nil, GPR64:
+		case GPR32:
+			addr32 = mode == 64
+		default:
+			return nil, 0, false, fmt.Errorf("x86enc: invalid address register %v", r)
+		}
+	}
+	if m.Index != nil {
+		if m.Index.Num() == 4 && !vsib {
+			return nil, 0, false, fmt.Errorf("x86enc: %v cannot be an index register", m.Index)
+		}
+		switch m.Scale {
+		case 1, 2, 4, 8:
+		default:
+			return nil, 0, false, fmt.Errorf("x86enc: invalid scale %d", m.Scale)
+		}
+	}
+	if m.Base == nil && m.Index == nil && mode == 32 {
+		// mod=00 rm=101 is disp32 outside 64-bit mode.
+		b = append(b, modrm|5)
+		return appendLE(b, int64(m.Disp), 4), 0, false, nil
+	}
+	if m.Base == nil {
+		// [disp32] or [index*scale+disp32], both requiring SIB since
+		// mod=00 rm=101 means RIP-relative in 64-bit mode.
+		sib := byte(0x25)
+		if m.Index != nil {
+			sib = scaleBits(m.Scale) | byte(m.Index.Num()&7)<<3 | 5
+			rex |= rexBit(m.Index.Num(), 0x42)
+		}
+		b = append(b, modrm|4, sib)
+		return appendLE(b, int64(m.Disp), 4), rex, addr32, nil
+	}
+	base := m.Base.Num()
+	rex |= rexBit(base, 0x41)
+	var mod byte
+	var disp int
+	d := int64(m.Disp)
+	switch n := int64(1) << scale; {
+	case d == 0 && base&7 != 5:
+		// RBP and R13 as base with mod=00 mean RIP or disp32 instead.
+	case d%n == 0 && -128 <= d/n && d/n < 128:
+		mod, disp, d = 0x40, 1, d/n
+	default:
+		mod, disp = 0x80, 4
+	}
+	if m.Index == nil && base&7 != 4 {
+		b = append(b, mod|modrm|byte(base&7))
+	} else {
+		// RSP and R12 as base require SIB.
+		sib := byte(0x20) | byte(base&7)
+		if m.Index != nil {
+			sib = scaleBits(m.Scale) | byte(m.Index.Num()&7)<<3 | byte(base&7)
+			rex |= rexBit(m.Index.Num(), 0x42)
+		}
+		b = append(b, mod|modrm|4, sib)
+	}
+	return appendLE(b, d, disp), rex, addr32, nil
+}
+
+func scaleBits(s uint8) byte {
+	switch s {
+	case 2:
+		return 0x40
+	case 4:
+		return 0x80
+	case 8:
+		return 0xc0
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package x86enc_test
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/zephyrtronium/ikitai/internal/unsafewx"
+	. "github.com/zephyrtronium/ikitai/internal/x86enc"
+)
+
+func ExampleEncoder_Encode_mode32() {
+	// func(p []int32) int32 {
+	// 	s := int32(0)
+	// 	for i := len(p); i != 0; i-- {
+	// 		s += p[i-1]
+	// 	}
+	// 	return s
+	// }
+	// Go's ABI on 386 passes the slice's pointer, length, and capacity at
+	// 4(SP), 8(SP), and 12(SP) and expects the result at 16(SP).
+	e := &Encoder{Mode: 32}
+	var code []byte
+	for _, inst := range []struct {
+		op   string
+		args []Operand
+	}{
+		{"XOR", []Operand{EAX, EAX}},
+		{"MOV", []Operand{ECX, Mem{Base: ESP, Disp: 8}}},
+		{"MOV", []Operand{EDX, Mem{Base: ESP, Disp: 4}}},
+		{"JECXZ", []Operand{Rel(7)}}, // done
+		// loop:
+		{"ADD", []Operand{EAX, Mem{Base: EDX, Index: ECX, Scale: 4, Disp: -4}}},
+		{"DEC", []Operand{ECX}},
+		{"JNE", []Operand{Rel(-7)}}, // loop
+		// done:
+		{"MOV", []Operand{Mem{Base: ESP, Disp: 16}, EAX}},
+		{"RET", nil},
+	} {
+		b, err := e.Encode(inst.op, inst.args...)
+		if err != nil {
+			panic(err)
+		}
+		code = append(code, b...)
+	}
+
+	b := unsafewx.MustAlloc(len(code))
+	defer b.Close()
+	b.Write(code)
+	b.Exec()
+	var f func([]int32) int32
+	f = b.Func(0, reflect.TypeOf(f)).(func([]int32) int32)
+	fmt.Println(f([]int32{1, 2, 3, 4}), f(nil))
+	// Output: 10 0
+}
@@ -0,0 +1,623 @@
+package x86enc
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// hostTarget is the target of the package's functions, which TestMain
+// replaces.
+var hostTarget = hostCPU
+
+// TestMain makes the package's functions target every feature, so that the
+// tests do not depend on the processor running them.
+func TestMain(m *testing.M) {
+	hostCPU = anyCPU
+	os.Exit(m.Run())
+}
+
+// checkDecode decodes b in the given mode and checks that it is exactly one
+// instruction with the given Intel syntax.
+func checkDecode(t *testing.T, b []byte, mode int, want string) {
+	t.Helper()
+	inst, err := x86asm.Decode(b, mode)
+	if err != nil {
+		t.Errorf("%x does not decode: %v", b, err)
+		return
+	}
+	if inst.Len != len(b) {
+		t.Errorf("%x decodes as %d bytes, not %d", b, inst.Len, len(b))
+	}
+	if s := x86asm.IntelSyntax(inst, 0, nil); s != want {
+		t.Errorf("%x decodes as %q, not %q", b, s, want)
+	}
+}
+
+// TestEncode tests encoding legacy instructions in 64-bit mode.
+func TestEncode(t *testing.T) {
+	cases := []struct {
+		op   string
+		args []Operand
+		want string
+	}{
+		{"ADD", []Operand{RAX, Imm(1)}, "add rax, 0x1"},
+		{"ADD", []Operand{Mem{Base: RSP, Disp: 8}, R9}, "add qword ptr [rsp+0x8], r9"},
+		{"ADD", []Operand{R10D, Imm(-100000)}, "add r10d, -0x186a0"},
+		{"SUB", []Operand{Mem{Base: RBP}, AL}, "sub byte ptr [rbp], al"},
+		{"MOV", []Operand{R12D, Mem{Base: R13, Index: R14, Scale: 4}}, "mov r12d, dword ptr [r13+4*r14]"},
+		{"MOV", []Operand{RCX, Mem{Index: RDX, Scale: 8, Disp: 0x100}}, "mov rcx, qword ptr [8*rdx+0x100]"},
+		{"MOV", []Operand{RCX, Mem{Disp: 0x1000}}, "mov rcx, qword ptr [0x1000]"},
+		{"MOV", []Operand{Mem{Base: R12, Disp: -4}, EBX}, "mov dword ptr [r12-0x4], ebx"},
+		{"MOV", []Operand{SIL, Imm(200)}, "mov sil, 0xc8"},
+		{"MOV", []Operand{RAX, Imm(-1 << 40)}, "mov rax, 0xffffff0000000000"},
+		{"MOV", []Operand{AX, Mem{Base: EAX}}, "mov ax, word ptr [eax]"},
+		{"MOVZX", []Operand{EAX, BL}, "movzx eax, bl"},
+		{"LEA", []Operand{R8, Mem{Base: RAX, Index: RBX, Scale: 2, Disp: 1}}, "lea r8, ptr [rax+2*rbx+0x1]"},
+		{"PUSH", []Operand{R15}, "push r15"},
+		{"POP", []Operand{RBX}, "pop rbx"},
+		{"SHL", []Operand{EAX, Imm(1)}, "shl eax, 0x1"},
+		{"SHR", []Operand{R11, CL}, "shr r11, cl"},
+		{"IMUL", []Operand{RDX, Mem{Base: RSI}, Imm(10)}, "imul rdx, qword ptr [rsi], 0xa"},
+		{"JMP", []Operand{Rel(-2)}, "jmp .-0x2"},
+		{"CALL", []Operand{Rel(0x100)}, "call .+0x100"},
+		{"RET", nil, "ret"},
+		{"NOP", nil, "nop"},
+		{"PAUSE", nil, "pause"},
+		{"MOVDQU", []Operand{X9, Mem{Base: RAX}}, "movdqu xmm9, xmmword ptr [rax]"},
+		{"ADDPD", []Operand{X1, X15}, "addpd xmm1, xmm15"},
+		{"PSHUFD", []Operand{X0, X1, Imm(0x1b)}, "pshufd xmm0, xmm1, 0x1b"},
+		{"BLENDVPD", []Operand{X3, X4}, "blendvpd xmm3, xmm4"},
+		{"CVTSI2SD", []Operand{X2, R8}, "cvtsi2sd xmm2, r8"},
+		{"MOVZX", []Operand{EAX, Mem{Base: RDI, Size: 1}}, "movzx eax, byte ptr [rdi]"},
+		{"MOVZX", []Operand{EAX, Mem{Base: RDI, Size: 2}}, "movzx eax, word ptr [rdi]"},
+		{"MOV", []Operand{RAX, Mem{Base: RIP, Disp: 0x10}}, "mov rax, qword ptr [rip+0x10]"},
+		{"LEA", []Operand{R9, Mem{Base: RIP, Disp: -7}}, "lea r9, ptr [rip+0xfffffff9]"},
+		{"MOV", []Operand{EAX, Mem{Base: EIP}}, "mov eax, dword ptr [rip]"}, // x86asm prints EIP-relative addresses as rip
+		{"MOV", []Operand{AL, Mem{Disp: 0x1234}}, "mov al, byte ptr [0x1234]"},
+		{"MOV", []Operand{Mem{Disp: 0x40}, RAX}, "mov qword ptr [0x40], rax"},
+		{"MOV", []Operand{DS, Mem{Base: RAX, Size: 2}}, "mov ds, word ptr [rax]"},
+		{"MOV", []Operand{RCX, FS}, "mov rcx, fs"},
+		{"PUSH", []Operand{GS}, "push gs"},
+		{"MOV", []Operand{RAX, CR(3)}, "mov rax, cr3"},
+		{"MOV", []Operand{DR(7), RDX}, "mov dr7, rdx"},
+		{"FADD", []Operand{ST0, ST(3)}, "fadd st0, st3"},
+		{"FLD", []Operand{Mem{Base: RSP, Size: 10}}, "fld st0, ptr [rsp]"},
+		{"PADDB", []Operand{M1, Mem{Base: RBX}}, "paddb mmx1, qword ptr [rbx]"},
+		{"MOVQ2DQ", []Operand{X3, M2}, "movq2dq xmm3, mmx2"},
+		{"JECXZ", []Operand{Rel(4)}, "addr32 jecxz .+0x4"}, // x86asm shows the 67 prefix which makes it JECXZ
+		{"LAR", []Operand{RAX, RCX}, "lar rax, rcx"},
+	}
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			b, err := Encode(c.op, c.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDecode(t, b, 64, c.want)
+		})
+	}
+}
+
+// TestEncodeVEX tests encoding VEX-prefixed instructions in 64-bit mode.
+func TestEncodeVEX(t *testing.T) {
+	cases := []struct {
+		op   string
+		args []Operand
+		want string
+	}{
+		{"VADDPD", []Operand{X1, X2, X3}, "vaddpd xmm1, xmm2, xmm3"},
+		{"VADDPD", []Operand{Y1, Y2, Mem{Base: RAX}}, "vaddpd ymm1, ymm2, ymmword ptr [rax]"},
+		{"VADDPD", []Operand{X8, X9, X10}, "vaddpd xmm8, xmm9, xmm10"},
+		{"VADDPD", []Operand{Y0, Y15, Mem{Base: R8, Index: R9, Scale: 2}}, "vaddpd ymm0, ymm15, ymmword ptr [r8+2*r9]"},
+		{"VADDSD", []Operand{X1, X2, X3}, "vaddsd xmm1, xmm2, xmm3"},
+		{"VMOVDQU", []Operand{Y3, Mem{Base: RSI, Disp: 32}}, "vmovdqu ymm3, ymmword ptr [rsi+0x20]"},
+		{"VPXOR", []Operand{Y0, Y0, Y0}, "vpxor ymm0, ymm0, ymm0"},
+		{"VPSHUFD", []Operand{Y1, Y2, Imm(0x1b)}, "vpshufd ymm1, ymm2, 0x1b"},
+		{"VPERMQ", []Operand{Y1, Y2, Imm(0x4e)}, "vpermq ymm1, ymm2, 0x4e"},
+		{"VPSLLD", []Operand{X1, X2, Imm(3)}, "vpslld xmm1, xmm2, 0x3"},
+		{"VBLENDVPD", []Operand{X1, X2, X3, X4}, "vblendvpd xmm1, xmm2, xmm3, xmm4"},
+		{"VBLENDVPD", []Operand{Y1, Y2, Y3, Y12}, "vblendvpd ymm1, ymm2, ymm3, ymm12"},
+		{"VFMADD231PD", []Operand{Y1, Y2, Y3}, "vfmadd231pd ymm1, ymm2, ymm3"},
+		{"VFMADD231SS", []Operand{X1, X2, Mem{Base: RAX}}, "vfmadd231ss xmm1, xmm2, dword ptr [rax]"},
+		{"VGATHERDPD", []Operand{Y1, Mem{Base: RAX, Index: X2, Scale: 8}, Y3}, "vgatherdpd ymm1, qword ptr [rax+8*xmm2], ymm3"},
+		{"VPGATHERDD", []Operand{Y1, Mem{Base: RAX, Index: Y9, Scale: 4}, Y3}, "vpgatherdd ymm1, dword ptr [rax+4*ymm9], ymm3"},
+		{"VZEROUPPER", nil, "vzeroupper"},
+	}
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			b, err := Encode(c.op, c.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDecode(t, b, 64, c.want)
+		})
+	}
+}
+
+// TestEncodeVEXBytes tests the exact encodings of VEX-prefixed instructions,
+// including that the two-byte VEX prefix is used exactly when it can express
+// the instruction. x86asm does not decode BMI instructions, so they are
+// checked here.
+func TestEncodeVEXBytes(t *testing.T) {
+	cases := []struct {
+		op   string
+		args []Operand
+		want []byte
+	}{
+		{"VADDPD", []Operand{X1, X2, X3}, []byte{0xc5, 0xe9, 0x58, 0xcb}},
+		{"VADDPD", []Operand{Y9, Y2, Y3}, []byte{0xc5, 0x6d, 0x58, 0xcb}},
+		{"VADDPD", []Operand{X1, X2, X11}, []byte{0xc4, 0xc1, 0x69, 0x58, 0xcb}},
+		{"VPERMQ", []Operand{Y1, Y2, Imm(0)}, []byte{0xc4, 0xe3, 0xfd, 0x00, 0xca, 0x00}},
+		// Selectors of VEX forms are unsigned bytes, which must not fall
+		// through to EVEX forms.
+		{"VPERMQ", []Operand{Y1, Y2, Imm(0xd8)}, []byte{0xc4, 0xe3, 0xfd, 0x00, 0xca, 0xd8}},
+		{"VPERMQ", []Operand{Y1, Y2, Imm(-1)}, []byte{0xc4, 0xe3, 0xfd, 0x00, 0xca, 0xff}},
+		{"VPBLENDD", []Operand{Y1, Y2, Y3, Imm(0xf0)}, []byte{0xc4, 0xe3, 0x6d, 0x02, 0xcb, 0xf0}},
+		{"VPERM2I128", []Operand{Y1, Y2, Y3, Imm(0x80)}, []byte{0xc4, 0xe3, 0x6d, 0x46, 0xcb, 0x80}},
+		{"VPCMPEQB", []Operand{Y1, Y2, Y3}, []byte{0xc5, 0xed, 0x74, 0xcb}},
+		{"VBLENDVPD", []Operand{X1, X2, X3, X4}, []byte{0xc4, 0xe3, 0x69, 0x4b, 0xcb, 0x40}},
+		{"ANDN", []Operand{EAX, EBX, ECX}, []byte{0xc4, 0xe2, 0x60, 0xf2, 0xc1}},
+		{"ANDN", []Operand{R8, R9, Mem{Base: R10}}, []byte{0xc4, 0x42, 0xb0, 0xf2, 0x02}},
+		{"BEXTR", []Operand{RAX, RBX, RCX}, []byte{0xc4, 0xe2, 0xf0, 0xf7, 0xc3}},
+		{"MULX", []Operand{RAX, RBX, RCX}, []byte{0xc4, 0xe2, 0xe3, 0xf6, 0xc1}},
+		{"BLSR", []Operand{EAX, EBX}, []byte{0xc4, 0xe2, 0x78, 0xf3, 0xcb}},
+		{"SHLX", []Operand{R11, R12, R13}, []byte{0xc4, 0x42, 0x91, 0xf7, 0xdc}},
+	}
+	for _, c := range cases {
+		b, err := Encode(c.op, c.args...)
+		if err != nil {
+			t.Errorf("%s %v: %v", c.op, c.args, err)
+			continue
+		}
+		if !bytes.Equal(b, c.want) {
+			t.Errorf("%s %v: got %x, want %x", c.op, c.args, b, c.want)
+		}
+	}
+}
+
+// TestEncodeErrors tests that invalid instructions are rejected.
+func TestEncodeEVEX(t *testing.T) {
+	cases := []struct {
+		op   string
+		args []Operand
+		want string
+	}{
+		{"VADDPD", []Operand{Z1, Z2, Z3}, "vaddpd zmm1, zmm2, zmm3"},
+		{"VADDPD", []Operand{X17, X2, X3}, "vaddpd xmm17, xmm2, xmm3"},
+		{"VADDPD", []Operand{Y1, Y25, Y30}, "vaddpd ymm1, ymm25, ymm30"},
+		{"VADDPD", []Operand{Z8, Z16, Z31}, "vaddpd zmm8, zmm16, zmm31"},
+		{"VADDPD", []Operand{Masked{Op: Z0, Mask: K1}, Z0, Z3}, "vaddpd zmm0 {k1}, zmm0, zmm3"},
+		{"VADDPD", []Operand{Masked{Op: Z0, Mask: K7, Zero: true}, Z0, Z3}, "vaddpd zmm0 {k7} {z}, zmm0, zmm3"},
+		{"VADDPD", []Operand{Z1, Z2, Mem{Base: RAX, Disp: 0x40}}, "vaddpd zmm1, zmm2, zmmword ptr [rax+0x40]"},
+		{"VADDPD", []Operand{Z1, Z2, Mem{Base: RAX, Disp: 0x44}}, "vaddpd zmm1, zmm2, zmmword ptr [rax+0x44]"},
+		{"VADDPD", []Operand{Z1, Z2, Mem{Base: RAX, Disp: 8, Broadcast: true}}, "vaddpd zmm1, zmm2, qword ptr [rax+0x8]{1to8}"},
+		{"VADDPS", []Operand{Y1, Y2, Mem{Base: R12, Index: R13, Scale: 4, Broadcast: true}}, "vaddps ymm1, ymm2, dword ptr [r12+4*r13]{1to8}"},
+		{"VADDPD", []Operand{Z1, Z2, Z3, RoundNearest}, "vaddpd zmm1, zmm2, zmm3, {rn-sae}"},
+		{"VADDPD", []Operand{Z1, Z2, Z3, RoundZero}, "vaddpd zmm1, zmm2, zmm3, {rz-sae}"},
+		{"VADDSD", []Operand{X1, X2, X3, RoundUp}, "vaddsd xmm1, xmm2, xmm3, {ru-sae}"},
+		{"VCMPPD", []Operand{K2, Z2, Z3, Imm(1), SAE}, "vcmpltpd k2, zmm2, zmm3, {sae}"},
+		{"VMOVDQU64", []Operand{Masked{Op: Mem{Base: RDI, Disp: -0x80}, Mask: K3}, Z20}, "vmovdqu64 zmmword ptr [rdi-0x80] {k3}, zmm20"},
+		{"VPTERNLOGD", []Operand{Z1, Z2, Z3, Imm(0xff)}, "vpternlogd zmm1, zmm2, zmm3, 0xff"},
+		{"VCOMPRESSPD", []Operand{Masked{Op: Mem{Base: RAX, Disp: 0x40}, Mask: K1}, Z2}, "vcompresspd zmmword ptr [rax+0x40] {k1}, zmm2"},
+		{"VGATHERDPD", []Operand{Masked{Op: Z1, Mask: K1}, Mem{Base: RAX, Index: Y2, Scale: 8}}, "vgatherdpd zmm1 {k1}, qword ptr [rax+8*ymm2]"},
+		{"VPGATHERDD", []Operand{Masked{Op: Z1, Mask: K1}, Mem{Base: RAX, Index: Z18, Scale: 4, Disp: 0x100}}, "vpgatherdd zmm1 {k1}, dword ptr [rax+4*zmm18+0x100]"},
+		{"KANDW", []Operand{K1, K2, K3}, "kandw k1, k2, k3"},
+		{"KMOVW", []Operand{K1, EAX}, "kmovw k1, eax"},
+	}
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			b, err := Encode(c.op, c.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDecode(t, b, 64, c.want)
+		})
+	}
+}
+
+func TestEncodeEVEXBytes(t *testing.T) {
+	cases := []struct {
+		op   string
+		args []Operand
+		want []byte
+	}{
+		{"VADDPD", []Operand{Z1, Z2, Z3}, []byte{0x62, 0xf1, 0xed, 0x48, 0x58, 0xcb}},
+		// disp8*N: 0x40 is one zmmword, 8 one qword when broadcasting.
+		{"VADDPD", []Operand{Z1, Z2, Mem{Base: RAX, Disp: 0x40}}, []byte{0x62, 0xf1, 0xed, 0x48, 0x58, 0x48, 0x01}},
+		{"VADDPD", []Operand{Z1, Z2, Mem{Base: RAX, Disp: 8, Broadcast: true}}, []byte{0x62, 0xf1, 0xed, 0x58, 0x58, 0x48, 0x01}},
+		{"VADDPD", []Operand{Z1, Z2, Mem{Base: RAX, Disp: 8}}, []byte{0x62, 0xf1, 0xed, 0x48, 0x58, 0x88, 0x08, 0x00, 0x00, 0x00}},
+		{"VADDPD", []Operand{Z1, Z2, Z3, RoundDown}, []byte{0x62, 0xf1, 0xed, 0x38, 0x58, 0xcb}},
+		{"VADDPD", []Operand{Masked{Op: Z1, Mask: K5, Zero: true}, Z2, Z3}, []byte{0x62, 0xf1, 0xed, 0xcd, 0x58, 0xcb}},
+	}
+	for _, c := range cases {
+		b, err := Encode(c.op, c.args...)
+		if err != nil {
+			t.Errorf("%s %v: %v", c.op, c.args, err)
+			continue
+		}
+		if !bytes.Equal(b, c.want) {
+			t.Errorf("%s %v: got %x, want %x", c.op, c.args, b, c.want)
+		}
+	}
+}
+
+func TestEncodeShortest(t *testing.T) {
+	cases := []struct {
+		op   string
+		args []Operand
+		want []byte
+	}{
+		{"ADD", []Operand{AL, Imm(1)}, []byte{0x04, 0x01}},
+		{"ADD", []Operand{RAX, Imm(1)}, []byte{0x48, 0x83, 0xc0, 0x01}},
+		{"ADD", []Operand{RAX, Imm(0x1000)}, []byte{0x48, 0x05, 0x00, 0x10, 0x00, 0x00}},
+		{"ADD", []Operand{RCX, Imm(0x1000)}, []byte{0x48, 0x81, 0xc1, 0x00, 0x10, 0x00, 0x00}},
+		{"ADD", []Operand{Mem{Base: RSP, Disp: 8}, R9}, []byte{0x4c, 0x01, 0x4c, 0x24, 0x08}},
+		{"ADD", []Operand{Mem{Base: RAX, Disp: 0x100}, EAX}, []byte{0x01, 0x80, 0x00, 0x01, 0x00, 0x00}},
+		{"MOV", []Operand{RAX, Imm(-1)}, []byte{0x48, 0xc7, 0xc0, 0xff, 0xff, 0xff, 0xff}},
+		{"MOV", []Operand{RAX, Imm(-1 << 40)}, []byte{0x48, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff}},
+		{"MOV", []Operand{EAX, Imm(1)}, []byte{0xb8, 0x01, 0x00, 0x00, 0x00}},
+		{"XCHG", []Operand{ECX, EAX}, []byte{0x91}},
+		{"XCHG", []Operand{EAX, EAX}, []byte{0x87, 0xc0}},
+		{"XCHG", []Operand{RAX, RAX}, []byte{0x48, 0x90}},
+		{"VADDPD", []Operand{X1, X2, X3}, []byte{0xc5, 0xe9, 0x58, 0xcb}},
+	}
+	for _, c := range cases {
+		b, err := Encode(c.op, c.args...)
+		if err != nil {
+			t.Errorf("%s %v: %v", c.op, c.args, err)
+			continue
+		}
+		if !bytes.Equal(b, c.want) {
+			t.Errorf("%s %v: got %x, want %x", c.op, c.args, b, c.want)
+		}
+	}
+}
+
+// TestEncodeMemWidth tests that memory operands without a Size are errors
+// where the width of the operation depends on them, rather than encoding
+// whichever width is shortest.
+func TestEncodeMemWidth(t *testing.T) {
+	cases := []struct {
+		op   string
+		args []Operand
+		want string
+	}{
+		{"MOV", []Operand{Mem{Base: RSP, Index: RAX, Scale: 1, Size: 8}, Imm(1)}, "mov qword ptr [rsp+rax], 0x1"},
+		{"ADD", []Operand{Mem{Base: RAX, Size: 2}, Imm(1)}, "add word ptr [rax], 0x1"},
+		{"INC", []Operand{Mem{Base: RAX, Size: 1}}, "inc byte ptr [rax]"},
+		{"MOVZX", []Operand{EAX, Mem{Base: RAX, Size: 1}}, "movzx eax, byte ptr [rax]"},
+		// Registers determine the width.
+		{"MOV", []Operand{RAX, Mem{Base: RSP}}, "mov rax, qword ptr [rsp]"},
+		{"ADD", []Operand{Mem{Base: RAX}, ECX}, "add dword ptr [rax], ecx"},
+		{"VADDPD", []Operand{Y1, Y2, Mem{Base: RAX}}, "vaddpd ymm1, ymm2, ymmword ptr [rax]"},
+	}
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			b, err := Encode(c.op, c.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDecode(t, b, 64, c.want)
+		})
+	}
+	bad := []struct {
+		op   string
+		args []Operand
+	}{
+		{"MOV", []Operand{Mem{Base: RSP, Index: RAX, Scale: 1}, Imm(1)}},
+		{"ADD", []Operand{Mem{Base: RAX}, Imm(1)}},
+		{"INC", []Operand{Mem{Base: RAX}}},
+		{"MOVZX", []Operand{EAX, Mem{Base: RAX}}},
+	}
+	for _, c := range bad {
+		b, err := Encode(c.op, c.args...)
+		if err == nil {
+			t.Errorf("%s %v encoded as %x", c.op, c.args, b)
+		} else if !strings.Contains(err.Error(), "ambiguous") {
+			t.Errorf("%s %v: wrong error %v", c.op, c.args, err)
+		}
+	}
+}
+
+func TestEncodeForm(t *testing.T) {
+	cases := []struct {
+		form string
+		args []Operand
+		want []byte
+	}{
+		{"ADD r/m64, imm32", []Operand{RAX, Imm(1)}, []byte{0x48, 0x81, 0xc0, 0x01, 0x00, 0x00, 0x00}},
+		{"ADD RAX, imm32", []Operand{RAX, Imm(1)}, []byte{0x48, 0x05, 0x01, 0x00, 0x00, 0x00}},
+		{"ADD r/m64,imm8", []Operand{RAX, Imm(1)}, []byte{0x48, 0x83, 0xc0, 0x01}},
+		{"MOV r64op, imm64", []Operand{RAX, Imm(1)}, []byte{0x48, 0xb8, 0x01, 0, 0, 0, 0, 0, 0, 0}},
+		{"JMP rel32", []Operand{Rel(0)}, []byte{0xe9, 0, 0, 0, 0}},
+		{"VADDPD xmm1{k1}{z}, xmm2, xmm3/m128/m64bcst", []Operand{X1, X2, X3}, []byte{0x62, 0xf1, 0xed, 0x08, 0x58, 0xcb}},
+	}
+	for _, c := range cases {
+		b, err := EncodeForm(c.form, c.args...)
+		if err != nil {
+			t.Errorf("%s %v: %v", c.form, c.args, err)
+			continue
+		}
+		if !bytes.Equal(b, c.want) {
+			t.Errorf("%s %v: got %x, want %x", c.form, c.args, b, c.want)
+		}
+	}
+	errs := []struct {
+		form string
+		args []Operand
+	}{
+		{"", nil},
+		{"FROB r/m64", []Operand{RAX}},
+		{"ADD r/m64, imm64", []Operand{RAX, Imm(1)}},
+		{"ADD r/m64, imm8", []Operand{RAX, Imm(0x1000)}},
+		{"ADD r/m64, imm8", []Operand{EAX, Imm(1)}},
+		{"AAA", nil},
+		{"XCHG r32op, EAX", []Operand{EAX, EAX}},
+	}
+	for _, c := range errs {
+		if b, err := EncodeForm(c.form, c.args...); err == nil {
+			t.Errorf("%q %v encoded as %x", c.form, c.args, b)
+		}
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		op   string
+		args []Operand
+	}{
+		{"unknown", "FROB", nil},
+		{"operand count", "ADD", []Operand{RAX}},
+		{"operand kinds", "ADD", []Operand{RAX, X0}},
+		{"width mismatch", "ADD", []Operand{RAX, EBX}},
+		{"high byte with REX", "MOV", []Operand{AH, SIL}},
+		{"rsp index", "MOV", []Operand{RAX, Mem{Base: RAX, Index: RSP, Scale: 1}}},
+		{"bad scale", "MOV", []Operand{RAX, Mem{Base: RAX, Index: RBX, Scale: 3}}},
+		{"mixed address", "MOV", []Operand{RAX, Mem{Base: RAX, Index: EBX, Scale: 1}}},
+		{"rel8 range", "JRCXZ", []Operand{Rel(200)}},
+		{"vex imm8 range", "VPERMQ", []Operand{Y1, Y2, Imm(0x100)}},
+		{"invalid in 64-bit mode", "AAA", nil},
+		{"memory width", "MOVZX", []Operand{EAX, Mem{Base: RAX, Size: 4}}},
+		{"rip index", "MOV", []Operand{RAX, Mem{Base: RIP, Index: RBX, Scale: 1}}},
+		{"register class", "PADDB", []Operand{M1, X1}},
+		{"far pointer in 64-bit mode", "LJMP", []Operand{FarPtr{Seg: 8, Off: 0x1000}}},
+		{"k0 mask", "VADDPD", []Operand{Masked{Op: Z0, Mask: K0}, Z1, Z2}},
+		{"unmasked gather", "VGATHERDPD", []Operand{Z1, Mem{Base: RAX, Index: Y2, Scale: 8}}},
+		{"rounding with memory", "VADDPD", []Operand{Z1, Z2, Mem{Base: RAX}, RoundNearest}},
+		{"rounding without support", "VPADDD", []Operand{Z1, Z2, Z3, RoundNearest}},
+		{"sae for rounding", "VADDPD", []Operand{Z1, Z2, Z3, SAE}},
+		{"zeroing memory", "VMOVDQU64", []Operand{Masked{Op: Mem{Base: RAX}, Mask: K1, Zero: true}, Z1}},
+		{"broadcast width", "VADDPD", []Operand{Z1, Z2, Mem{Base: RAX, Size: 4, Broadcast: true}}},
+		{"no broadcast", "VPSHUFB", []Operand{Z1, Z2, Mem{Base: RAX, Broadcast: true}}},
+		{"high register without evex", "PADDB", []Operand{X16, X1}},
+		{"mask without evex", "ADD", []Operand{Masked{Op: RAX, Mask: K1}, RBX}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if b, err := Encode(c.op, c.args...); err == nil {
+				t.Errorf("%s %v encoded as %x", c.op, c.args, b)
+			}
+		})
+	}
+}
+
+// TestEncode32 tests encoding instructions in 32-bit mode.
+func TestEncode32(t *testing.T) {
+	e := &Encoder{Features: AllFeatures, Mode: 32}
+	cases := []struct {
+		op   string
+		args []Operand
+		want string
+	}{
+		{"ADD", []Operand{EAX, Imm(1)}, "add eax, 0x1"},
+		{"ADD", []Operand{Mem{Base: ESP, Disp: 8}, ECX}, "add dword ptr [esp+0x8], ecx"},
+		{"MOV", []Operand{EDX, Mem{Base: EBP, Index: ESI, Scale: 4}}, "mov edx, dword ptr [ebp+4*esi]"},
+		{"MOV", []Operand{EAX, Mem{Disp: 0x1000}}, "mov eax, dword ptr [0x1000]"},
+		{"MOV", []Operand{ECX, Mem{Index: EDX, Scale: 8, Disp: 0x100}}, "mov ecx, dword ptr [8*edx+0x100]"},
+		{"MOV", []Operand{AX, Mem{Base: EBX}}, "mov ax, word ptr [ebx]"},
+		{"MOV", []Operand{AH, Imm(1)}, "mov ah, 0x1"},
+		{"INC", []Operand{ECX}, "inc ecx"},
+		{"DEC", []Operand{DI}, "dec di"},
+		{"PUSH", []Operand{EBX}, "push ebx"},
+		{"PUSH", []Operand{Imm(0x1000)}, "push 0x1000"},
+		{"POP", []Operand{Mem{Base: EAX, Size: 4}}, "pop dword ptr [eax]"},
+		{"CALL", []Operand{EAX}, "call eax"},
+		{"JMP", []Operand{Rel(0x1000)}, "jmp .+0x1000"},
+		{"JNE", []Operand{Rel(-0x200)}, "jnz .-0x200"},
+		{"JECXZ", []Operand{Rel(4)}, "jecxz .+0x4"},
+		{"JCXZ", []Operand{Rel(4)}, "addr16 jcxz .+0x4"},
+		{"LJMP", []Operand{FarPtr{Seg: 8, Off: 0x1000}}, "jmp far 0x1000, 0x8"},
+		{"AAA", nil, "aaa"},
+		{"PUSHAD", nil, "pushad"},
+		{"MOVDQU", []Operand{X7, Mem{Base: EAX}}, "movdqu xmm7, xmmword ptr [eax]"},
+		{"VADDPD", []Operand{Y1, Y2, Mem{Base: ECX, Disp: 0x20}}, "vaddpd ymm1, ymm2, ymmword ptr [ecx+0x20]"},
+		{"VPXORD", []Operand{Masked{Op: Z0, Mask: K1}, Z6, Z7}, "vpxord zmm0 {k1}, zmm6, zmm7"},
+	}
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			b, err := e.Encode(c.op, c.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDecode(t, b, 32, c.want)
+		})
+	}
+	if b, err := e.Encode("MOV", AL, Mem{Disp: 0x1000}); err != nil || !bytes.Equal(b, []byte{0xa0, 0x00, 0x10, 0x00, 0x00}) {
+		t.Errorf("MOV AL, moffs8 encoded as %x, %v", b, err)
+	}
+
+	bad := []struct {
+		name string
+		op   string
+		args []Operand
+	}{
+		{"64-bit register", "ADD", []Operand{RAX, Imm(1)}},
+		{"extended register", "ADD", []Operand{R8D, EAX}},
+		{"REX byte register", "MOV", []Operand{SIL, Imm(1)}},
+		{"extended vector", "MOVDQU", []Operand{X8, X0}},
+		{"extended evex vector", "VPXORD", []Operand{Z16, Z0, Z1}},
+		{"64-bit address", "MOV", []Operand{EAX, Mem{Base: RAX}}},
+		{"rip-relative", "MOV", []Operand{EAX, Mem{Base: RIP}}},
+		{"16-bit address", "MOV", []Operand{EAX, Mem{Base: BX}}},
+		{"extended control register", "MOV", []Operand{EAX, CR(8)}},
+		{"invalid in 32-bit mode", "SWAPGS", nil},
+		{"rex.w form", "CMPXCHG16B", []Operand{Mem{Base: EAX}}},
+	}
+	for _, c := range bad {
+		t.Run(c.name, func(t *testing.T) {
+			if b, err := e.Encode(c.op, c.args...); err == nil {
+				t.Errorf("%s %v encoded as %x", c.op, c.args, b)
+			}
+		})
+	}
+	if b, err := e.EncodeForm("JMP rel16", Rel(0)); err == nil {
+		t.Errorf("JMP rel16 encoded as %x", b)
+	}
+	if b, err := (&Encoder{Mode: 16}).Encode("NOP"); err == nil {
+		t.Errorf("NOP in 16-bit mode encoded as %x", b)
+	}
+}
+
+// TestEncodePrefixes tests prefixes and segment overrides.
+func TestEncodePrefixes(t *testing.T) {
+	cases := []struct {
+		op   string
+		args []Operand
+		want string
+	}{
+		{"ADD", []Operand{LOCK, Mem{Base: RDI}, EAX}, "lock add dword ptr [rdi], eax"},
+		{"XADD", []Operand{LOCK, Mem{Base: RSI, Disp: 8}, RCX}, "lock xadd qword ptr [rsi+0x8], rcx"},
+		{"CMPXCHG", []Operand{LOCK, Mem{Base: RBX}, EDX}, "lock cmpxchg dword ptr [rbx], edx"},
+		{"CMPXCHG16B", []Operand{LOCK, Mem{Base: RDI}}, "lock cmpxchg16b xmmword ptr [rdi]"},
+		{"INC", []Operand{LOCK, Mem{Base: RAX, Size: 8}}, "lock inc qword ptr [rax]"},
+		{"XCHG", []Operand{LOCK, Mem{Base: RDX}, EAX}, "lock xchg dword ptr [rdx], eax"},
+		{"MOVSB", []Operand{REP}, "rep movsb byte ptr [rdi], byte ptr [rsi]"},
+		{"STOSQ", []Operand{REP}, "rep stosq qword ptr [rdi]"},
+		{"SCASB", []Operand{REPN}, "repne scasb byte ptr [rdi]"},
+		{"CMPSD", []Operand{REP}, "rep cmpsd dword ptr [rsi], dword ptr [rdi]"},
+		{"MOVSB", []Operand{ADDRSIZE}, "movsb byte ptr [edi], byte ptr [esi]"},
+		{"STOSD", []Operand{REP, ADDRSIZE}, "rep stosd dword ptr [edi]"},
+		{"MOVSB", []Operand{SegPrefix(FS)}, "movsb byte ptr [rdi], byte ptr fs:[rsi]"},
+		{"CMPSB", []Operand{REP, SegPrefix(GS)}, "rep cmpsb byte ptr gs:[rsi], byte ptr [rdi]"},
+		{"LODSQ", []Operand{SegPrefix(FS), ADDRSIZE}, "lodsq qword ptr fs:[esi]"},
+		{"MOV", []Operand{RAX, Mem{Seg: FS, Disp: -8}}, "mov rax, qword ptr fs:[0xfffffff8]"},
+		{"MOV", []Operand{Mem{Seg: GS, Base: RCX, Disp: 0x30}, RDX}, "mov qword ptr gs:[rcx+0x30], rdx"},
+		{"ADD", []Operand{LOCK, Mem{Seg: GS, Base: RAX, Size: 4}, Imm(1)}, "lock add dword ptr gs:[rax], 0x1"},
+		{"MOV", []Operand{AL, Mem{Seg: FS, Disp: 0x10}}, "mov al, byte ptr fs:[0x10]"},
+	}
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			b, err := Encode(c.op, c.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDecode(t, b, 64, c.want)
+		})
+	}
+	e32 := &Encoder{Mode: 32}
+	if b, err := e32.Encode("MOVSB", REP, ADDRSIZE); err != nil {
+		t.Error(err)
+	} else {
+		checkDecode(t, b, 32, "rep movsb byte ptr [di], byte ptr [si]")
+	}
+	if b, err := e32.Encode("MOV", EAX, Mem{Seg: GS, Disp: 0x14}); err != nil {
+		t.Error(err)
+	} else {
+		checkDecode(t, b, 32, "mov eax, dword ptr gs:[0x14]")
+	}
+	if b, err := EncodeForm("ADD r/m32, imm32", LOCK, Mem{Base: RAX}, Imm(1)); err != nil {
+		t.Error(err)
+	} else {
+		checkDecode(t, b, 64, "lock add dword ptr [rax], 0x1")
+	}
+
+	bad := []struct {
+		name string
+		op   string
+		args []Operand
+	}{
+		{"lock without lockable", "MOV", []Operand{LOCK, Mem{Base: RAX}, EAX}},
+		{"lock with register destination", "ADD", []Operand{LOCK, EAX, Mem{Base: RAX}}},
+		{"lock twice", "ADD", []Operand{LOCK, LOCK, Mem{Base: RAX}, EAX}},
+		{"rep without string", "ADD", []Operand{REP, EAX, EBX}},
+		{"repn with movs", "MOVSB", []Operand{REPN}},
+		{"rep with repn", "SCASB", []Operand{REP, REPN}},
+		{"rep with xlat", "XLATB", []Operand{REP}},
+		{"rep with sse", "MOVSD", []Operand{REP, X0, X1}},
+		{"addrsize with modrm", "ADD", []Operand{ADDRSIZE, Mem{Base: RAX}, EAX}},
+		{"unknown prefix", "NOP", []Operand{Prefix(0x66)}},
+		{"segment prefix without string", "NOP", []Operand{SegPrefix(CS)}},
+		{"segment prefix with stos", "STOSB", []Operand{SegPrefix(FS)}},
+		{"segment prefix with scas", "SCASB", []Operand{REP, SegPrefix(FS)}},
+		{"segment prefixes", "MOVSB", []Operand{SegPrefix(FS), SegPrefix(GS)}},
+		{"segment register", "MOV", []Operand{EAX, Mem{Seg: RAX, Base: RBX}}},
+	}
+	for _, c := range bad {
+		t.Run(c.name, func(t *testing.T) {
+			if b, err := Encode(c.op, c.args...); err == nil {
+				t.Errorf("%s %v encoded as %x", c.op, c.args, b)
+			}
+		})
+	}
+}
+
+// TestEncodeSETcc tests that SETcc, which x86.csv lists as /r, uses 0 for
+// ModRM.reg.
+func TestEncodeSETcc(t *testing.T) {
+	b, err := Encode("SETNE", CL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x0f, 0x95, 0xc1}; !bytes.Equal(b, want) {
+		t.Errorf("got %x, want %x", b, want)
+	}
+}
+
+// TestLookup tests that every mnemonic in the table is found by name.
+func TestLookup(t *testing.T) {
+	n := 0
+	for m := mnemonic(0); int(m) < len(tableIdcs)-1; m++ {
+		rows := lookup(m.String())
+		if len(rows) == 0 {
+			t.Errorf("no rows for %v", m)
+		}
+		for _, r := range rows {
+			if r.op != m {
+				t.Errorf("row for %v has mnemonic %v", m, r.op)
+			}
+		}
+		n += len(rows)
+	}
+	if n != len(table) {
+		t.Errorf("found %d rows, but the table has %d", n, len(table))
+	}
+	for _, op := range []string{"", "A", "ADDX", "add", "JA JA", "\xff"} {
+		if rows := lookup(op); rows != nil {
+			t.Errorf("%q has %d rows", op, len(rows))
+		}
+		if Known(op) {
+			t.Errorf("%q is known", op)
+		}
+	}
+}
+
+// TestTableSize checks the size of the static data describing the table,
+// which dominates the contribution of x86enc to binary size.
+func TestTableSize(t *testing.T) {
+	n := unsafe.Sizeof(table) + unsafe.Sizeof(kindTable) + unsafe.Sizeof(featureTable) +
+		uintptr(len(mnemonicText)) + unsafe.Sizeof(mnemonicIdcs) + unsafe.Sizeof(tableIdcs) +
+		unsafe.Sizeof(mnemonicSeeds) + unsafe.Sizeof(mnemonicSlots)
+	t.Logf("%d rows of %d bytes, %d bytes in all", len(table), unsafe.Sizeof(table[0]), n)
+	if row := unsafe.Sizeof(table[0]); row > 32 {
+		t.Errorf("rows are %d bytes, want at most 32", row)
+	}
+	if n > 192<<10 {
+		t.Errorf("table data is %d bytes, want at most %d", n, 192<<10)
+	}
+}
@@ -0,0 +1,139 @@
+package x86enc
+
+// encoding is the precompiled interpretation of the encoding column of a
+// table row, such as "REX.W + 81 /2 id".
+type encoding struct {
+	// prefix holds mandatory prefixes which precede REX, like 66 and F3, in
+	// its first nprefix bytes.
+	prefix  [2]byte
+	nprefix uint8
+	// opcode holds the opcode bytes in its first nopcode bytes.
+	opcode  [3]byte
+	nopcode uint8
+	// modrm is the ModRM reg field for /digit forms, modrmR for /r forms, or
+	// modrmNone if the form has no ModRM byte.
+	modrm int8
+	// imm holds the sizes of immediates and code offsets in order, followed
+	// by zeros.
+	imm [2]uint8
+	// pp and mmmmm are the implied prefix and opcode map of VEX- and
+	// EVEX-encoded forms: pp is 0 for none, 1 for 66, 2 for F3, and 3 for F2,
+	// and mmmmm is 1 for 0F, 2 for 0F38, and 3 for 0F3A.
+	pp, mmmmm uint8
+	// disp8 is the base 2 logarithm of the scale of compressed 8-bit
+	// displacements in EVEX-encoded forms when the memory operand is not
+	// broadcast.
+	disp8 uint8
+	flags encFlag
+}
+
+const (
+	modrmNone = -1
+	modrmR    = 8
+)
+
+// encFlag holds boolean properties of an encoding.
+type encFlag uint16
+
+const (
+	// encREX is set if the form requires a REX prefix even if it is
+	// otherwise empty; encREXW is set if it requires REX.W.
+	encREX encFlag = 1 << iota
+	encREXW
+	// encPlus is set if a register number is added to the last opcode byte.
+	encPlus
+	// encIs4 is set if a register is encoded in the high bits of an
+	// immediate.
+	encIs4
+	// encVEX is set for VEX-encoded forms, and encEVEX for EVEX-encoded
+	// forms, which share the VEX fields below.
+	encVEX
+	encEVEX
+	// vexL is VEX.L; vexLIG is set if the processor ignores it. evexL2 is
+	// EVEX.L', set for 512-bit forms.
+	vexL
+	vexLIG
+	evexL2
+	// vexW is VEX.W; vexWIG is set if the processor ignores it.
+	vexW
+	vexWIG
+	// vexV is set if VEX.vvvv encodes an operand.
+	vexV
+)
+
+// role is the part of an encoding which holds an operand.
+type role uint8
+
+const (
+	roleNone role = iota
+	roleReg
+	roleRM
+	roleVVVV
+	roleOpcode
+	roleImm
+	roleIs4
+)
+
+// rowFlag holds the validity and tags of a table row.
+type rowFlag uint16
+
+const (
+	// valid32 and valid64 are set if the form is valid in 32- and 64-bit
+	// mode.
+	valid32 rowFlag = 1 << iota
+	valid64
+	tagPseudo
+	tagPseudo64
+	tagOperand16
+	tagOperand32
+	tagOperand64
+	// tagRegOnly and tagMemOnly restrict ModRM.rm to registers or memory.
+	tagRegOnly
+	tagMemOnly
+	tagAddress16
+	tagAddress32
+	tagAddress64
+)
+
+// mnemonic is an index into the mnemonics of the table.
+type mnemonic uint16
+
+func (m mnemonic) String() string {
+	return mnemonicText[mnemonicIdcs[m]:mnemonicIdcs[m+1]]
+}
+
+// rows returns the forms of the mnemonic.
+func (m mnemonic) rows() []instruction {
+	return table[tableIdcs[m]:tableIdcs[m+1]]
+}
+
+// index returns the index in the table of a row.
+func (r *instruction) index() int {
+	for i := range r.op.rows() {
+		if &table[int(tableIdcs[r.op])+i] == r {
+			return int(tableIdcs[r.op]) + i
+		}
+	}
+	panic("x86enc: row not in table")
+}
+
+// lookup returns the rows of the table for a mnemonic using the perfect hash
+// generated by mkenc.
+func lookup(op string) []instruction {
+	d := mnemonicSeeds[hash(op, 0)&uint32(len(mnemonicSeeds)-1)]
+	m := mnemonicSlots[hash(op, uint32(d)+1)&uint32(len(mnemonicSlots)-1)]
+	if m == 0 || mnemonic(m-1).String() != op {
+		return nil
+	}
+	return mnemonic(m - 1).rows()
+}
+
+// hash is FNV-1a with a seed. It must agree with hash in mkenc.
+func hash(s string, seed uint32) uint32 {
+	h := 2166136261 ^ seed*16777619
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
@@ -0,0 +1,152 @@
+package x86enc
+
+import (
+	"strings"
+
+	"golang.org/x/sys/cpu"
+)
+
+// Feature is a set of processor features, as reported by CPUID, which
+// instructions can require. The features of a processor are combined with |.
+type Feature uint64
+
+const (
+	FeatureMMX Feature = 1 << iota
+	FeatureSSE
+	FeatureSSE2
+	FeatureSSE3
+	FeatureSSSE3
+	FeatureSSE41
+	FeatureSSE42
+	FeatureAES
+	FeatureCLMUL
+	FeatureAVX
+	FeatureAVX2
+	FeatureFMA
+	FeatureF16C
+	FeatureBMI1
+	FeatureBMI2
+	FeatureLZCNT
+	FeaturePRFCHW
+	FeatureRDRAND
+	FeatureFSGSBASE
+	FeatureINVPCID
+	FeatureHLE
+	FeatureRTM
+	FeatureXSAVEOPT
+	FeatureAVX512F
+	FeatureAVX512VL
+	FeatureAVX512BW
+	FeatureAVX512DQ
+	FeatureAVX512CD
+	FeatureAVX512ER
+	FeatureAVX512PF
+	FeatureAVX512IFMA
+	FeatureAVX512VBMI
+	FeatureAVX512VBMI2
+	FeatureAVX512VNNI
+	FeatureAVX512BITALG
+	FeatureAVX512VPOPCNTDQ
+	FeatureAVX512GFNI
+	FeatureAVX512VAES
+	FeatureAVX512VPCLMULQDQ
+
+	// AllFeatures is the set of every feature.
+	AllFeatures = FeatureAVX512VPCLMULQDQ<<1 - 1
+)
+
+var featureStrings = [...]string{
+	"MMX", "SSE", "SSE2", "SSE3", "SSSE3", "SSE41", "SSE42", "AES", "CLMUL",
+	"AVX", "AVX2", "FMA", "F16C", "BMI1", "BMI2", "LZCNT", "PRFCHW", "RDRAND",
+	"FSGSBASE", "INVPCID", "HLE", "RTM", "XSAVEOPT", "AVX512F", "AVX512VL",
+	"AVX512BW", "AVX512DQ", "AVX512CD", "AVX512ER", "AVX512PF", "AVX512IFMA",
+	"AVX512VBMI", "AVX512VBMI2", "AVX512VNNI", "AVX512BITALG",
+	"AVX512VPOPCNTDQ", "AVX512GFNI", "AVX512VAES", "AVX512VPCLMULQDQ",
+}
+
+// String returns the names of the features in the set separated by |, or
+// "none" if the set is empty.
+func (f Feature) String() string {
+	if f == 0 {
+		return "none"
+	}
+	var s []string
+	for i, n := range featureStrings {
+		if f&(1<<uint(i)) != 0 {
+			s = append(s, n)
+		}
+	}
+	return strings.Join(s, "|")
+}
+
+// HostFeatures returns the features of the processor running the program, as
+// golang.org/x/sys/cpu reports them. It does not report F16C, LZCNT, PRFCHW,
+// FSGSBASE, INVPCID, RTM, HLE, or XSAVEOPT, so they are never included, nor
+// is anything on processors other than x86.
+func HostFeatures() Feature {
+	var f Feature
+	for _, h := range []struct {
+		has bool
+		f   Feature
+	}{
+		// Every processor with SSE2 has MMX and SSE.
+		{cpu.X86.HasSSE2, FeatureMMX | FeatureSSE | FeatureSSE2},
+		{cpu.X86.HasSSE3, FeatureSSE3},
+		{cpu.X86.HasSSSE3, FeatureSSSE3},
+		{cpu.X86.HasSSE41, FeatureSSE41},
+		{cpu.X86.HasSSE42, FeatureSSE42},
+		{cpu.X86.HasAES, FeatureAES},
+		{cpu.X86.HasPCLMULQDQ, FeatureCLMUL},
+		{cpu.X86.HasAVX, FeatureAVX},
+		{cpu.X86.HasAVX2, FeatureAVX2},
+		{cpu.X86.HasFMA, FeatureFMA},
+		{cpu.X86.HasBMI1, FeatureBMI1},
+		{cpu.X86.HasBMI2, FeatureBMI2},
+		{cpu.X86.HasRDRAND, FeatureRDRAND},
+		{cpu.X86.HasAVX512F, FeatureAVX512F},
+		{cpu.X86.HasAVX512VL, FeatureAVX512VL},
+		{cpu.X86.HasAVX512BW, FeatureAVX512BW},
+		{cpu.X86.HasAVX512DQ, FeatureAVX512DQ},
+		{cpu.X86.HasAVX512CD, FeatureAVX512CD},
+		{cpu.X86.HasAVX512ER, FeatureAVX512ER},
+		{cpu.X86.HasAVX512PF, FeatureAVX512PF},
+		{cpu.X86.HasAVX512IFMA, FeatureAVX512IFMA},
+		{cpu.X86.HasAVX512VBMI, FeatureAVX512VBMI},
+		{cpu.X86.HasAVX512VBMI2, FeatureAVX512VBMI2},
+		{cpu.X86.HasAVX512VNNI, FeatureAVX512VNNI},
+		{cpu.X86.HasAVX512BITALG, FeatureAVX512BITALG},
+		{cpu.X86.HasAVX512VPOPCNTDQ, FeatureAVX512VPOPCNTDQ},
+		{cpu.X86.HasAVX512GFNI, FeatureAVX512GFNI},
+		{cpu.X86.HasAVX512VAES, FeatureAVX512VAES},
+		{cpu.X86.HasAVX512VPCLMULQDQ, FeatureAVX512VPCLMULQDQ},
+	} {
+		if h.has {
+			f |= h.f
+		}
+	}
+	return f
+}
+
+// featureSet is the features a row requires: all of all, and any one of any
+// if it is not empty.
+type featureSet struct {
+	all, any Feature
+}
+
+// in returns whether a processor with features f has the set.
+func (s featureSet) in(f Feature) bool {
+	return s.all&^f == 0 && (s.any == 0 || s.any&f != 0)
+}
+
+// missing describes the features of the set which f lacks.
+func (s featureSet) missing(f Feature) string {
+	if m := s.all &^ f; m != 0 {
+		return m.String()
+	}
+	return strings.ReplaceAll(s.any.String(), "|", " or ")
+}
+
+// features returns the features the row requires.
+func (r *instruction) features() featureSet {
+	return featureTable[r.feature]
+}
@@ -0,0 +1,165 @@
+package x86enc
+
+import (
+	"encoding/hex"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestEncoderFeatures checks that an Encoder uses only forms its target
+// supports.
+func TestEncoderFeatures(t *testing.T) {
+	avx := &Encoder{Features: FeatureMMX | FeatureSSE | FeatureSSE2 | FeatureAVX}
+	ok := []struct {
+		e    *Encoder
+		op   string
+		args []Operand
+		want string
+	}{
+		{&Encoder{}, "ADD", []Operand{RAX, Imm(1)}, "add rax, 0x1"},
+		{avx, "VADDPD", []Operand{X0, X1, X2}, "vaddpd xmm0, xmm1, xmm2"},
+		{avx, "VADDPD", []Operand{Y9, Y1, Mem{Base: RAX}}, "vaddpd ymm9, ymm1, ymmword ptr [rax]"},
+		{&Encoder{Features: FeatureRTM}, "XTEST", nil, "xtest"},
+	}
+	for _, c := range ok {
+		t.Run(c.want, func(t *testing.T) {
+			b, err := c.e.Encode(c.op, c.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDecode(t, b, 64, c.want)
+		})
+	}
+	bad := []struct {
+		e    *Encoder
+		op   string
+		args []Operand
+		want string
+	}{
+		{&Encoder{}, "ADDPD", []Operand{X0, X1}, "requires SSE2"},
+		{avx, "VADDPD", []Operand{Z0, Z1, Z2}, "requires AVX512F"},
+		{avx, "VADDPD", []Operand{X17, X1, X2}, "requires AVX512F|AVX512VL"},
+		{&Encoder{Features: FeatureAVX512F}, "VADDPD", []Operand{Y17, Y1, Y2}, "requires AVX512VL"},
+		{avx, "VFMADD132PD", []Operand{X0, X1, X2}, "requires FMA"},
+		{&Encoder{}, "XTEST", nil, "requires HLE or RTM"},
+	}
+	for _, c := range bad {
+		t.Run(c.want, func(t *testing.T) {
+			b, err := c.e.Encode(c.op, c.args...)
+			if err == nil {
+				t.Fatalf("%s %v encoded as %x", c.op, c.args, b)
+			}
+			if !strings.Contains(err.Error(), c.want) {
+				t.Errorf("%s %v: wrong error %q", c.op, c.args, err)
+			}
+		})
+	}
+	if _, err := avx.EncodeForm("VADDPD zmm1{k1}{z}, zmm2, zmm3/m512/m64bcst{er}", Z0, Z1, Z2); err == nil || !strings.Contains(err.Error(), "requires AVX512F") {
+		t.Errorf("EncodeForm without AVX512F: wrong error %v", err)
+	}
+}
+
+// TestRequires checks the features reported for instructions.
+func TestRequires(t *testing.T) {
+	cases := []struct {
+		e    *Encoder
+		op   string
+		args []Operand
+		want Feature
+	}{
+		{anyCPU, "ADD", []Operand{RAX, RBX}, 0},
+		{anyCPU, "PSHUFD", []Operand{X0, X1, Imm(0)}, FeatureSSE2},
+		{anyCPU, "VADDPD", []Operand{X0, X1, X2}, FeatureAVX},
+		{anyCPU, "VADDPD", []Operand{X0, X1, X17}, FeatureAVX512F | FeatureAVX512VL},
+		{anyCPU, "VPADDB", []Operand{Z0, Z1, Z2}, FeatureAVX512BW},
+		{anyCPU, "XTEST", nil, FeatureHLE | FeatureRTM},
+		{&Encoder{Features: FeatureRTM}, "XTEST", nil, FeatureRTM},
+	}
+	for _, c := range cases {
+		got, err := c.e.Requires(c.op, c.args...)
+		if err != nil {
+			t.Errorf("%s %v: %v", c.op, c.args, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s %v requires %v, want %v", c.op, c.args, got, c.want)
+		}
+	}
+	if _, err := Requires("VADDPD", RAX); err == nil {
+		t.Errorf("Requires with mismatched operands succeeded")
+	}
+}
+
+// TestRequiresCode checks the features reported for decoded code.
+func TestRequiresCode(t *testing.T) {
+	var code []byte
+	for _, s := range []string{
+		"4801c8",       // add rax, rcx
+		"660f70c11b",   // pshufd xmm0, xmm1, 0x1b
+		"c5f158c2",     // vaddpd xmm0, xmm1, xmm2
+		"62f1f50858c2", // vaddpd xmm0, xmm1, xmm2 with EVEX
+		"62f1f54858c2", // vaddpd zmm0, zmm1, zmm2
+		"c3",           // ret
+	} {
+		code = append(code, unhex(t, s)...)
+	}
+	got, err := RequiresCode(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := FeatureSSE2 | FeatureAVX | FeatureAVX512F | FeatureAVX512VL; got != want {
+		t.Errorf("code requires %v, want %v", got, want)
+	}
+	for _, s := range []string{
+		"c4e278f2c0", // andn eax, eax, eax, which x86asm cannot decode
+		"62",         // truncated EVEX prefix
+		"62f1",
+	} {
+		if f, err := RequiresCode(unhex(t, s)); err == nil {
+			t.Errorf("%s requires %v", s, f)
+		}
+	}
+}
+
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestHostFeatures(t *testing.T) {
+	f := HostFeatures()
+	t.Logf("host features: %v", f)
+	if runtime.GOARCH == "amd64" && f&FeatureSSE2 == 0 {
+		t.Errorf("host has no SSE2")
+	}
+	if f&^AllFeatures != 0 {
+		t.Errorf("host has unknown features %#x", uint64(f&^AllFeatures))
+	}
+	if hostTarget.Features != f || hostTarget.Mode != 0 {
+		t.Errorf("package functions target %v in mode %d", hostTarget.Features, hostTarget.Mode)
+	}
+}
+
+func TestFeatureString(t *testing.T) {
+	cases := []struct {
+		f    Feature
+		want string
+	}{
+		{0, "none"},
+		{FeatureMMX, "MMX"},
+		{FeatureSSE41 | FeatureAVX512VPCLMULQDQ, "SSE41|AVX512VPCLMULQDQ"},
+	}
+	for _, c := range cases {
+		if got := c.f.String(); got != c.want {
+			t.Errorf("%#x: got %q, want %q", uint64(c.f), got, c.want)
+		}
+	}
+	if n := len(featureStrings); AllFeatures != 1<<uint(n)-1 {
+		t.Errorf("%d feature names for %v", n, AllFeatures)
+	}
+}
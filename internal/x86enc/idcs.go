@@ -1,988 +1,472 @@
+// Code generated by mkenc; DO NOT EDIT.
+//
+// Sources:
+//	x86.csv sha256:fcbb870b925e607abec992bde67d2c9fab251d70c3151d1541981b5fe9a182b1
+//	evex.csv sha256:3c2ee39d0f826ca169fc168aa33d1b2bc57b02abcdb37693c4e490b5802b4552
+//	meta.csv sha256:5f5f0318ecfe764d00f5af4cbbefdd00ec2b101907117a4ad09f2f2b36e885eb
+
 package x86enc
 
-// Code generated by go generate; DO NOT EDIT
+const mnemonicText = "AAAAADAAMAASADCADDADDPDADDPSADDSDADDSSADDSUBPDADDSUBPSAESDECAESDECLASTAESENCAESENCLASTAESIMCAESKEYGENASSISTANDANDNANDNPDANDNPSANDPDANDPSARPLBEXTRBLENDPDBLENDPSBLENDVPDBLENDVPSBLSIBLSMSKBLSRBOUNDBSFBSRBSWAPBTBTCBTRBTSBZHICALLCBWCDQCDQECLCCLDCLFLUSHCLICLTSCMCCMOVACMOVAECMOVBCMOVBECMOVECMOVGCMOVGECMOVLCMOVLECMOVNECMOVNOCMOVNPCMOVNSCMOVOCMOVPCMOVSCMPCMPPDCMPPSCMPSBCMPSDCMPSD_XMMCMPSQCMPSSCMPSWCMPXCHGCMPXCHG16BCMPXCHG8BCOMISDCOMISSCPUIDCQOCRC32CVTDQ2PDCVTDQ2PSCVTPD2DQCVTPD2PICVTPD2PSCVTPI2PDCVTPI2PSCVTPS2DQCVTPS2PDCVTPS2PICVTSD2SICVTSD2SSCVTSI2SDCVTSI2SSCVTSS2SDCVTSS2SICVTTPD2DQCVTTPD2PICVTTPS2DQCVTTPS2PICVTTSD2SICVTTSS2SICWDCWDEDAADASDECDIVDIVPDDIVPSDIVSDDIVSSDPPDDPPSEMMSENTEREXTRACTPSF2XM1FABSFADDFADDPFBLDFBSTPFCHSFCMOVBFCMOVBEFCMOVEFCMOVNBFCMOVNBEFCMOVNEFCMOVNUFCMOVUFCOMFCOMIFCOMIPFCOMPFCOMPPFCOSFDECSTPFDIVFDIVPFDIVRFDIVRPFFREEFFREEPFIADDFICOMFICOMPFIDIVFIDIVRFILDFIMULFINCSTPFISTFISTPFISTTPFISUBFISUBRFLDFLD1FLDCWFLDENVFLDL2EFLDL2TFLDLG2FLDPIFMULFMULPFNCLEXFNINITFNOPFNSAVEFNSTCWFNSTENVFNSTSWFPATANFPREMFPREM1FPTANFRNDINTFRSTORFSCALEFSINFSINCOSFSQRTFSTFSTPFSUBFSUBPFSUBRFSUBRPFTSTFUCOMFUCOMIFUCOMIPFUCOMPFUCOMPPFWAITFXAMFXCHFXRSTORFXRSTOR64FXSAVEFXSAVE64FXTRACTFYL2XFYL2XP1HADDPDHADDPSHLTHSUBPDHSUBPSICEBPIDIVIMULININCINSBINSDINSERTPSINSWINTINTOINVDINVLPGINVPCIDIRETIRETDIRETQJAJAEJBJBEJCXZJEJECXZJGJGEJLJLEJMPJNEJNOJNPJNSJOJPJRCXZJSKADDBKADDDKADDQKADDWKANDBKANDDKANDNBKANDNDKANDNQKANDNWKANDQKANDWKMOVBKMOVDKMOVQKMOVWKNOTBKNOTDKNOTQKNOTWKORBKORDKORQKORTESTBKORTESTDKORTESTQKORTESTWKORWKSHIFTLBKSHIFTLDKSHIFTLQKSHIFTLWKSHIFTRBKSHIFTRDKSHIFTRQKSHIFTRWKTESTBKTESTDKTESTQKTESTWKUNPCKBWKUNPCKDQKUNPCKWDKXNORBKXNORDKXNORQKXNORWKXORBKXORDKXORQKXORWLAHFLARLCALLLDDQULDMXCSRLDSLEALEAVELESLFENCELFSLGDTLGSLIDTLJMPLLDTLMSWLODSBLODSDLODSQLODSWLOOPLOOPELOOPNELRETLSLLSSLTRLZCNTMASKMOVDQUMASKMOVQMAXPDMAXPSMAXSDMAXSSMFENCEMINPDMINPSMINSDMINSSMONITORMOVMOVAPDMOVAPSMOVBEMOVDMOVDDUPMOVDQ2QMOVDQAMOVDQUMOVHLPSMOVHPDMOVHPSMOVLHPSMOVLPDMOVLPSMOVMSKPDMOVMSKPSMOVNTDQMOVNTDQAMOVNTIMOVNTPDMOVNTPSMOVNTQMOVNTSDMOVNTSSMOVQMOVQ2DQMOVSBMOVSDMOVSD_XMMMOVSHDUPMOVSLDUPMOVSQMOVSSMOVSWMOVSXMOVSXDMOVUPDMOVUPSMOVZXMPSADBWMULMULPDMULPSMULSDMULSSMULXMWAITNEGNOPNOTORORPDORPSOUTOUTSBOUTSDOUTSWPABSBPABSDPABSWPACKSSDWPACKSSWBPACKUSDWPACKUSWBPADDBPADDDPADDQPADDSBPADDSWPADDUSBPADDUSWPADDWPALIGNRPANDPANDNPAUSEPAVGBPAVGWPBLENDVBPBLENDWPCLMULQDQPCMPEQBPCMPEQDPCMPEQQPCMPEQWPCMPESTRIPCMPESTRMPCMPGTBPCMPGTDPCMPGTQPCMPGTWPCMPISTRIPCMPISTRMPDEPPEXTPEXTRBPEXTRDPEXTRQPEXTRWPHADDDPHADDSWPHADDWPHMINPOSUWPHSUBDPHSUBSWPHSUBWPINSRBPINSRDPINSRQPINSRWPMADDUBSWPMADDWDPMAXSBPMAXSDPMAXSWPMAXUBPMAXUDPMAXUWPMINSBPMINSDPMINSWPMINUBPMINUDPMINUWPMOVMSKBPMOVSXBDPMOVSXBQPMOVSXBWPMOVSXDQPMOVSXWDPMOVSXWQPMOVZXBDPMOVZXBQPMOVZXBWPMOVZXDQPMOVZXWDPMOVZXWQPMULDQPMULHRSWPMULHUWPMULHWPMULLDPMULLWPMULUDQPOPPOPAPOPADPOPCNTPOPFPOPFDPOPFQPORPREFETCHNTAPREFETCHT0PREFETCHT1PREFETCHT2PREFETCHWPSADBWPSHUFBPSHUFDPSHUFHWPSHUFLWPSHUFWPSIGNBPSIGNDPSIGNWPSLLDPSLLDQPSLLQPSLLWPSRADPSRAWPSRLDPSRLDQPSRLQPSRLWPSUBBPSUBDPSUBQPSUBSBPSUBSWPSUBUSBPSUBUSWPSUBWPTESTPUNPCKHBWPUNPCKHDQPUNPCKHQDQPUNPCKHWDPUNPCKLBWPUNPCKLDQPUNPCKLQDQPUNPCKLWDPUSHPUSHAPUSHADPUSHFPUSHFDPUSHFQPXORRCLRCPPSRCPSSRCRRDFSBASERDGSBASERDMSRRDPMCRDRANDRDTSCRDTSCPRETROLRORRORXROUNDPDROUNDPSROUNDSDROUNDSSRSMRSQRTPSRSQRTSSSAHFSARSARXSBBSCASBSCASDSCASQSCASWSETASETAESETBSETBESETESETGSETGESETLSETLESETNESETNOSETNPSETNSSETOSETPSETSSFENCESGDTSHLSHLDSHLXSHRSHRDSHRXSHUFPDSHUFPSSIDTSLDTSMSWSQRTPDSQRTPSSQRTSDSQRTSSSTCSTDSTISTMXCSRSTOSBSTOSDSTOSQSTOSWSTRSUBSUBPDSUBPSSUBSDSUBSSSWAPGSSYSCALLSYSENTERSYSEXITSYSRETTESTTZCNTUCOMISDUCOMISSUD1UD2UNPCKHPDUNPCKHPSUNPCKLPDUNPCKLPSVADDPDVADDPSVADDSDVADDSSVADDSUBPDVADDSUBPSVAESDECVAESDECLASTVAESENCVAESENCLASTVAESIMCVAESKEYGENASSISTVALIGNDVALIGNQVANDNPDVANDNPSVANDPDVANDPSVBLENDMPDVBLENDMPSVBLENDPDVBLENDPSVBLENDVPDVBLENDVPSVBROADCASTF128VBROADCASTF32X2VBROADCASTF32X4VBROADCASTF32X8VBROADCASTF64X2VBROADCASTF64X4VBROADCASTI128VBROADCASTI32X2VBROADCASTI32X4VBROADCASTI32X8VBROADCASTI64X2VBROADCASTI64X4VBROADCASTSDVBROADCASTSSVCMPPDVCMPPSVCMPSDVCMPSSVCOMISDVCOMISSVCOMPRESSPDVCOMPRESSPSVCVTDQ2PDVCVTDQ2PSVCVTPD2DQVCVTPD2PSVCVTPD2QQVCVTPD2UDQVCVTPD2UQQVCVTPH2PSVCVTPS2DQVCVTPS2PDVCVTPS2PHVCVTPS2QQVCVTPS2UDQVCVTPS2UQQVCVTQQ2PDVCVTQQ2PSVCVTSD2SIVCVTSD2SSVCVTSD2USIVCVTSI2SDVCVTSI2SSVCVTSS2SDVCVTSS2SIVCVTSS2USIVCVTTPD2DQVCVTTPD2QQVCVTTPD2UDQVCVTTPD2UQQVCVTTPS2DQVCVTTPS2QQVCVTTPS2UDQVCVTTPS2UQQVCVTTSD2SIVCVTTSD2USIVCVTTSS2SIVCVTTSS2USIVCVTUDQ2PDVCVTUDQ2PSVCVTUQQ2PDVCVTUQQ2PSVCVTUSI2SDVCVTUSI2SSVDBPSADBWVDIVPDVDIVPSVDIVSDVDIVSSVDPPDVDPPSVERRVERWVEXP2PDVEXP2PSVEXPANDPDVEXPANDPSVEXTRACTF128VEXTRACTF32X4VEXTRACTF32X8VEXTRACTF64X2VEXTRACTF64X4VEXTRACTI128VEXTRACTI32X4VEXTRACTI32X8VEXTRACTI64X2VEXTRACTI64X4VEXTRACTPSVFIXUPIMMPDVFIXUPIMMPSVFIXUPIMMSDVFIXUPIMMSSVFMADD132PDVFMADD132PSVFMADD132SDVFMADD132SSVFMADD213PDVFMADD213PSVFMADD213SDVFMADD213SSVFMADD231PDVFMADD231PSVFMADD231SDVFMADD231SSVFMADDSUB132PDVFMADDSUB132PSVFMADDSUB213PDVFMADDSUB213PSVFMADDSUB231PDVFMADDSUB231PSVFMSUB132PDVFMSUB132PSVFMSUB132SDVFMSUB132SSVFMSUB213PDVFMSUB213PSVFMSUB213SDVFMSUB213SSVFMSUB231PDVFMSUB231PSVFMSUB231SDVFMSUB231SSVFMSUBADD132PDVFMSUBADD132PSVFMSUBADD213PDVFMSUBADD213PSVFMSUBADD231PDVFMSUBADD231PSVFNMADD132PDVFNMADD132PSVFNMADD132SDVFNMADD132SSVFNMADD213PDVFNMADD213PSVFNMADD213SDVFNMADD213SSVFNMADD231PDVFNMADD231PSVFNMADD231SDVFNMADD231SSVFNMSUB132PDVFNMSUB132PSVFNMSUB132SDVFNMSUB132SSVFNMSUB213PDVFNMSUB213PSVFNMSUB213SDVFNMSUB213SSVFNMSUB231PDVFNMSUB231PSVFNMSUB231SDVFNMSUB231SSVFPCLASSPDVFPCLASSPSVFPCLASSSDVFPCLASSSSVGATHERDPDVGATHERDPSVGATHERPF0DPDVGATHERPF0DPSVGATHERPF0QPDVGATHERPF0QPSVGATHERPF1DPDVGATHERPF1DPSVGATHERPF1QPDVGATHERPF1QPSVGATHERQPDVGATHERQPSVGETEXPPDVGETEXPPSVGETEXPSDVGETEXPSSVGETMANTPDVGETMANTPSVGETMANTSDVGETMANTSSVGF2P8AFFINEINVQBVGF2P8AFFINEQBVGF2P8MULBVHADDPDVHADDPSVHSUBPDVHSUBPSVINSERTF128VINSERTF32X4VINSERTF32X8VINSERTF64X2VINSERTF64X4VINSERTI128VINSERTI32X4VINSERTI32X8VINSERTI64X2VINSERTI64X4VINSERTPSVLDDQUVLDMXCSRVMASKMOVDQUVMASKMOVPDVMASKMOVPSVMAXPDVMAXPSVMAXSDVMAXSSVMINPDVMINPSVMINSDVMINSSVMOVAPDVMOVAPSVMOVDVMOVDDUPVMOVDQAVMOVDQA32VMOVDQA64VMOVDQUVMOVDQU16VMOVDQU32VMOVDQU64VMOVDQU8VMOVHLPSVMOVHPDVMOVHPSVMOVLHPSVMOVLPDVMOVLPSVMOVMSKPDVMOVMSKPSVMOVNTDQVMOVNTDQAVMOVNTPDVMOVNTPSVMOVQVMOVSDVMOVSHDUPVMOVSLDUPVMOVSSVMOVUPDVMOVUPSVMPSADBWVMULPDVMULPSVMULSDVMULSSVORPDVORPSVPABSBVPABSDVPABSQVPABSWVPACKSSDWVPACKSSWBVPACKUSDWVPACKUSWBVPADDBVPADDDVPADDQVPADDSBVPADDSWVPADDUSBVPADDUSWVPADDWVPALIGNRVPANDVPANDDVPANDNVPANDNDVPANDNQVPANDQVPAVGBVPAVGWVPBLENDDVPBLENDMBVPBLENDMDVPBLENDMQVPBLENDMWVPBLENDVBVPBLENDWVPBROADCASTBVPBROADCASTDVPBROADCASTMB2QVPBROADCASTMW2DVPBROADCASTQVPBROADCASTWVPCLMULQDQVPCMPBVPCMPDVPCMPEQBVPCMPEQDVPCMPEQQVPCMPEQWVPCMPESTRIVPCMPESTRMVPCMPGTBVPCMPGTDVPCMPGTQVPCMPGTWVPCMPISTRIVPCMPISTRMVPCMPQVPCMPUBVPCMPUDVPCMPUQVPCMPUWVPCMPWVPCOMPRESSBVPCOMPRESSDVPCOMPRESSQVPCOMPRESSWVPCONFLICTDVPCONFLICTQVPDPBUSDVPDPBUSDSVPDPWSSDVPDPWSSDSVPERM2F128VPERM2I128VPERMBVPERMDVPERMI2BVPERMI2DVPERMI2PDVPERMI2PSVPERMI2QVPERMI2WVPERMILPDVPERMILPSVPERMPDVPERMPSVPERMQVPERMT2BVPERMT2DVPERMT2PDVPERMT2PSVPERMT2QVPERMT2WVPERMWVPEXPANDBVPEXPANDDVPEXPANDQVPEXPANDWVPEXTRBVPEXTRDVPEXTRQVPEXTRWVPEXTRW_C5VPGATHERDDVPGATHERDQVPGATHERQDVPGATHERQQVPHADDDVPHADDSWVPHADDWVPHMINPOSUWVPHSUBDVPHSUBSWVPHSUBWVPINSRBVPINSRDVPINSRQVPINSRWVPLZCNTDVPLZCNTQVPMADD52HUQVPMADD52LUQVPMADDUBSWVPMADDWDVPMASKMOVDVPMASKMOVQVPMAXSBVPMAXSDVPMAXSQVPMAXSWVPMAXUBVPMAXUDVPMAXUQVPMAXUWVPMINSBVPMINSDVPMINSQVPMINSWVPMINUBVPMINUDVPMINUQVPMINUWVPMOVB2MVPMOVD2MVPMOVDBVPMOVDWVPMOVM2BVPMOVM2DVPMOVM2QVPMOVM2WVPMOVMSKBVPMOVQ2MVPMOVQBVPMOVQDVPMOVQWVPMOVSDBVPMOVSDWVPMOVSQBVPMOVSQDVPMOVSQWVPMOVSWBVPMOVSXBDVPMOVSXBQVPMOVSXBWVPMOVSXDQVPMOVSXWDVPMOVSXWQVPMOVUSDBVPMOVUSDWVPMOVUSQBVPMOVUSQDVPMOVUSQWVPMOVUSWBVPMOVW2MVPMOVWBVPMOVZXBDVPMOVZXBQVPMOVZXBWVPMOVZXDQVPMOVZXWDVPMOVZXWQVPMULDQVPMULHRSWVPMULHUWVPMULHWVPMULLDVPMULLQVPMULLWVPMULTISHIFTQBVPMULUDQVPOPCNTBVPOPCNTDVPOPCNTQVPOPCNTWVPORVPORDVPORQVPROLDVPROLQVPROLVDVPROLVQVPRORDVPRORQVPRORVDVPRORVQVPSADBWVPSCATTERDDVPSCATTERDQVPSCATTERQDVPSCATTERQQVPSHLDDVPSHLDQVPSHLDVDVPSHLDVQVPSHLDVWVPSHLDWVPSHRDDVPSHRDQVPSHRDVDVPSHRDVQVPSHRDVWVPSHRDWVPSHUFBVPSHUFBITQMBVPSHUFDVPSHUFHWVPSHUFLWVPSIGNBVPSIGNDVPSIGNWVPSLLDVPSLLDQVPSLLQVPSLLVDVPSLLVQVPSLLVWVPSLLWVPSRADVPSRAQVPSRAVDVPSRAVQVPSRAVWVPSRAWVPSRLDVPSRLDQVPSRLQVPSRLVDVPSRLVQVPSRLVWVPSRLWVPSUBBVPSUBDVPSUBQVPSUBSBVPSUBSWVPSUBUSBVPSUBUSWVPSUBWVPTERNLOGDVPTERNLOGQVPTESTVPTESTMBVPTESTMDVPTESTMQVPTESTMWVPTESTNMBVPTESTNMDVPTESTNMQVPTESTNMWVPUNPCKHBWVPUNPCKHDQVPUNPCKHQDQVPUNPCKHWDVPUNPCKLBWVPUNPCKLDQVPUNPCKLQDQVPUNPCKLWDVPXORVPXORDVPXORQVRANGEPDVRANGEPSVRANGESDVRANGESSVRCP14PDVRCP14PSVRCP14SDVRCP14SSVRCP28PDVRCP28PSVRCP28SDVRCP28SSVRCPPSVRCPSSVREDUCEPDVREDUCEPSVREDUCESDVREDUCESSVRNDSCALEPDVRNDSCALEPSVRNDSCALESDVRNDSCALESSVROUNDPDVROUNDPSVROUNDSDVROUNDSSVRSQRT14PDVRSQRT14PSVRSQRT14SDVRSQRT14SSVRSQRT28PDVRSQRT28PSVRSQRT28SDVRSQRT28SSVRSQRTPSVRSQRTSSVSCALEFPDVSCALEFPSVSCALEFSDVSCALEFSSVSCATTERDPDVSCATTERDPSVSCATTERPF0DPDVSCATTERPF0DPSVSCATTERPF0QPDVSCATTERPF0QPSVSCATTERPF1DPDVSCATTERPF1DPSVSCATTERPF1QPDVSCATTERPF1QPSVSCATTERQPDVSCATTERQPSVSHUFF32X4VSHUFF64X2VSHUFI32X4VSHUFI64X2VSHUFPDVSHUFPSVSQRTPDVSQRTPSVSQRTSDVSQRTSSVSTMXCSRVSUBPDVSUBPSVSUBSDVSUBSSVTESTPDVTESTPSVUCOMISDVUCOMISSVUNPCKHPDVUNPCKHPSVUNPCKLPDVUNPCKLPSVXORPDVXORPSVZEROALLVZEROUPPERWBINVDWRFSBASEWRGSBASEWRMSRXABORTXADDXBEGINXCHGXENDXGETBVXLATBXORXORPDXORPSXRSTORXRSTOR64XRSTORSXRSTORS64XSAVEXSAVE64XSAVECXSAVEC64XSAVEOPTXSAVEOPT64XSAVESXSAVES64XSETBVXTEST"
+
+var mnemonicIdcs = [...]uint16{
+	0, 3, 6, 9, 12, 15, 18, 23, 28, 33, 38, 46, 54, 60, 70, 76,
+	86, 92, 107, 110, 114, 120, 126, 131, 136, 140, 145, 152, 159, 167, 175, 179,
+	185, 189, 194, 197, 200, 205, 207, 210, 213, 216, 220, 224, 227, 230, 234, 237,
+	240, 247, 250, 254, 257, 262, 268, 273, 279, 284, 289, 295, 300, 306, 312, 318,
+	324, 330, 335, 340, 345, 348, 353, 358, 363, 368, 377, 382, 387, 392, 399, 409,
+	418, 424, 430, 435, 438, 443, 451, 459, 467, 475, 483, 491, 499, 507, 515, 523,
+	531, 539, 547, 555, 563, 571, 580, 589, 598, 607, 616, 625, 628, 632, 635, 638,
+	641, 644, 649, 654, 659, 664, 668, 672, 676, 681, 690, 695, 699, 703, 708, 712,
+	717, 721, 727, 734, 740, 747, 755, 762, 769, 775, 779, 784, 790, 795, 801, 805,
+	812, 816, 821, 826, 832, 837, 843, 848, 853, 859, 864, 870, 874, 879, 886, 890,
+	895, 901, 906, 912, 915, 919, 924, 930, 936, 942, 948, 953, 957, 962, 968, 974,
+	978, 984, 990, 997, 1003, 1009, 1014, 1020, 1025, 1032, 1038, 1044, 1048, 1055, 1060, 1063,
+	1067, 1071, 1076, 1081, 1087, 1091, 1096, 1102, 1109, 1115, 1122, 1127, 1131, 1135, 1142, 1151,
+	1157, 1165, 1172, 1177, 1184, 1190, 1196, 1199, 1205, 1211, 1216, 1220, 1224, 1226, 1229, 1233,
+	1237, 1245, 1249, 1252, 1256, 1260, 1266, 1273, 1277, 1282, 1287, 1289, 1292, 1294, 1297, 1301,
+	1303, 1308, 1310, 1313, 1315, 1318, 1321, 1324, 1327, 1330, 1333, 1335, 1337, 1342, 1344, 1349,
+	1354, 1359, 1364, 1369, 1374, 1380, 1386, 1392, 1398, 1403, 1408, 1413, 1418, 1423, 1428, 1433,
+	1438, 1443, 1448, 1452, 1456, 1460, 1468, 1476, 1484, 1492, 1496, 1504, 1512, 1520, 1528, 1536,
+	1544, 1552, 1560, 1566, 1572, 1578, 1584, 1592, 1600, 1608, 1614, 1620, 1626, 1632, 1637, 1642,
+	1647, 1652, 1656, 1659, 1664, 1669, 1676, 1679, 1682, 1687, 1690, 1696, 1699, 1703, 1706, 1710,
+	1714, 1718, 1722, 1727, 1732, 1737, 1742, 1746, 1751, 1757, 1761, 1764, 1767, 1770, 1775, 1785,
+	1793, 1798, 1803, 1808, 1813, 1819, 1824, 1829, 1834, 1839, 1846, 1849, 1855, 1861, 1866, 1870,
+	1877, 1884, 1890, 1896, 1903, 1909, 1915, 1922, 1928, 1934, 1942, 1950, 1957, 1965, 1971, 1978,
+	1985, 1991, 1998, 2005, 2009, 2016, 2021, 2026, 2035, 2043, 2051, 2056, 2061, 2066, 2071, 2077,
+	2083, 2089, 2094, 2101, 2104, 2109, 2114, 2119, 2124, 2128, 2133, 2136, 2139, 2142, 2144, 2148,
+	2152, 2155, 2160, 2165, 2170, 2175, 2180, 2185, 2193, 2201, 2209, 2217, 2222, 2227, 2232, 2238,
+	2244, 2251, 2258, 2263, 2270, 2274, 2279, 2284, 2289, 2294, 2302, 2309, 2318, 2325, 2332, 2339,
+	2346, 2355, 2364, 2371, 2378, 2385, 2392, 2401, 2410, 2414, 2418, 2424, 2430, 2436, 2442, 2448,
+	2455, 2461, 2471, 2477, 2484, 2490, 2496, 2502, 2508, 2514, 2523, 2530, 2536, 2542, 2548, 2554,
+	2560, 2566, 2572, 2578, 2584, 2590, 2596, 2602, 2610, 2618, 2626, 2634, 2642, 2650, 2658, 2666,
+	2674, 2682, 2690, 2698, 2706, 2712, 2720, 2727, 2733, 2739, 2745, 2752, 2755, 2759, 2764, 2770,
+	2774, 2779, 2784, 2787, 2798, 2808, 2818, 2828, 2837, 2843, 2849, 2855, 2862, 2869, 2875, 2881,
+	2887, 2893, 2898, 2904, 2909, 2914, 2919, 2924, 2929, 2935, 2940, 2945, 2950, 2955, 2960, 2966,
+	2972, 2979, 2986, 2991, 2996, 3005, 3014, 3024, 3033, 3042, 3051, 3061, 3070, 3074, 3079, 3085,
+	3090, 3096, 3102, 3106, 3109, 3114, 3119, 3122, 3130, 3138, 3143, 3148, 3154, 3159, 3165, 3168,
+	3171, 3174, 3178, 3185, 3192, 3199, 3206, 3209, 3216, 3223, 3227, 3230, 3234, 3237, 3242, 3247,
+	3252, 3257, 3261, 3266, 3270, 3275, 3279, 3283, 3288, 3292, 3297, 3302, 3307, 3312, 3317, 3321,
+	3325, 3329, 3335, 3339, 3342, 3346, 3350, 3353, 3357, 3361, 3367, 3373, 3377, 3381, 3385, 3391,
+	3397, 3403, 3409, 3412, 3415, 3418, 3425, 3430, 3435, 3440, 3445, 3448, 3451, 3456, 3461, 3466,
+	3471, 3477, 3484, 3492, 3499, 3505, 3509, 3514, 3521, 3528, 3531, 3534, 3542, 3550, 3558, 3566,
+	3572, 3578, 3584, 3590, 3599, 3608, 3615, 3626, 3633, 3644, 3651, 3667, 3674, 3681, 3688, 3695,
+	3701, 3707, 3716, 3725, 3733, 3741, 3750, 3759, 3773, 3788, 3803, 3818, 3833, 3848, 3862, 3877,
+	3892, 3907, 3922, 3937, 3949, 3961, 3967, 3973, 3979, 3985, 3992, 3999, 4010, 4021, 4030, 4039,
+	4048, 4057, 4066, 4076, 4086, 4095, 4104, 4113, 4122, 4131, 4141, 4151, 4160, 4169, 4178, 4187,
+	4197, 4206, 4215, 4224, 4233, 4243, 4253, 4263, 4274, 4285, 4295, 4305, 4316, 4327, 4337, 4348,
+	4358, 4369, 4379, 4389, 4399, 4409, 4419, 4429, 4438, 4444, 4450, 4456, 4462, 4467, 4472, 4476,
+	4480, 4487, 4494, 4503, 4512, 4524, 4537, 4550, 4563, 4576, 4588, 4601, 4614, 4627, 4640, 4650,
+	4661, 4672, 4683, 4694, 4705, 4716, 4727, 4738, 4749, 4760, 4771, 4782, 4793, 4804, 4815, 4826,
+	4840, 4854, 4868, 4882, 4896, 4910, 4921, 4932, 4943, 4954, 4965, 4976, 4987, 4998, 5009, 5020,
+	5031, 5042, 5056, 5070, 5084, 5098, 5112, 5126, 5138, 5150, 5162, 5174, 5186, 5198, 5210, 5222,
+	5234, 5246, 5258, 5270, 5282, 5294, 5306, 5318, 5330, 5342, 5354, 5366, 5378, 5390, 5402, 5414,
+	5424, 5434, 5444, 5454, 5464, 5474, 5487, 5500, 5513, 5526, 5539, 5552, 5565, 5578, 5588, 5598,
+	5607, 5616, 5625, 5634, 5644, 5654, 5664, 5674, 5691, 5705, 5715, 5722, 5729, 5736, 5743, 5754,
+	5766, 5778, 5790, 5802, 5813, 5825, 5837, 5849, 5861, 5870, 5876, 5884, 5895, 5905, 5915, 5921,
+	5927, 5933, 5939, 5945, 5951, 5957, 5963, 5970, 5977, 5982, 5990, 5997, 6006, 6015, 6022, 6031,
+	6040, 6049, 6057, 6065, 6072, 6079, 6087, 6094, 6101, 6110, 6119, 6127, 6136, 6144, 6152, 6157,
+	6163, 6172, 6181, 6187, 6194, 6201, 6209, 6215, 6221, 6227, 6233, 6238, 6243, 6249, 6255, 6261,
+	6267, 6276, 6285, 6294, 6303, 6309, 6315, 6321, 6328, 6335, 6343, 6351, 6357, 6365, 6370, 6376,
+	6382, 6389, 6396, 6402, 6408, 6414, 6422, 6431, 6440, 6449, 6458, 6467, 6475, 6487, 6499, 6514,
+	6529, 6541, 6553, 6563, 6569, 6575, 6583, 6591, 6599, 6607, 6617, 6627, 6635, 6643, 6651, 6659,
+	6669, 6679, 6685, 6692, 6699, 6706, 6713, 6719, 6730, 6741, 6752, 6763, 6774, 6785, 6793, 6802,
+	6810, 6819, 6829, 6839, 6845, 6851, 6859, 6867, 6876, 6885, 6893, 6901, 6910, 6919, 6926, 6933,
+	6939, 6947, 6955, 6964, 6973, 6981, 6989, 6995, 7004, 7013, 7022, 7031, 7038, 7045, 7052, 7059,
+	7069, 7079, 7089, 7099, 7109, 7116, 7124, 7131, 7142, 7149, 7157, 7164, 7171, 7178, 7185, 7192,
+	7200, 7208, 7219, 7230, 7240, 7248, 7258, 7268, 7275, 7282, 7289, 7296, 7303, 7310, 7317, 7324,
+	7331, 7338, 7345, 7352, 7359, 7366, 7373, 7380, 7388, 7396, 7403, 7410, 7418, 7426, 7434, 7442,
+	7451, 7459, 7466, 7473, 7480, 7488, 7496, 7504, 7512, 7520, 7528, 7537, 7546, 7555, 7564, 7573,
+	7582, 7591, 7600, 7609, 7618, 7627, 7636, 7644, 7651, 7660, 7669, 7678, 7687, 7696, 7705, 7712,
+	7721, 7729, 7736, 7743, 7750, 7757, 7771, 7779, 7787, 7795, 7803, 7811, 7815, 7820, 7825, 7831,
+	7837, 7844, 7851, 7857, 7863, 7870, 7877, 7884, 7895, 7906, 7917, 7928, 7935, 7942, 7950, 7958,
+	7966, 7973, 7980, 7987, 7995, 8003, 8011, 8018, 8025, 8037, 8044, 8052, 8060, 8067, 8074, 8081,
+	8087, 8094, 8100, 8107, 8114, 8121, 8127, 8133, 8139, 8146, 8153, 8160, 8166, 8172, 8179, 8185,
+	8192, 8199, 8206, 8212, 8218, 8224, 8230, 8237, 8244, 8252, 8260, 8266, 8276, 8286, 8292, 8300,
+	8308, 8316, 8324, 8333, 8342, 8351, 8360, 8370, 8380, 8391, 8401, 8411, 8421, 8432, 8442, 8447,
+	8453, 8459, 8467, 8475, 8483, 8491, 8499, 8507, 8515, 8523, 8531, 8539, 8547, 8555, 8561, 8567,
+	8576, 8585, 8594, 8603, 8614, 8625, 8636, 8647, 8655, 8663, 8671, 8679, 8689, 8699, 8709, 8719,
+	8729, 8739, 8749, 8759, 8767, 8775, 8784, 8793, 8802, 8811, 8822, 8833, 8847, 8861, 8875, 8889,
+	8903, 8917, 8931, 8945, 8956, 8967, 8977, 8987, 8997, 9007, 9014, 9021, 9028, 9035, 9042, 9049,
+	9057, 9063, 9069, 9075, 9081, 9088, 9095, 9103, 9111, 9120, 9129, 9138, 9147, 9153, 9159, 9167,
+	9177, 9183, 9191, 9199, 9204, 9210, 9214, 9220, 9224, 9228, 9234, 9239, 9242, 9247, 9252, 9258,
+	9266, 9273, 9282, 9287, 9294, 9300, 9308, 9316, 9326, 9332, 9340, 9346, 9351,
+}
+
+var tableIdcs = [...]uint16{
+	0, 1, 3, 5, 6, 28, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59,
+	60, 61, 62, 84, 86, 87, 88, 89, 90, 91, 93, 94, 95, 96, 97, 99,
+	101, 103, 105, 108, 111, 114, 120, 126, 132, 138, 140, 146, 147, 148, 149, 150,
+	151, 152, 153, 154, 155, 158, 161, 164, 167, 170, 173, 176, 179, 182, 185, 188,
+	191, 194, 197, 200, 203, 225, 226, 227, 228, 229, 230, 231, 232, 233, 238, 239,
+	240, 241, 242, 243, 244, 250, 251, 252, 253, 254, 255, 256, 257, 258, 259, 260,
+	262, 263, 265, 267, 268, 270, 271, 272, 273, 274, 276, 278, 279, 280, 281, 282,
+	289, 294, 295, 296, 297, 298, 299, 300, 301, 304, 305, 306, 307, 311, 313, 314,
+	315, 316, 317, 318, 319, 320, 321, 322, 323, 324, 328, 329, 330, 334, 335, 336,
+	337, 341, 343, 347, 349, 350, 351, 353, 355, 357, 359, 361, 364, 366, 367, 369,
+	372, 375, 377, 379, 383, 384, 385, 386, 387, 388, 389, 390, 394, 396, 397, 398,
+	399, 400, 401, 402, 404, 405, 406, 407, 408, 409, 410, 411, 412, 413, 414, 417,
+	421, 425, 427, 431, 433, 434, 436, 437, 438, 440, 441, 442, 443, 445, 446, 447,
+	448, 449, 450, 451, 452, 453, 454, 455, 456, 457, 458, 463, 476, 482, 489, 490,
+	491, 492, 493, 495, 496, 497, 498, 500, 501, 502, 503, 507, 511, 515, 519, 520,
+	524, 525, 529, 533, 537, 541, 548, 552, 556, 560, 564, 568, 572, 573, 577, 578,
+	579, 580, 581, 582, 583, 584, 585, 586, 587, 588, 589, 593, 599, 603, 607, 608,
+	609, 610, 611, 612, 613, 614, 615, 616, 617, 618, 619, 620, 621, 622, 623, 624,
+	625, 626, 627, 628, 629, 630, 631, 632, 633, 634, 635, 636, 637, 638, 639, 640,
+	641, 642, 643, 646, 651, 652, 653, 655, 658, 661, 663, 664, 667, 669, 672, 674,
+	679, 680, 681, 682, 683, 684, 685, 686, 687, 688, 690, 693, 696, 697, 700, 701,
+	702, 703, 704, 705, 706, 707, 708, 709, 710, 711, 712, 760, 762, 764, 770, 774,
+	775, 776, 778, 780, 781, 783, 785, 786, 788, 790, 791, 792, 793, 794, 796, 797,
+	798, 799, 800, 801, 809, 810, 811, 812, 814, 815, 816, 817, 819, 820, 826, 829,
+	831, 833, 839, 840, 845, 846, 847, 848, 849, 851, 852, 857, 860, 865, 887, 888,
+	889, 895, 896, 897, 898, 900, 902, 904, 906, 908, 909, 911, 913, 915, 917, 919,
+	921, 923, 925, 927, 929, 931, 933, 934, 936, 938, 939, 940, 941, 943, 945, 946,
+	948, 949, 950, 952, 954, 955, 957, 958, 959, 961, 963, 964, 965, 966, 969, 971,
+	973, 975, 976, 978, 980, 982, 983, 984, 985, 987, 989, 991, 992, 993, 995, 997,
+	998, 999, 1000, 1001, 1003, 1005, 1006, 1007, 1009, 1010, 1011, 1012, 1013, 1014, 1015, 1016,
+	1017, 1018, 1019, 1020, 1021, 1022, 1024, 1026, 1028, 1029, 1031, 1033, 1048, 1049, 1050, 1053,
+	1054, 1055, 1056, 1058, 1059, 1060, 1061, 1062, 1063, 1065, 1067, 1068, 1069, 1070, 1071, 1073,
+	1075, 1077, 1081, 1082, 1086, 1090, 1094, 1098, 1102, 1103, 1107, 1111, 1113, 1115, 1117, 1119,
+	1121, 1123, 1125, 1127, 1128, 1130, 1132, 1133, 1135, 1137, 1139, 1140, 1142, 1157, 1158, 1159,
+	1160, 1161, 1162, 1164, 1179, 1180, 1181, 1196, 1198, 1200, 1201, 1202, 1205, 1206, 1207, 1209,
+	1224, 1239, 1241, 1242, 1243, 1244, 1245, 1246, 1247, 1248, 1249, 1264, 1266, 1288, 1289, 1290,
+	1291, 1292, 1294, 1296, 1298, 1300, 1302, 1304, 1306, 1308, 1310, 1312, 1314, 1316, 1318, 1320,
+	1322, 1324, 1325, 1326, 1341, 1347, 1349, 1364, 1370, 1372, 1373, 1374, 1375, 1378, 1381, 1382,
+	1383, 1384, 1385, 1386, 1387, 1388, 1389, 1390, 1391, 1392, 1393, 1396, 1418, 1419, 1420, 1421,
+	1422, 1423, 1424, 1425, 1427, 1429, 1443, 1446, 1447, 1448, 1449, 1450, 1451, 1452, 1453, 1454,
+	1459, 1464, 1466, 1468, 1470, 1472, 1476, 1480, 1484, 1488, 1489, 1490, 1493, 1496, 1501, 1506,
+	1511, 1516, 1519, 1522, 1524, 1526, 1528, 1530, 1531, 1533, 1535, 1536, 1538, 1539, 1540, 1543,
+	1545, 1546, 1548, 1549, 1553, 1560, 1565, 1570, 1572, 1574, 1576, 1578, 1581, 1584, 1589, 1594,
+	1599, 1604, 1607, 1610, 1613, 1618, 1623, 1628, 1633, 1636, 1639, 1642, 1645, 1648, 1653, 1655,
+	1658, 1663, 1668, 1670, 1675, 1678, 1683, 1686, 1689, 1692, 1697, 1700, 1703, 1706, 1711, 1714,
+	1719, 1722, 1725, 1728, 1731, 1734, 1737, 1740, 1743, 1748, 1753, 1755, 1757, 1758, 1760, 1761,
+	1762, 1763, 1764, 1767, 1770, 1771, 1773, 1774, 1776, 1777, 1778, 1780, 1781, 1783, 1784, 1786,
+	1789, 1792, 1793, 1794, 1799, 1804, 1806, 1808, 1813, 1818, 1820, 1822, 1827, 1832, 1834, 1836,
+	1841, 1846, 1851, 1856, 1861, 1866, 1871, 1876, 1878, 1880, 1885, 1890, 1892, 1894, 1899, 1904,
+	1906, 1908, 1913, 1918, 1923, 1928, 1933, 1938, 1943, 1948, 1950, 1952, 1957, 1962, 1964, 1966,
+	1971, 1976, 1978, 1980, 1985, 1990, 1992, 1994, 1999, 2004, 2006, 2008, 2013, 2018, 2020, 2022,
+	2025, 2028, 2029, 2030, 2035, 2040, 2041, 2042, 2043, 2044, 2045, 2046, 2047, 2048, 2053, 2058,
+	2061, 2064, 2065, 2066, 2069, 2072, 2073, 2074, 2077, 2080, 2083, 2085, 2087, 2089, 2091, 2092,
+	2094, 2095, 2097, 2098, 2099, 2101, 2102, 2104, 2105, 2107, 2109, 2110, 2111, 2115, 2119, 2124,
+	2129, 2131, 2133, 2138, 2143, 2145, 2147, 2157, 2167, 2173, 2178, 2182, 2188, 2194, 2198, 2204,
+	2210, 2216, 2222, 2224, 2228, 2232, 2234, 2238, 2242, 2244, 2246, 2251, 2256, 2261, 2266, 2275,
+	2282, 2287, 2292, 2299, 2309, 2319, 2321, 2326, 2331, 2333, 2335, 2340, 2345, 2350, 2355, 2358,
+	2363, 2368, 2373, 2378, 2383, 2388, 2393, 2398, 2403, 2408, 2413, 2418, 2423, 2428, 2430, 2433,
+	2435, 2438, 2441, 2444, 2449, 2454, 2456, 2459, 2462, 2465, 2468, 2470, 2472, 2480, 2491, 2494,
+	2497, 2505, 2513, 2517, 2520, 2523, 2528, 2533, 2539, 2544, 2545, 2546, 2551, 2556, 2561, 2566,
+	2567, 2568, 2571, 2574, 2577, 2580, 2583, 2586, 2589, 2592, 2595, 2598, 2601, 2604, 2607, 2610,
+	2613, 2616, 2617, 2618, 2621, 2624, 2627, 2630, 2633, 2636, 2639, 2642, 2652, 2662, 2667, 2670,
+	2675, 2678, 2681, 2684, 2687, 2690, 2693, 2696, 2699, 2702, 2705, 2708, 2710, 2713, 2715, 2718,
+	2720, 2725, 2730, 2735, 2740, 2742, 2744, 2746, 2747, 2749, 2751, 2753, 2755, 2758, 2760, 2762,
+	2765, 2768, 2771, 2774, 2779, 2784, 2788, 2792, 2797, 2802, 2805, 2810, 2815, 2820, 2823, 2828,
+	2833, 2838, 2841, 2846, 2851, 2856, 2859, 2864, 2867, 2870, 2873, 2876, 2879, 2882, 2885, 2888,
+	2890, 2893, 2896, 2899, 2902, 2905, 2908, 2911, 2914, 2917, 2920, 2925, 2930, 2935, 2939, 2944,
+	2948, 2951, 2954, 2957, 2960, 2963, 2966, 2969, 2972, 2977, 2982, 2987, 2991, 2996, 3000, 3005,
+	3010, 3015, 3020, 3025, 3028, 3033, 3036, 3041, 3044, 3047, 3050, 3053, 3055, 3058, 3061, 3064,
+	3067, 3070, 3073, 3076, 3079, 3082, 3085, 3090, 3093, 3096, 3099, 3102, 3105, 3108, 3111, 3114,
+	3117, 3120, 3123, 3126, 3129, 3132, 3135, 3138, 3143, 3146, 3151, 3156, 3161, 3163, 3165, 3167,
+	3175, 3180, 3188, 3193, 3198, 3201, 3211, 3221, 3227, 3232, 3235, 3238, 3248, 3256, 3261, 3269,
+	3274, 3279, 3282, 3292, 3297, 3302, 3307, 3312, 3317, 3322, 3327, 3332, 3335, 3338, 3340, 3343,
+	3346, 3349, 3352, 3355, 3358, 3361, 3364, 3369, 3374, 3379, 3384, 3389, 3394, 3399, 3404, 3406,
+	3409, 3412, 3415, 3418, 3419, 3420, 3423, 3426, 3427, 3428, 3429, 3430, 3431, 3432, 3434, 3435,
+	3438, 3441, 3442, 3443, 3446, 3449, 3450, 3451, 3453, 3455, 3456, 3457, 3460, 3463, 3464, 3465,
+	3466, 3467, 3468, 3469, 3471, 3472, 3475, 3478, 3479, 3480, 3483, 3486, 3487, 3488, 3489, 3490,
+	3491, 3492, 3493, 3494, 3497, 3500, 3502, 3504, 3506, 3508, 3513, 3518, 3523, 3528, 3530, 3532,
+	3533, 3538, 3543, 3545, 3547, 3549, 3551, 3553, 3555, 3560, 3565, 3570, 3575, 3580, 3585, 3586,
+	3587, 3588, 3590, 3592, 3593, 3594, 3599, 3601, 3617, 3618, 3619, 3621, 3643, 3644, 3645, 3646,
+	3647, 3648, 3649, 3650, 3651, 3652, 3653, 3654, 3655, 3656, 3657, 3658, 3659,
+}
+
+var mnemonicSeeds = [...]uint16{
+	2, 1, 0, 2, 1, 1, 0, 0, 0, 1, 0, 0, 0, 1, 1, 0,
+	1, 0, 0, 0, 0, 0, 2, 0, 1, 1, 0, 0, 0, 1, 0, 0,
+	1, 1, 0, 0, 1, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0,
+	3, 0, 0, 0, 0, 0, 0, 0, 0, 2, 1, 0, 0, 0, 1, 5,
+	0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 1,
+	0, 0, 0, 2, 0, 0, 1, 1, 0, 0, 0, 2, 1, 0, 0, 0,
+	0, 0, 0, 2, 0, 1, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0,
+	0, 0, 6, 0, 0, 1, 0, 1, 1, 2, 2, 0, 0, 0, 0, 1,
+	1, 1, 0, 2, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, 3,
+	0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1, 0, 2,
+	0, 1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 1, 2, 0, 1, 0,
+	1, 0, 0, 0, 2, 2, 0, 0, 0, 0, 0, 0, 2, 1, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 1, 3, 0, 0, 1, 0, 1, 0, 0,
+	0, 1, 3, 0, 0, 0, 0, 3, 1, 1, 1, 1, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 0, 0, 2, 0,
+	10, 0, 0, 0, 0, 0, 0, 0, 1, 2, 0, 3, 0, 0, 0, 0,
+	0, 0, 1, 1, 0, 0, 1, 2, 0, 0, 0, 0, 1, 0, 0, 1,
+	0, 0, 0, 0, 0, 0, 2, 0, 1, 4, 0, 1, 0, 1, 0, 0,
+	0, 0, 0, 1, 2, 0, 0, 4, 0, 0, 0, 0, 0, 2, 0, 0,
+	0, 0, 0, 2, 0, 0, 5, 0, 0, 2, 6, 0, 0, 0, 0, 1,
+	0, 0, 1, 0, 0, 0, 0, 0, 2, 1, 0, 0, 0, 0, 0, 2,
+	1, 4, 0, 0, 0, 1, 1, 0, 0, 3, 1, 0, 0, 0, 1, 0,
+	2, 3, 0, 1, 0, 1, 2, 2, 1, 3, 2, 0, 2, 1, 0, 0,
+	1, 1, 0, 0, 1, 2, 0, 1, 0, 3, 0, 0, 0, 1, 1, 0,
+	0, 1, 0, 0, 1, 0, 0, 0, 1, 0, 1, 0, 2, 0, 1, 1,
+	1, 0, 0, 0, 1, 3, 0, 2, 0, 0, 0, 0, 0, 1, 0, 1,
+	0, 0, 0, 0, 0, 1, 4, 0, 0, 1, 1, 0, 0, 1, 1, 2,
+	1, 2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 1,
+	2, 0, 0, 3, 2, 1, 1, 0, 1, 0, 0, 0, 1, 0, 0, 2,
+	0, 0, 1, 0, 0, 0, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0,
+	0, 0, 3, 5, 1, 1, 2, 1, 1, 0, 0, 0, 0, 0, 0, 1,
+	0, 1, 0, 0, 1, 2, 0, 0, 0, 0, 0, 0, 0, 1, 1, 0,
+}
 
-var tableIdcs = [...][2]int16{
-	{0, 1},
-	{1, 3},
-	{3, 5},
-	{5, 6},
-	{6, 28},
-	{28, 50},
-	{50, 51},
-	{51, 52},
-	{52, 53},
-	{53, 54},
-	{54, 55},
-	{55, 56},
-	{56, 57},
-	{57, 58},
-	{58, 59},
-	{59, 60},
-	{60, 61},
-	{61, 62},
-	{62, 84},
-	{84, 86},
-	{86, 87},
-	{87, 88},
-	{88, 89},
-	{89, 90},
-	{90, 91},
-	{91, 93},
-	{93, 94},
-	{94, 95},
-	{95, 96},
-	{96, 97},
-	{97, 99},
-	{99, 101},
-	{101, 103},
-	{103, 105},
-	{105, 108},
-	{108, 111},
-	{111, 114},
-	{114, 120},
-	{120, 126},
-	{126, 132},
-	{132, 138},
-	{138, 140},
-	{140, 146},
-	{146, 147},
-	{147, 148},
-	{148, 149},
-	{149, 150},
-	{150, 151},
-	{151, 152},
-	{152, 153},
-	{153, 154},
-	{154, 155},
-	{155, 158},
-	{158, 161},
-	{161, 164},
-	{164, 167},
-	{167, 170},
-	{170, 173},
-	{173, 176},
-	{176, 179},
-	{179, 182},
-	{182, 185},
-	{185, 188},
-	{188, 191},
-	{191, 194},
-	{194, 197},
-	{197, 200},
-	{200, 203},
-	{203, 225},
-	{225, 226},
-	{226, 227},
-	{227, 228},
-	{228, 229},
-	{229, 230},
-	{230, 231},
-	{231, 232},
-	{232, 233},
-	{233, 238},
-	{238, 239},
-	{239, 240},
-	{240, 241},
-	{241, 242},
-	{242, 243},
-	{243, 244},
-	{244, 250},
-	{250, 251},
-	{251, 252},
-	{252, 253},
-	{253, 254},
-	{254, 255},
-	{255, 256},
-	{256, 257},
-	{257, 258},
-	{258, 259},
-	{259, 260},
-	{260, 262},
-	{262, 263},
-	{263, 265},
-	{265, 267},
-	{267, 268},
-	{268, 270},
-	{270, 271},
-	{271, 272},
-	{272, 273},
-	{273, 274},
-	{274, 276},
-	{276, 278},
-	{278, 279},
-	{279, 280},
-	{280, 281},
-	{281, 282},
-	{282, 289},
-	{289, 294},
-	{294, 295},
-	{295, 296},
-	{296, 297},
-	{297, 298},
-	{298, 299},
-	{299, 300},
-	{300, 301},
-	{301, 304},
-	{304, 305},
-	{305, 306},
-	{306, 307},
-	{307, 311},
-	{311, 313},
-	{313, 314},
-	{314, 315},
-	{315, 316},
-	{316, 317},
-	{317, 318},
-	{318, 319},
-	{319, 320},
-	{320, 321},
-	{321, 322},
-	{322, 323},
-	{323, 324},
-	{324, 328},
-	{328, 329},
-	{329, 330},
-	{330, 334},
-	{334, 335},
-	{335, 336},
-	{336, 337},
-	{337, 341},
-	{341, 343},
-	{343, 347},
-	{347, 349},
-	{349, 350},
-	{350, 351},
-	{351, 353},
-	{353, 355},
-	{355, 357},
-	{357, 359},
-	{359, 361},
-	{361, 364},
-	{364, 366},
-	{366, 367},
-	{367, 369},
-	{369, 372},
-	{372, 375},
-	{375, 377},
-	{377, 379},
-	{379, 383},
-	{383, 384},
-	{384, 385},
-	{385, 386},
-	{386, 387},
-	{387, 388},
-	{388, 389},
-	{389, 390},
-	{390, 394},
-	{394, 396},
-	{396, 397},
-	{397, 398},
-	{398, 399},
-	{399, 400},
-	{400, 401},
-	{401, 402},
-	{402, 404},
-	{404, 405},
-	{405, 406},
-	{406, 407},
-	{407, 408},
-	{408, 409},
-	{409, 410},
-	{410, 411},
-	{411, 412},
-	{412, 413},
-	{413, 414},
-	{414, 417},
-	{417, 421},
-	{421, 425},
-	{425, 427},
-	{427, 431},
-	{431, 433},
-	{433, 434},
-	{434, 436},
-	{436, 437},
-	{437, 438},
-	{438, 440},
-	{440, 441},
-	{441, 442},
-	{442, 443},
-	{443, 445},
-	{445, 446},
-	{446, 447},
-	{447, 448},
-	{448, 449},
-	{449, 450},
-	{450, 451},
-	{451, 452},
-	{452, 453},
-	{453, 454},
-	{454, 455},
-	{455, 456},
-	{456, 457},
-	{457, 458},
-	{458, 463},
-	{463, 476},
-	{476, 482},
-	{482, 489},
-	{489, 490},
-	{490, 491},
-	{491, 492},
-	{492, 493},
-	{493, 495},
-	{495, 496},
-	{496, 497},
-	{497, 498},
-	{498, 500},
-	{500, 501},
-	{501, 502},
-	{502, 503},
-	{503, 506},
-	{506, 509},
-	{509, 512},
-	{512, 515},
-	{515, 516},
-	{516, 519},
-	{519, 520},
-	{520, 523},
-	{523, 526},
-	{526, 529},
-	{529, 532},
-	{532, 539},
-	{539, 542},
-	{542, 545},
-	{545, 548},
-	{548, 551},
-	{551, 554},
-	{554, 557},
-	{557, 558},
-	{558, 561},
-	{561, 562},
-	{562, 563},
-	{563, 564},
-	{564, 565},
-	{565, 566},
-	{566, 567},
-	{567, 568},
-	{568, 569},
-	{569, 570},
-	{570, 571},
-	{571, 572},
-	{572, 573},
-	{573, 574},
-	{574, 575},
-	{575, 576},
-	{576, 577},
-	{577, 578},
-	{578, 581},
-	{581, 586},
-	{586, 587},
-	{587, 588},
-	{588, 590},
-	{590, 593},
-	{593, 596},
-	{596, 598},
-	{598, 599},
-	{599, 602},
-	{602, 604},
-	{604, 607},
-	{607, 609},
-	{609, 614},
-	{614, 615},
-	{615, 616},
-	{616, 617},
-	{617, 618},
-	{618, 619},
-	{619, 620},
-	{620, 621},
-	{621, 622},
-	{622, 623},
-	{623, 625},
-	{625, 628},
-	{628, 631},
-	{631, 632},
-	{632, 635},
-	{635, 636},
-	{636, 637},
-	{637, 638},
-	{638, 639},
-	{639, 640},
-	{640, 641},
-	{641, 642},
-	{642, 643},
-	{643, 644},
-	{644, 645},
-	{645, 646},
-	{646, 647},
-	{647, 695},
-	{695, 697},
-	{697, 699},
-	{699, 705},
-	{705, 709},
-	{709, 710},
-	{710, 711},
-	{711, 713},
-	{713, 715},
-	{715, 716},
-	{716, 718},
-	{718, 720},
-	{720, 721},
-	{721, 723},
-	{723, 725},
-	{725, 726},
-	{726, 727},
-	{727, 728},
-	{728, 729},
-	{729, 731},
-	{731, 732},
-	{732, 733},
-	{733, 734},
-	{734, 735},
-	{735, 736},
-	{736, 744},
-	{744, 745},
-	{745, 746},
-	{746, 747},
-	{747, 749},
-	{749, 750},
-	{750, 751},
-	{751, 752},
-	{752, 754},
-	{754, 755},
-	{755, 761},
-	{761, 764},
-	{764, 766},
-	{766, 768},
-	{768, 774},
-	{774, 775},
-	{775, 780},
-	{780, 781},
-	{781, 782},
-	{782, 783},
-	{783, 784},
-	{784, 786},
-	{786, 787},
-	{787, 792},
-	{792, 795},
-	{795, 800},
-	{800, 822},
-	{822, 823},
-	{823, 824},
-	{824, 830},
-	{830, 831},
-	{831, 832},
-	{832, 833},
-	{833, 835},
-	{835, 837},
-	{837, 839},
-	{839, 841},
-	{841, 843},
-	{843, 844},
-	{844, 846},
-	{846, 848},
-	{848, 850},
-	{850, 852},
-	{852, 854},
-	{854, 856},
-	{856, 858},
-	{858, 860},
-	{860, 862},
-	{862, 864},
-	{864, 866},
-	{866, 868},
-	{868, 870},
-	{870, 872},
-	{872, 873},
-	{873, 874},
-	{874, 875},
-	{875, 877},
-	{877, 879},
-	{879, 880},
-	{880, 882},
-	{882, 883},
-	{883, 884},
-	{884, 886},
-	{886, 888},
-	{888, 889},
-	{889, 891},
-	{891, 892},
-	{892, 893},
-	{893, 895},
-	{895, 897},
-	{897, 898},
-	{898, 899},
-	{899, 900},
-	{900, 903},
-	{903, 905},
-	{905, 907},
-	{907, 909},
-	{909, 910},
-	{910, 912},
-	{912, 914},
-	{914, 916},
-	{916, 917},
-	{917, 918},
-	{918, 919},
-	{919, 921},
-	{921, 923},
-	{923, 925},
-	{925, 926},
-	{926, 927},
-	{927, 929},
-	{929, 931},
-	{931, 932},
-	{932, 933},
-	{933, 934},
-	{934, 935},
-	{935, 937},
-	{937, 939},
-	{939, 940},
-	{940, 941},
-	{941, 943},
-	{943, 944},
-	{944, 945},
-	{945, 946},
-	{946, 947},
-	{947, 948},
-	{948, 949},
-	{949, 950},
-	{950, 951},
-	{951, 952},
-	{952, 953},
-	{953, 954},
-	{954, 955},
-	{955, 956},
-	{956, 958},
-	{958, 960},
-	{960, 962},
-	{962, 963},
-	{963, 965},
-	{965, 967},
-	{967, 982},
-	{982, 983},
-	{983, 984},
-	{984, 987},
-	{987, 988},
-	{988, 989},
-	{989, 990},
-	{990, 992},
-	{992, 993},
-	{993, 994},
-	{994, 995},
-	{995, 996},
-	{996, 997},
-	{997, 999},
-	{999, 1001},
-	{1001, 1002},
-	{1002, 1003},
-	{1003, 1004},
-	{1004, 1005},
-	{1005, 1007},
-	{1007, 1009},
-	{1009, 1011},
-	{1011, 1015},
-	{1015, 1016},
-	{1016, 1020},
-	{1020, 1024},
-	{1024, 1028},
-	{1028, 1032},
-	{1032, 1036},
-	{1036, 1037},
-	{1037, 1041},
-	{1041, 1045},
-	{1045, 1047},
-	{1047, 1049},
-	{1049, 1051},
-	{1051, 1053},
-	{1053, 1055},
-	{1055, 1057},
-	{1057, 1059},
-	{1059, 1061},
-	{1061, 1062},
-	{1062, 1064},
-	{1064, 1066},
-	{1066, 1067},
-	{1067, 1069},
-	{1069, 1071},
-	{1071, 1073},
-	{1073, 1074},
-	{1074, 1076},
-	{1076, 1091},
-	{1091, 1092},
-	{1092, 1093},
-	{1093, 1094},
-	{1094, 1095},
-	{1095, 1096},
-	{1096, 1098},
-	{1098, 1113},
-	{1113, 1114},
-	{1114, 1115},
-	{1115, 1130},
-	{1130, 1132},
-	{1132, 1134},
-	{1134, 1135},
-	{1135, 1136},
-	{1136, 1139},
-	{1139, 1140},
-	{1140, 1141},
-	{1141, 1143},
-	{1143, 1158},
-	{1158, 1173},
-	{1173, 1175},
-	{1175, 1176},
-	{1176, 1177},
-	{1177, 1178},
-	{1178, 1179},
-	{1179, 1180},
-	{1180, 1181},
-	{1181, 1182},
-	{1182, 1183},
-	{1183, 1198},
-	{1198, 1200},
-	{1200, 1222},
-	{1222, 1223},
-	{1223, 1224},
-	{1224, 1225},
-	{1225, 1226},
-	{1226, 1228},
-	{1228, 1230},
-	{1230, 1232},
-	{1232, 1234},
-	{1234, 1236},
-	{1236, 1238},
-	{1238, 1240},
-	{1240, 1242},
-	{1242, 1244},
-	{1244, 1246},
-	{1246, 1248},
-	{1248, 1250},
-	{1250, 1252},
-	{1252, 1254},
-	{1254, 1256},
-	{1256, 1258},
-	{1258, 1259},
-	{1259, 1260},
-	{1260, 1275},
-	{1275, 1281},
-	{1281, 1283},
-	{1283, 1298},
-	{1298, 1304},
-	{1304, 1306},
-	{1306, 1307},
-	{1307, 1308},
-	{1308, 1309},
-	{1309, 1312},
-	{1312, 1315},
-	{1315, 1316},
-	{1316, 1317},
-	{1317, 1318},
-	{1318, 1319},
-	{1319, 1320},
-	{1320, 1321},
-	{1321, 1322},
-	{1322, 1323},
-	{1323, 1324},
-	{1324, 1325},
-	{1325, 1326},
-	{1326, 1327},
-	{1327, 1330},
-	{1330, 1352},
-	{1352, 1353},
-	{1353, 1354},
-	{1354, 1355},
-	{1355, 1356},
-	{1356, 1357},
-	{1357, 1358},
-	{1358, 1359},
-	{1359, 1361},
-	{1361, 1363},
-	{1363, 1377},
-	{1377, 1380},
-	{1380, 1381},
-	{1381, 1382},
-	{1382, 1383},
-	{1383, 1384},
-	{1384, 1385},
-	{1385, 1386},
-	{1386, 1387},
-	{1387, 1388},
-	{1388, 1390},
-	{1390, 1392},
-	{1392, 1393},
-	{1393, 1394},
-	{1394, 1396},
-	{1396, 1398},
-	{1398, 1399},
-	{1399, 1400},
-	{1400, 1401},
-	{1401, 1402},
-	{1402, 1403},
-	{1403, 1404},
-	{1404, 1406},
-	{1406, 1408},
-	{1408, 1410},
-	{1410, 1412},
-	{1412, 1414},
-	{1414, 1416},
-	{1416, 1418},
-	{1418, 1420},
-	{1420, 1421},
-	{1421, 1422},
-	{1422, 1424},
-	{1424, 1428},
-	{1428, 1430},
-	{1430, 1432},
-	{1432, 1433},
-	{1433, 1434},
-	{1434, 1435},
-	{1435, 1436},
-	{1436, 1438},
-	{1438, 1440},
-	{1440, 1442},
-	{1442, 1444},
-	{1444, 1446},
-	{1446, 1448},
-	{1448, 1450},
-	{1450, 1452},
-	{1452, 1454},
-	{1454, 1455},
-	{1455, 1457},
-	{1457, 1459},
-	{1459, 1460},
-	{1460, 1462},
-	{1462, 1464},
-	{1464, 1466},
-	{1466, 1468},
-	{1468, 1470},
-	{1470, 1472},
-	{1472, 1474},
-	{1474, 1475},
-	{1475, 1476},
-	{1476, 1477},
-	{1477, 1479},
-	{1479, 1480},
-	{1480, 1481},
-	{1481, 1482},
-	{1482, 1483},
-	{1483, 1484},
-	{1484, 1486},
-	{1486, 1488},
-	{1488, 1489},
-	{1489, 1490},
-	{1490, 1492},
-	{1492, 1494},
-	{1494, 1495},
-	{1495, 1496},
-	{1496, 1498},
-	{1498, 1500},
-	{1500, 1501},
-	{1501, 1502},
-	{1502, 1504},
-	{1504, 1506},
-	{1506, 1508},
-	{1508, 1510},
-	{1510, 1512},
-	{1512, 1514},
-	{1514, 1516},
-	{1516, 1518},
-	{1518, 1519},
-	{1519, 1520},
-	{1520, 1522},
-	{1522, 1524},
-	{1524, 1525},
-	{1525, 1526},
-	{1526, 1528},
-	{1528, 1530},
-	{1530, 1531},
-	{1531, 1532},
-	{1532, 1534},
-	{1534, 1536},
-	{1536, 1538},
-	{1538, 1540},
-	{1540, 1542},
-	{1542, 1544},
-	{1544, 1546},
-	{1546, 1548},
-	{1548, 1549},
-	{1549, 1550},
-	{1550, 1552},
-	{1552, 1554},
-	{1554, 1555},
-	{1555, 1556},
-	{1556, 1558},
-	{1558, 1560},
-	{1560, 1561},
-	{1561, 1562},
-	{1562, 1564},
-	{1564, 1566},
-	{1566, 1567},
-	{1567, 1568},
-	{1568, 1570},
-	{1570, 1572},
-	{1572, 1573},
-	{1573, 1574},
-	{1574, 1576},
-	{1576, 1578},
-	{1578, 1579},
-	{1579, 1580},
-	{1580, 1582},
-	{1582, 1584},
-	{1584, 1586},
-	{1586, 1588},
-	{1588, 1590},
-	{1590, 1592},
-	{1592, 1594},
-	{1594, 1596},
-	{1596, 1597},
-	{1597, 1598},
-	{1598, 1599},
-	{1599, 1601},
-	{1601, 1602},
-	{1602, 1603},
-	{1603, 1607},
-	{1607, 1611},
-	{1611, 1613},
-	{1613, 1615},
-	{1615, 1616},
-	{1616, 1617},
-	{1617, 1619},
-	{1619, 1621},
-	{1621, 1622},
-	{1622, 1623},
-	{1623, 1627},
-	{1627, 1631},
-	{1631, 1633},
-	{1633, 1635},
-	{1635, 1639},
-	{1639, 1643},
-	{1643, 1644},
-	{1644, 1646},
-	{1646, 1648},
-	{1648, 1649},
-	{1649, 1651},
-	{1651, 1653},
-	{1653, 1655},
-	{1655, 1657},
-	{1657, 1659},
-	{1659, 1661},
-	{1661, 1663},
-	{1663, 1665},
-	{1665, 1670},
-	{1670, 1674},
-	{1674, 1676},
-	{1676, 1678},
-	{1678, 1682},
-	{1682, 1686},
-	{1686, 1690},
-	{1690, 1692},
-	{1692, 1694},
-	{1694, 1696},
-	{1696, 1697},
-	{1697, 1698},
-	{1698, 1700},
-	{1700, 1702},
-	{1702, 1704},
-	{1704, 1706},
-	{1706, 1708},
-	{1708, 1710},
-	{1710, 1712},
-	{1712, 1714},
-	{1714, 1716},
-	{1716, 1718},
-	{1718, 1720},
-	{1720, 1722},
-	{1722, 1724},
-	{1724, 1726},
-	{1726, 1728},
-	{1728, 1730},
-	{1730, 1732},
-	{1732, 1734},
-	{1734, 1736},
-	{1736, 1738},
-	{1738, 1740},
-	{1740, 1742},
-	{1742, 1744},
-	{1744, 1746},
-	{1746, 1748},
-	{1748, 1750},
-	{1750, 1752},
-	{1752, 1754},
-	{1754, 1756},
-	{1756, 1757},
-	{1757, 1758},
-	{1758, 1760},
-	{1760, 1763},
-	{1763, 1765},
-	{1765, 1766},
-	{1766, 1767},
-	{1767, 1769},
-	{1769, 1771},
-	{1771, 1773},
-	{1773, 1775},
-	{1775, 1776},
-	{1776, 1777},
-	{1777, 1778},
-	{1778, 1779},
-	{1779, 1780},
-	{1780, 1784},
-	{1784, 1788},
-	{1788, 1789},
-	{1789, 1790},
-	{1790, 1791},
-	{1791, 1792},
-	{1792, 1793},
-	{1793, 1794},
-	{1794, 1796},
-	{1796, 1798},
-	{1798, 1800},
-	{1800, 1802},
-	{1802, 1804},
-	{1804, 1806},
-	{1806, 1808},
-	{1808, 1810},
-	{1810, 1811},
-	{1811, 1813},
-	{1813, 1815},
-	{1815, 1817},
-	{1817, 1818},
-	{1818, 1819},
-	{1819, 1820},
-	{1820, 1821},
-	{1821, 1823},
-	{1823, 1825},
-	{1825, 1829},
-	{1829, 1833},
-	{1833, 1835},
-	{1835, 1837},
-	{1837, 1839},
-	{1839, 1841},
-	{1841, 1843},
-	{1843, 1845},
-	{1845, 1847},
-	{1847, 1849},
-	{1849, 1851},
-	{1851, 1853},
-	{1853, 1855},
-	{1855, 1857},
-	{1857, 1859},
-	{1859, 1861},
-	{1861, 1863},
-	{1863, 1865},
-	{1865, 1866},
-	{1866, 1868},
-	{1868, 1869},
-	{1869, 1871},
-	{1871, 1873},
-	{1873, 1875},
-	{1875, 1876},
-	{1876, 1878},
-	{1878, 1879},
-	{1879, 1881},
-	{1881, 1883},
-	{1883, 1885},
-	{1885, 1887},
-	{1887, 1889},
-	{1889, 1891},
-	{1891, 1893},
-	{1893, 1895},
-	{1895, 1897},
-	{1897, 1899},
-	{1899, 1901},
-	{1901, 1903},
-	{1903, 1905},
-	{1905, 1907},
-	{1907, 1909},
-	{1909, 1911},
-	{1911, 1913},
-	{1913, 1915},
-	{1915, 1917},
-	{1917, 1919},
-	{1919, 1921},
-	{1921, 1925},
-	{1925, 1929},
-	{1929, 1931},
-	{1931, 1935},
-	{1935, 1937},
-	{1937, 1939},
-	{1939, 1941},
-	{1941, 1943},
-	{1943, 1945},
-	{1945, 1949},
-	{1949, 1951},
-	{1951, 1953},
-	{1953, 1955},
-	{1955, 1957},
-	{1957, 1959},
-	{1959, 1961},
-	{1961, 1963},
-	{1963, 1965},
-	{1965, 1967},
-	{1967, 1969},
-	{1969, 1971},
-	{1971, 1973},
-	{1973, 1975},
-	{1975, 1977},
-	{1977, 1979},
-	{1979, 1981},
-	{1981, 1983},
-	{1983, 1985},
-	{1985, 1987},
-	{1987, 1988},
-	{1988, 1990},
-	{1990, 1992},
-	{1992, 1993},
-	{1993, 1994},
-	{1994, 1996},
-	{1996, 1997},
-	{1997, 1999},
-	{1999, 2001},
-	{2001, 2003},
-	{2003, 2005},
-	{2005, 2006},
-	{2006, 2007},
-	{2007, 2008},
-	{2008, 2010},
-	{2010, 2012},
-	{2012, 2013},
-	{2013, 2014},
-	{2014, 2016},
-	{2016, 2018},
-	{2018, 2019},
-	{2019, 2020},
-	{2020, 2022},
-	{2022, 2024},
-	{2024, 2026},
-	{2026, 2028},
-	{2028, 2030},
-	{2030, 2032},
-	{2032, 2033},
-	{2033, 2034},
-	{2034, 2035},
-	{2035, 2037},
-	{2037, 2039},
-	{2039, 2040},
-	{2040, 2041},
-	{2041, 2046},
-	{2046, 2048},
-	{2048, 2064},
-	{2064, 2065},
-	{2065, 2066},
-	{2066, 2068},
-	{2068, 2090},
-	{2090, 2091},
-	{2091, 2092},
-	{2092, 2093},
-	{2093, 2094},
-	{2094, 2095},
-	{2095, 2096},
-	{2096, 2097},
-	{2097, 2098},
-	{2098, 2099},
-	{2099, 2100},
-	{2100, 2101},
-	{2101, 2102},
-	{2102, 2103},
-	{2103, 2104},
-	{2104, 2105},
-	{2105, 2106},
+var mnemonicSlots = [...]uint16{
+	0, 170, 0, 0, 28, 0, 805, 0, 0, 0, 0, 0, 970, 297, 0, 0,
+	345, 0, 0, 0, 19, 0, 0, 0, 733, 0, 0, 0, 0, 679, 702, 0,
+	0, 0, 0, 0, 672, 0, 1030, 0, 1276, 0, 0, 317, 0, 0, 0, 1076,
+	488, 0, 0, 0, 0, 0, 833, 180, 991, 0, 419, 0, 0, 0, 126, 0,
+	1094, 0, 0, 0, 506, 0, 0, 0, 1106, 1238, 0, 830, 0, 0, 0, 0,
+	495, 0, 0, 0, 1018, 490, 1279, 1097, 0, 51, 471, 0, 0, 0, 0, 0,
+	0, 0, 335, 0, 0, 0, 0, 895, 718, 0, 0, 0, 0, 0, 0, 1032,
+	0, 0, 780, 0, 1102, 0, 0, 0, 826, 913, 0, 0, 0, 722, 0, 862,
+	0, 1006, 69, 879, 492, 0, 0, 141, 0, 1196, 0, 1201, 0, 0, 151, 0,
+	90, 0, 207, 0, 1166, 0, 0, 0, 0, 0, 0, 951, 0, 0, 40, 939,
+	825, 0, 1137, 410, 0, 1152, 0, 0, 0, 63, 0, 883, 0, 0, 0, 0,
+	614, 0, 0, 662, 0, 964, 0, 245, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 863, 534, 0, 0, 103, 358, 496, 0, 0, 1289, 1003, 0, 0,
+	0, 0, 0, 300, 0, 0, 0, 393, 0, 0, 0, 0, 0, 1156, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 443, 0, 171, 0, 68, 0, 0,
+	0, 0, 529, 0, 0, 0, 0, 0, 854, 0, 0, 0, 134, 0, 0, 0,
+	0, 0, 1069, 0, 545, 0, 0, 0, 0, 706, 0, 0, 0, 1204, 0, 127,
+	1267, 0, 0, 0, 0, 0, 0, 1026, 0, 0, 0, 0, 0, 0, 1049, 0,
+	0, 1266, 1016, 0, 0, 519, 0, 851, 0, 0, 0, 652, 0, 500, 0, 198,
+	0, 0, 0, 0, 620, 1052, 827, 0, 0, 0, 0, 0, 982, 0, 1081, 1275,
+	0, 0, 0, 797, 1115, 0, 0, 0, 1002, 0, 0, 995, 0, 0, 0, 0,
+	0, 0, 847, 72, 533, 0, 0, 1273, 0, 0, 0, 859, 1190, 1061, 0, 1147,
+	124, 0, 0, 0, 0, 0, 118, 0, 0, 448, 0, 0, 924, 0, 0, 0,
+	0, 0, 0, 464, 0, 0, 122, 0, 0, 0, 0, 0, 1185, 1254, 78, 0,
+	0, 380, 0, 0, 0, 0, 0, 0, 0, 0, 0, 860, 1057, 0, 0, 0,
+	0, 0, 897, 538, 223, 0, 0, 0, 828, 1149, 1200, 1177, 0, 0, 0, 0,
+	0, 0, 1239, 0, 0, 0, 0, 556, 1098, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 164, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 781, 0, 1167, 0, 633, 0, 0, 0, 765, 0, 0, 514, 0,
+	0, 0, 193, 411, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 707, 0,
+	425, 253, 1021, 0, 272, 6, 0, 0, 0, 0, 0, 618, 605, 1093, 0, 0,
+	589, 1229, 0, 0, 0, 691, 259, 0, 0, 0, 0, 596, 0, 0, 0, 0,
+	418, 457, 0, 0, 0, 61, 0, 0, 406, 0, 637, 0, 286, 0, 0, 1078,
+	0, 0, 0, 0, 0, 0, 340, 1108, 0, 0, 1126, 758, 0, 1153, 0, 0,
+	0, 0, 215, 0, 0, 0, 0, 0, 0, 0, 0, 288, 0, 0, 1163, 0,
+	0, 41, 543, 0, 0, 0, 0, 763, 1130, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 384, 0, 315, 0, 312, 0, 1082, 0, 0,
+	0, 1209, 0, 0, 0, 0, 0, 0, 0, 509, 0, 1044, 371, 403, 0, 0,
+	0, 0, 0, 0, 339, 238, 0, 0, 0, 853, 461, 791, 0, 0, 0, 0,
+	0, 894, 0, 0, 0, 1005, 0, 0, 0, 976, 0, 456, 0, 1103, 1257, 659,
+	0, 0, 0, 0, 1224, 0, 0, 178, 102, 0, 528, 1101, 0, 1143, 0, 0,
+	117, 0, 1146, 0, 0, 0, 0, 0, 0, 0, 0, 966, 0, 0, 112, 0,
+	0, 714, 0, 661, 0, 0, 0, 1051, 132, 0, 903, 615, 0, 0, 1062, 0,
+	0, 0, 0, 0, 0, 0, 987, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	578, 0, 0, 0, 0, 0, 0, 0, 0, 961, 0, 1291, 354, 0, 0, 660,
+	0, 93, 0, 0, 0, 0, 0, 0, 594, 0, 0, 0, 0, 0, 1141, 655,
+	0, 0, 1179, 0, 0, 0, 0, 0, 0, 1048, 0, 657, 342, 0, 0, 815,
+	0, 778, 0, 0, 0, 0, 0, 0, 0, 0, 1210, 439, 0, 0, 0, 0,
+	0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 816, 0, 0, 0, 1170, 927, 0, 646, 0, 0, 0, 277, 0, 0,
+	162, 0, 0, 625, 0, 140, 0, 0, 0, 0, 348, 0, 0, 622, 458, 0,
+	0, 0, 0, 1277, 0, 1092, 730, 0, 1134, 937, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 1258, 473, 0, 0, 0, 0, 0, 788, 1054, 0, 47, 0,
+	80, 0, 0, 101, 0, 204, 282, 0, 0, 320, 0, 636, 409, 0, 469, 508,
+	0, 1072, 1009, 0, 768, 0, 293, 0, 1107, 0, 0, 0, 878, 0, 0, 0,
+	0, 667, 417, 0, 0, 367, 686, 0, 0, 865, 790, 0, 0, 0, 0, 0,
+	567, 0, 0, 285, 487, 0, 0, 0, 0, 822, 1292, 0, 0, 0, 0, 441,
+	0, 0, 0, 1184, 454, 0, 0, 0, 0, 0, 1017, 32, 0, 1014, 0, 459,
+	161, 352, 0, 0, 242, 0, 0, 105, 899, 0, 0, 226, 0, 0, 0, 0,
+	0, 518, 1236, 0, 263, 0, 36, 0, 0, 0, 0, 0, 0, 1228, 0, 0,
+	45, 0, 0, 0, 0, 481, 0, 0, 587, 649, 0, 536, 0, 1198, 1104, 0,
+	0, 0, 0, 0, 955, 0, 0, 0, 0, 0, 0, 0, 0, 1059, 632, 0,
+	0, 0, 0, 911, 0, 0, 840, 0, 0, 670, 990, 0, 540, 0, 94, 0,
+	0, 0, 0, 128, 0, 0, 968, 0, 0, 1022, 0, 0, 0, 842, 0, 0,
+	0, 1171, 0, 0, 0, 1284, 0, 0, 0, 0, 0, 0, 906, 0, 56, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1264, 0, 1087, 305, 71,
+	0, 0, 398, 544, 0, 0, 0, 949, 0, 0, 0, 0, 0, 0, 0, 648,
+	754, 0, 0, 0, 0, 0, 0, 0, 131, 1133, 0, 0, 0, 0, 177, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 734, 154, 0,
+	0, 467, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 925, 385, 0,
+	58, 0, 574, 817, 0, 0, 0, 163, 931, 0, 1271, 0, 0, 0, 1038, 0,
+	0, 0, 0, 0, 0, 292, 0, 553, 329, 0, 0, 0, 1280, 0, 144, 0,
+	0, 0, 0, 969, 0, 0, 0, 1127, 0, 0, 0, 0, 1007, 0, 0, 892,
+	0, 0, 0, 0, 1287, 0, 474, 0, 0, 0, 0, 0, 0, 520, 0, 613,
+	0, 1029, 0, 0, 709, 0, 0, 0, 0, 401, 0, 0, 901, 0, 923, 0,
+	743, 994, 0, 777, 0, 168, 0, 0, 0, 0, 22, 510, 332, 175, 0, 1084,
+	0, 0, 0, 0, 213, 0, 0, 749, 0, 0, 856, 0, 0, 0, 767, 0,
+	936, 0, 1119, 0, 0, 824, 0, 0, 0, 690, 0, 0, 0, 0, 0, 1231,
+	0, 0, 0, 0, 337, 0, 0, 0, 0, 0, 1074, 0, 0, 0, 0, 831,
+	0, 0, 0, 0, 8, 0, 861, 0, 0, 0, 364, 0, 0, 0, 0, 1055,
+	0, 0, 0, 1237, 0, 0, 0, 0, 0, 0, 0, 322, 0, 820, 0, 1037,
+	0, 493, 187, 975, 792, 663, 829, 209, 357, 0, 0, 0, 551, 0, 0, 0,
+	0, 53, 0, 0, 0, 0, 0, 0, 0, 644, 0, 0, 0, 0, 0, 0,
+	0, 92, 0, 0, 0, 0, 0, 0, 153, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 922, 0, 0, 0, 846, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 740, 0, 1189, 857, 0, 0, 0, 0, 0, 0, 0, 1203, 0, 149, 0,
+	0, 0, 697, 0, 0, 0, 0, 757, 0, 530, 0, 0, 0, 0, 0, 0,
+	191, 0, 0, 123, 0, 0, 552, 413, 0, 0, 0, 546, 0, 896, 16, 888,
+	0, 0, 0, 0, 216, 0, 1058, 60, 0, 0, 0, 0, 0, 0, 0, 537,
+	0, 0, 0, 0, 0, 0, 0, 470, 606, 0, 0, 0, 789, 0, 0, 0,
+	120, 0, 0, 0, 64, 599, 0, 0, 0, 0, 479, 0, 0, 0, 1116, 0,
+	0, 0, 681, 0, 0, 0, 84, 630, 0, 1263, 0, 1114, 0, 0, 0, 717,
+	87, 0, 776, 0, 159, 952, 30, 183, 0, 884, 0, 262, 0, 0, 0, 235,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 647, 0, 0,
+	0, 1186, 0, 917, 0, 0, 0, 1, 0, 0, 642, 75, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 600, 1244, 770, 0, 934, 1286, 0,
+	0, 310, 0, 0, 138, 755, 0, 759, 0, 429, 0, 0, 0, 0, 210, 889,
+	1155, 0, 0, 0, 347, 0, 0, 1079, 1173, 0, 0, 0, 0, 0, 0, 1158,
+	800, 0, 958, 0, 0, 0, 0, 0, 0, 1012, 0, 0, 0, 0, 0, 394,
+	0, 485, 0, 0, 0, 83, 1178, 0, 0, 486, 0, 0, 1169, 0, 1240, 1067,
+	0, 0, 0, 0, 0, 1183, 0, 0, 0, 539, 0, 0, 0, 185, 0, 0,
+	875, 0, 266, 421, 0, 165, 0, 0, 25, 0, 0, 273, 0, 0, 34, 938,
+	0, 1241, 1188, 0, 0, 0, 0, 0, 303, 0, 0, 0, 0, 1100, 0, 0,
+	0, 0, 760, 0, 696, 0, 0, 0, 0, 577, 269, 0, 201, 0, 0, 735,
+	0, 0, 0, 0, 773, 0, 1248, 0, 0, 0, 0, 0, 294, 0, 0, 0,
+	0, 100, 0, 0, 0, 0, 54, 0, 0, 1191, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 12, 445, 0, 0, 0, 590, 1036, 0, 0, 880, 449, 0,
+	219, 0, 0, 989, 0, 0, 0, 0, 868, 0, 427, 0, 0, 656, 374, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 414, 0, 0, 0, 0, 0, 0, 0,
+	715, 0, 0, 908, 0, 0, 611, 0, 0, 1112, 249, 0, 0, 88, 0, 0,
+	5, 0, 0, 0, 0, 0, 279, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 17, 0, 434, 1063, 0, 658, 0, 0, 0, 0, 0, 0, 1089, 0, 573,
+	0, 527, 0, 547, 0, 0, 0, 0, 0, 0, 0, 0, 692, 0, 0, 0,
+	1111, 0, 0, 0, 0, 0, 1202, 0, 0, 0, 0, 0, 0, 837, 876, 1023,
+	0, 0, 0, 0, 775, 751, 0, 1172, 1255, 299, 0, 0, 476, 0, 0, 0,
+	0, 33, 532, 265, 809, 156, 0, 585, 0, 0, 0, 0, 1165, 435, 106, 400,
+	0, 361, 0, 1159, 202, 0, 0, 0, 0, 0, 1216, 0, 515, 0, 0, 0,
+	0, 0, 0, 0, 503, 208, 0, 1124, 0, 0, 0, 0, 57, 0, 845, 719,
+	0, 1131, 237, 355, 916, 42, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 231, 0, 581, 0, 0, 0, 466, 0, 0, 0, 0, 0, 0, 0, 160,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 275, 0, 0, 0, 0, 370, 0, 0, 566, 408, 0, 0,
+	0, 27, 0, 0, 1070, 0, 0, 0, 0, 0, 0, 1056, 0, 550, 0, 560,
+	0, 316, 744, 623, 150, 0, 680, 1015, 0, 0, 0, 0, 475, 0, 0, 0,
+	0, 1027, 0, 761, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1222, 0, 318, 0, 0, 0, 241, 1265, 0, 0, 188, 0, 0, 0, 0, 1281,
+	0, 0, 629, 711, 0, 943, 1211, 0, 0, 0, 24, 0, 0, 852, 0, 99,
+	0, 561, 0, 983, 0, 280, 412, 0, 0, 0, 873, 835, 0, 0, 0, 1282,
+	569, 0, 0, 548, 0, 0, 0, 48, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 494, 0, 0, 116, 0, 0, 0, 0, 0, 0, 0, 945, 962,
+	0, 0, 689, 0, 0, 531, 890, 89, 0, 1109, 0, 0, 0, 542, 1274, 0,
+	0, 0, 0, 373, 806, 1180, 321, 0, 1260, 957, 0, 0, 0, 0, 236, 0,
+	0, 0, 0, 0, 505, 0, 0, 0, 396, 0, 38, 0, 0, 0, 0, 631,
+	0, 0, 1040, 0, 0, 0, 0, 0, 814, 0, 0, 0, 0, 0, 0, 0,
+	0, 452, 0, 0, 0, 350, 0, 37, 699, 0, 0, 0, 0, 0, 1043, 0,
+	1020, 0, 0, 0, 0, 0, 0, 874, 0, 0, 0, 0, 0, 992, 0, 0,
+	0, 0, 0, 463, 0, 0, 0, 0, 0, 0, 0, 306, 0, 1013, 638, 0,
+	0, 268, 0, 276, 0, 67, 70, 0, 0, 0, 0, 1217, 0, 0, 0, 0,
+	0, 0, 0, 0, 438, 0, 0, 0, 1125, 0, 341, 1154, 1132, 0, 716, 0,
+	0, 984, 0, 0, 0, 432, 721, 0, 152, 283, 0, 0, 598, 428, 914, 0,
+	0, 0, 624, 0, 0, 155, 750, 0, 0, 0, 0, 1047, 0, 437, 0, 0,
+	0, 133, 1034, 0, 0, 738, 381, 1019, 0, 586, 0, 1083, 0, 0, 0, 0,
+	0, 0, 610, 0, 0, 641, 0, 0, 220, 0, 0, 402, 0, 0, 0, 331,
+	136, 762, 0, 1144, 0, 0, 387, 0, 460, 0, 701, 0, 1269, 1256, 953, 0,
+	0, 0, 0, 1004, 731, 881, 0, 0, 0, 0, 0, 1065, 0, 0, 0, 986,
+	0, 0, 0, 0, 0, 960, 0, 0, 0, 849, 1088, 0, 234, 0, 0, 0,
+	0, 483, 0, 0, 250, 0, 0, 389, 998, 557, 808, 147, 139, 942, 0, 430,
+	0, 0, 0, 248, 0, 0, 0, 251, 1138, 0, 0, 23, 0, 0, 0, 26,
+	301, 0, 324, 0, 0, 501, 0, 440, 0, 1118, 0, 0, 0, 1113, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 145, 0, 0, 650, 0,
+	756, 1073, 1199, 0, 0, 0, 0, 0, 0, 0, 0, 864, 705, 0, 0, 785,
+	0, 0, 0, 0, 0, 0, 524, 0, 137, 0, 0, 558, 810, 996, 0, 0,
+	783, 49, 0, 44, 243, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	206, 0, 0, 0, 0, 0, 0, 0, 902, 0, 404, 0, 0, 0, 0, 576,
+	1161, 0, 972, 0, 0, 928, 0, 593, 0, 0, 0, 1194, 0, 0, 1250, 1215,
+	0, 0, 346, 985, 0, 855, 0, 0, 0, 0, 0, 0, 1247, 933, 110, 0,
+	1213, 0, 0, 0, 415, 395, 0, 0, 0, 0, 0, 0, 0, 0, 981, 0,
+	214, 0, 0, 0, 1122, 330, 386, 0, 0, 383, 167, 886, 0, 0, 639, 979,
+	0, 512, 0, 974, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 603,
+	0, 0, 169, 0, 0, 86, 887, 264, 0, 0, 0, 0, 668, 0, 732, 13,
+	369, 0, 0, 0, 0, 0, 98, 462, 1245, 295, 0, 0, 278, 0, 0, 0,
+	570, 246, 308, 0, 0, 0, 0, 1192, 0, 0, 0, 0, 0, 0, 786, 356,
+	0, 0, 344, 76, 0, 1028, 0, 382, 0, 0, 0, 583, 0, 0, 0, 0,
+	74, 764, 0, 0, 491, 0, 0, 468, 0, 221, 0, 0, 950, 635, 0, 0,
+	0, 0, 189, 0, 0, 114, 516, 1136, 0, 746, 62, 1242, 0, 0, 0, 172,
+	0, 793, 0, 932, 0, 801, 588, 0, 0, 158, 0, 0, 997, 65, 0, 46,
+	0, 1278, 0, 0, 135, 0, 200, 0, 0, 0, 0, 255, 0, 0, 739, 0,
+	0, 0, 1140, 115, 940, 0, 0, 194, 0, 0, 0, 1139, 0, 0, 0, 684,
+	0, 0, 376, 0, 0, 0, 478, 0, 244, 190, 803, 0, 0, 0, 0, 872,
+	0, 0, 0, 0, 0, 0, 0, 334, 174, 0, 0, 0, 0, 1129, 0, 0,
+	563, 0, 0, 737, 0, 0, 0, 978, 309, 926, 0, 0, 227, 0, 0, 1162,
+	0, 0, 9, 484, 0, 0, 0, 1011, 363, 0, 1195, 1205, 0, 0, 1046, 0,
+	0, 0, 0, 0, 0, 73, 0, 1142, 0, 0, 0, 0, 1207, 0, 0, 988,
+	0, 0, 812, 0, 0, 0, 0, 0, 1010, 0, 0, 0, 1096, 0, 1157, 0,
+	0, 748, 0, 0, 0, 0, 0, 0, 0, 0, 870, 1121, 0, 0, 0, 673,
+	575, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1075,
+	0, 769, 0, 291, 0, 0, 0, 0, 0, 0, 0, 184, 252, 0, 0, 0,
+	595, 1091, 0, 0, 713, 0, 666, 948, 0, 0, 0, 0, 930, 0, 0, 0,
+	0, 0, 256, 0, 232, 0, 59, 0, 0, 482, 584, 0, 0, 0, 0, 1135,
+	0, 0, 0, 233, 424, 0, 0, 0, 0, 0, 592, 0, 0, 0, 390, 343,
+	0, 0, 651, 0, 946, 366, 920, 0, 261, 0, 0, 745, 0, 0, 0, 0,
+	0, 0, 877, 0, 0, 0, 804, 0, 675, 325, 0, 0, 0, 0, 0, 956,
+	1120, 39, 0, 420, 0, 0, 0, 0, 688, 0, 0, 0, 0, 0, 0, 0,
+	196, 0, 0, 0, 0, 0, 0, 199, 0, 0, 0, 197, 0, 0, 728, 0,
+	0, 0, 774, 0, 0, 0, 784, 0, 0, 907, 480, 0, 0, 0, 0, 612,
+	0, 0, 1066, 0, 1042, 0, 298, 0, 918, 0, 0, 0, 0, 287, 0, 0,
+	217, 205, 0, 0, 0, 0, 0, 0, 0, 905, 1128, 0, 327, 1262, 0, 66,
+	807, 0, 0, 0, 0, 0, 399, 267, 391, 0, 0, 0, 0, 0, 0, 832,
+	121, 0, 634, 742, 0, 0, 1214, 0, 0, 0, 0, 0, 0, 1045, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 1283, 0, 766, 0, 802, 472, 626, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 954, 904, 447, 823, 559, 0, 0,
+	0, 1008, 0, 0, 0, 0, 0, 0, 513, 0, 619, 229, 0, 0, 0, 0,
+	0, 0, 0, 10, 142, 0, 602, 0, 0, 444, 0, 0, 0, 1220, 523, 0,
+	222, 818, 0, 442, 693, 0, 0, 0, 166, 1077, 0, 0, 0, 616, 0, 0,
+	0, 0, 1145, 0, 108, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 465, 1175, 0, 0, 349, 869, 0, 0, 640, 55,
+	1025, 0, 359, 0, 0, 0, 909, 0, 0, 900, 0, 179, 0, 0, 1182, 0,
+	0, 146, 0, 0, 0, 921, 218, 0, 0, 0, 0, 0, 504, 0, 0, 555,
+	0, 35, 0, 0, 0, 0, 0, 0, 967, 0, 1095, 0, 405, 1259, 0, 0,
+	0, 0, 446, 0, 698, 1243, 0, 239, 0, 0, 0, 1068, 52, 296, 0, 0,
+	195, 338, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 834, 0, 0, 0, 313, 0, 0, 0, 700, 0, 1227, 525, 683, 0, 0,
+	0, 841, 0, 489, 0, 378, 0, 0, 82, 0, 0, 0, 0, 0, 0, 0,
+	959, 0, 604, 109, 130, 0, 1223, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	848, 1197, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	29, 0, 0, 0, 0, 0, 377, 0, 0, 565, 0, 1268, 0, 866, 0, 0,
+	499, 0, 0, 0, 0, 0, 971, 0, 0, 0, 944, 752, 0, 0, 290, 677,
+	0, 0, 993, 1099, 980, 0, 0, 0, 0, 0, 0, 1176, 212, 0, 671, 843,
+	867, 819, 1050, 571, 0, 0, 0, 511, 270, 0, 230, 85, 433, 0, 0, 0,
+	712, 0, 0, 0, 1208, 0, 0, 0, 0, 554, 0, 0, 0, 0, 507, 0,
+	0, 0, 1060, 0, 521, 708, 0, 0, 664, 0, 0, 0, 0, 0, 0, 0,
+	407, 0, 0, 929, 0, 526, 0, 710, 0, 257, 0, 682, 882, 535, 0, 0,
+	0, 898, 549, 0, 0, 0, 314, 0, 0, 1033, 0, 1206, 1181, 0, 729, 1024,
+	0, 582, 0, 97, 0, 912, 1249, 0, 0, 0, 0, 839, 0, 0, 0, 0,
+	0, 1261, 0, 720, 0, 0, 0, 0, 0, 0, 0, 0, 517, 771, 617, 0,
+	0, 0, 1039, 0, 0, 0, 0, 541, 96, 0, 1272, 522, 0, 0, 0, 0,
+	211, 0, 0, 0, 0, 871, 0, 0, 0, 0, 977, 423, 645, 176, 0, 0,
+	1233, 0, 0, 591, 333, 695, 0, 0, 0, 0, 173, 0, 0, 0, 703, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 844, 0, 77, 104, 1218, 1090, 20, 0,
+	1252, 0, 0, 43, 779, 782, 0, 15, 0, 0, 0, 0, 0, 0, 0, 1080,
+	0, 0, 601, 0, 0, 0, 451, 0, 0, 0, 0, 436, 674, 0, 0, 794,
+	0, 1270, 7, 727, 915, 0, 0, 274, 1150, 0, 125, 0, 694, 0, 0, 0,
+	0, 0, 0, 1230, 0, 0, 0, 1290, 0, 0, 0, 0, 181, 111, 0, 260,
+	0, 0, 0, 0, 0, 1212, 0, 497, 450, 676, 753, 0, 0, 0, 0, 0,
+	129, 850, 0, 838, 455, 0, 0, 0, 2, 0, 0, 0, 1031, 1168, 0, 0,
+	0, 0, 0, 375, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1086,
+	0, 0, 0, 1064, 963, 0, 0, 0, 0, 0, 0, 1148, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 21, 0, 0, 0, 416, 665, 0, 0, 0,
+	0, 11, 935, 0, 1187, 0, 919, 0, 678, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 947, 0, 0, 379, 0, 0, 579, 724,
+	0, 654, 0, 628, 0, 319, 0, 0, 0, 0, 0, 0, 0, 0, 224, 0,
+	0, 203, 973, 502, 365, 0, 0, 0, 0, 0, 0, 0, 0, 81, 0, 326,
+	0, 0, 0, 281, 0, 0, 422, 0, 0, 836, 0, 0, 0, 0, 1193, 0,
+	0, 0, 799, 0, 397, 0, 858, 0, 0, 0, 1225, 0, 0, 1110, 0, 372,
+	736, 0, 1221, 0, 0, 0, 0, 0, 723, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 143, 704, 0, 0, 0, 0, 811, 0, 1085, 0, 0, 1117, 741,
+	0, 1164, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 304, 747, 0,
+	388, 0, 107, 0, 0, 564, 0, 0, 0, 0, 0, 1234, 0, 0, 392, 0,
+	1041, 0, 726, 0, 580, 0, 0, 627, 0, 0, 1071, 0, 0, 362, 0, 0,
+	772, 0, 0, 0, 0, 0, 685, 1105, 621, 1246, 0, 0, 0, 307, 0, 687,
+	0, 271, 0, 0, 0, 0, 0, 0, 228, 0, 0, 999, 0, 258, 0, 0,
+	31, 0, 609, 0, 653, 0, 0, 0, 607, 0, 0, 0, 0, 0, 0, 0,
+	572, 1226, 813, 0, 0, 0, 353, 0, 0, 0, 0, 669, 1219, 0, 247, 225,
+	0, 0, 0, 0, 0, 95, 0, 0, 0, 0, 0, 157, 0, 0, 0, 182,
+	368, 0, 0, 431, 0, 0, 0, 0, 498, 79, 289, 0, 0, 0, 0, 119,
+	0, 453, 787, 0, 0, 0, 0, 0, 477, 0, 0, 0, 0, 0, 0, 608,
+	1035, 0, 0, 0, 0, 1232, 0, 1235, 0, 0, 0, 0, 910, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 643, 0, 0, 1123, 0, 0, 891,
+	0, 0, 1151, 240, 0, 1251, 0, 0, 186, 0, 1174, 0, 0, 0, 50, 336,
+	0, 0, 0, 0, 568, 0, 0, 0, 0, 0, 0, 0, 0, 426, 1285, 0,
+	0, 0, 0, 0, 360, 0, 965, 0, 1253, 0, 0, 0, 0, 597, 0, 0,
+	0, 0, 148, 885, 0, 0, 1288, 311, 0, 0, 0, 941, 0, 0, 1000, 0,
+	0, 0, 1053, 0, 0, 254, 0, 0, 0, 0, 0, 328, 0, 1160, 562, 0,
+	323, 14, 0, 0, 0, 18, 113, 0, 0, 0, 302, 0, 0, 4, 821, 0,
+	0, 0, 893, 798, 0, 192, 795, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	351, 0, 796, 725, 91, 284, 0, 1001, 0, 0, 0, 0, 0, 0, 0, 0,
 }
@@ -0,0 +1,276 @@
+package x86enc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Info describes the effects of an instruction beyond its encoding, for
+// register allocators and schedulers. mkenc attaches the metadata of each
+// form in the table from meta.csv, and Describe adjusts it for the operands,
+// prefixes, and mode of a particular instruction.
+type Info struct {
+	// Access is the access of each operand, in the order given, excluding
+	// prefixes and rounding controls. Immediates are read, and operands
+	// which are neither read nor written, like the memory operand of LEA,
+	// have no access.
+	Access []Access
+	// Reads and Writes are the registers the instruction reads and writes
+	// without naming them as operands, like RDX and RAX for DIV, including
+	// omitted fixed operands like the XMM0 of BLENDVPS and opmasks.
+	Reads, Writes []Reg
+	// FlagsRead and FlagsWritten are the status flags the instruction reads
+	// and writes. FlagsWritten includes flags left undefined.
+	FlagsRead, FlagsWritten Flags
+	// Memory is the instruction's access to memory, through its operands or
+	// implicitly, as for PUSH and MOVSB.
+	Memory Access
+	// Latency is the class of the instruction's latency and throughput.
+	Latency Latency
+}
+
+// Access is a kind of access to an operand or to memory.
+type Access uint8
+
+// Accesses.
+const (
+	Read Access = 1 << iota
+	Write
+
+	ReadWrite = Read | Write
+)
+
+func (a Access) String() string {
+	switch a {
+	case 0:
+		return "none"
+	case Read:
+		return "read"
+	case Write:
+		return "write"
+	case ReadWrite:
+		return "read-write"
+	}
+	return fmt.Sprintf("Access(%d)", uint8(a))
+}
+
+// Flags is a set of the status flags and the direction flag of RFLAGS.
+type Flags uint8
+
+// Flags.
+const (
+	CF Flags = 1 << iota // carry
+	PF                   // parity
+	AF                   // auxiliary carry
+	ZF                   // zero
+	SF                   // sign
+	DF                   // direction
+	OF                   // overflow
+)
+
+var flagStrings = [...]string{"CF", "PF", "AF", "ZF", "SF", "DF", "OF"}
+
+// String returns the names of the flags in the set separated by |, or "none"
+// if the set is empty.
+func (f Flags) String() string {
+	if f == 0 {
+		return "none"
+	}
+	var s []string
+	for i, n := range flagStrings {
+		if f&(1<<uint(i)) != 0 {
+			s = append(s, n)
+		}
+	}
+	return strings.Join(s, "|")
+}
+
+// Latency is a coarse class of the latency and throughput of instructions on
+// recent Intel and AMD processors.
+type Latency uint8
+
+// Latency classes.
+const (
+	// LatencyFast instructions, like ADD and MOV, take about a cycle, with
+	// several per cycle.
+	LatencyFast Latency = iota
+	// LatencyShuffle instructions move data between vector lanes or
+	// registers, like PSHUFB, taking a cycle or a few on a limited set of
+	// ports.
+	LatencyShuffle
+	// LatencyMedium instructions, like IMUL and ADDPS, take three to five
+	// cycles, pipelined.
+	LatencyMedium
+	// LatencyDivide instructions, like DIV and SQRTPD, take tens of cycles
+	// and are not fully pipelined.
+	LatencyDivide
+	// LatencyBranch instructions transfer control.
+	LatencyBranch
+	// LatencyGather instructions load or store scattered elements, taking
+	// a cycle or more per element.
+	LatencyGather
+	// LatencyMicrocode instructions run microcode sequences or serialize
+	// the processor, like CPUID and REP MOVSB.
+	LatencyMicrocode
+)
+
+var latencyStrings = [...]string{"fast", "shuffle", "medium", "divide", "branch", "gather", "microcode"}
+
+func (l Latency) String() string {
+	if int(l) < len(latencyStrings) {
+		return latencyStrings[l]
+	}
+	return fmt.Sprintf("Latency(%d)", uint8(l))
+}
+
+// Describe describes the instruction which Encode would encode for 64-bit
+// mode on the processor running the program.
+func Describe(op string, args ...Operand) (Info, error) {
+	return hostCPU.Describe(op, args...)
+}
+
+// Describe describes the instruction which the Encoder's Encode would encode
+// for the target.
+func (e *Encoder) Describe(op string, args ...Operand) (Info, error) {
+	_, r, err := e.choose(op, args)
+	if err != nil {
+		return Info{}, err
+	}
+	mode, _ := e.mode()
+	return describe(r, args, mode), nil
+}
+
+// DescribeForm describes an instruction using a form named as for EncodeForm
+// in 64-bit mode on the processor running the program.
+func DescribeForm(form string, args ...Operand) (Info, error) {
+	return hostCPU.DescribeForm(form, args...)
+}
+
+// DescribeForm describes an instruction using a form named as for the
+// Encoder's EncodeForm.
+func (e *Encoder) DescribeForm(form string, args ...Operand) (Info, error) {
+	if _, err := e.EncodeForm(form, args...); err != nil {
+		return Info{}, err
+	}
+	mode, _ := e.mode()
+	f := strings.FieldsFunc(form, func(c rune) bool { return c == ' ' || c == ',' })
+	rows := lookup(f[0])
+	for i := range rows {
+		if r := &rows[i]; r.valid(mode) && r.is(f[1:]) {
+			return describe(r, args, mode), nil
+		}
+	}
+	panic("x86enc: EncodeForm used no row for " + form)
+}
+
+// describe returns the metadata of a row adjusted for the operands it
+// encodes in a mode.
+func describe(r *instruction, args []Operand, mode int) Info {
+	m := &metaTable[rowMeta[r.index()]]
+	args, ps := splitPrefixes(args)
+	ops, _ := splitRounding(args)
+	info := Info{
+		FlagsRead:    m.flagsRead,
+		FlagsWritten: m.flagsWritten,
+		Memory:       m.mem,
+		Latency:      m.latency,
+	}
+	if len(ops) > 0 {
+		info.Access = append([]Access(nil), m.access[:len(ops)]...)
+	}
+	addrsize := false
+	for _, p := range ps {
+		switch p {
+		case REP, REPN:
+			info.Reads = appendReg(info.Reads, RCX)
+			info.Writes = appendReg(info.Writes, RCX)
+			if op := r.op.String(); strings.HasPrefix(op, "CMPS") || strings.HasPrefix(op, "SCAS") {
+				info.FlagsRead |= ZF
+			}
+		case ADDRSIZE:
+			addrsize = true
+		}
+	}
+	for _, reg := range m.reads {
+		info.Reads = appendReg(info.Reads, reg)
+	}
+	for _, reg := range m.writes {
+		info.Writes = appendReg(info.Writes, reg)
+	}
+	if n := r.nargs(); n > len(ops) {
+		// The omitted operand is a fixed register, like the XMM0 of
+		// BLENDVPS.
+		if reg, ok := kindTable[r.args[n-1]].fixed.(Reg); ok {
+			if m.access[n-1]&Read != 0 {
+				info.Reads = appendReg(info.Reads, reg)
+			}
+			if m.access[n-1]&Write != 0 {
+				info.Writes = appendReg(info.Writes, reg)
+			}
+		}
+	}
+	for i, o := range ops {
+		if mo, ok := o.(Masked); ok {
+			o = mo.Op
+			if mo.Mask != K0 {
+				info.Reads = appendReg(info.Reads, mo.Mask)
+				if m.writesMask {
+					info.Writes = appendReg(info.Writes, mo.Mask)
+				}
+				if _, mem := o.(Mem); !mem && !mo.Zero && info.Access[i]&Write != 0 {
+					// Merge masking keeps the elements whose bits are
+					// clear.
+					info.Access[i] |= Read
+				}
+			}
+		}
+		if _, ok := o.(Mem); ok {
+			info.Memory |= info.Access[i]
+		}
+	}
+	info.Reads = modeRegs(info.Reads, mode, addrsize)
+	info.Writes = modeRegs(info.Writes, mode, addrsize)
+	return info
+}
+
+// appendReg appends a register to a list of registers if it is not already
+// present.
+func appendReg(regs []Reg, r Reg) []Reg {
+	for _, s := range regs {
+		if s == r {
+			return regs
+		}
+	}
+	return append(regs, r)
+}
+
+// modeRegs adjusts implicit registers, which meta.csv names by their 64-bit
+// forms where their width follows the mode, to their forms in a mode. With
+// an address size prefix, the address and count registers of string
+// instructions narrow further.
+func modeRegs(regs []Reg, mode int, addrsize bool) []Reg {
+	out := regs[:0]
+	for _, r := range regs {
+		if g, ok := r.(GPR64); ok && mode == 32 {
+			r = GPR32(g)
+		}
+		if mode == 32 && needs64(r) {
+			continue
+		}
+		if addrsize {
+			switch r {
+			case RCX, RSI, RDI:
+				r = GPR32(r.(GPR64))
+			case ECX, ESI, EDI:
+				if mode == 32 {
+					r = GPR16(r.(GPR32))
+				}
+			}
+		}
+		out = appendReg(out, r)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package x86enc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	const arith = CF | PF | AF | ZF | SF | OF
+	mode32 := &Encoder{Features: AllFeatures, Mode: 32}
+	cases := []struct {
+		e    *Encoder
+		op   string
+		args []Operand
+		want Info
+	}{
+		{anyCPU, "DIV", []Operand{RCX}, Info{Access: []Access{Read}, Reads: []Reg{RAX, RDX}, Writes: []Reg{RAX, RDX}, FlagsWritten: arith, Latency: LatencyDivide}},
+		{anyCPU, "DIV", []Operand{CL}, Info{Access: []Access{Read}, Reads: []Reg{AX}, Writes: []Reg{AX}, FlagsWritten: arith, Latency: LatencyDivide}},
+		{anyCPU, "MUL", []Operand{Mem{Base: RSI, Size: 4}}, Info{Access: []Access{Read}, Reads: []Reg{EAX}, Writes: []Reg{EAX, EDX}, FlagsWritten: arith, Memory: Read, Latency: LatencyMedium}},
+		{anyCPU, "CMP", []Operand{RAX, Imm(1)}, Info{Access: []Access{Read, Read}, FlagsWritten: arith}},
+		{anyCPU, "CMOVE", []Operand{EAX, ECX}, Info{Access: []Access{ReadWrite, Read}, FlagsRead: ZF}},
+		{anyCPU, "ADC", []Operand{Mem{Base: RAX, Size: 4}, ECX}, Info{Access: []Access{ReadWrite, Read}, FlagsRead: CF, FlagsWritten: arith, Memory: ReadWrite}},
+		{anyCPU, "MOV", []Operand{EAX, Mem{Base: RAX}}, Info{Access: []Access{Write, Read}, Memory: Read}},
+		{anyCPU, "LEA", []Operand{RAX, Mem{Base: RAX, Disp: 8}}, Info{Access: []Access{Write, 0}}},
+		{anyCPU, "PUSH", []Operand{RBX}, Info{Access: []Access{Read}, Reads: []Reg{RSP}, Writes: []Reg{RSP}, Memory: Write}},
+		{anyCPU, "JNE", []Operand{Rel(0)}, Info{Access: []Access{Read}, FlagsRead: ZF, Latency: LatencyBranch}},
+		{anyCPU, "CPUID", nil, Info{Reads: []Reg{EAX, ECX}, Writes: []Reg{EAX, EBX, ECX, EDX}, Latency: LatencyMicrocode}},
+		{anyCPU, "MOVSB", []Operand{REP}, Info{Reads: []Reg{RCX, RSI, RDI}, Writes: []Reg{RCX, RSI, RDI}, FlagsRead: DF, Memory: ReadWrite, Latency: LatencyMicrocode}},
+		{anyCPU, "SCASB", []Operand{REPN, ADDRSIZE}, Info{Reads: []Reg{ECX, EDI, AL}, Writes: []Reg{ECX, EDI}, FlagsRead: ZF | DF, FlagsWritten: arith, Memory: Read, Latency: LatencyMicrocode}},
+		{anyCPU, "ADDPS", []Operand{X0, X1}, Info{Access: []Access{ReadWrite, Read}, Latency: LatencyMedium}},
+		{anyCPU, "BLENDVPS", []Operand{X1, X2}, Info{Access: []Access{ReadWrite, Read}, Reads: []Reg{X0}}},
+		{anyCPU, "VADDPS", []Operand{Y0, Y1, Y2}, Info{Access: []Access{Write, Read, Read}, Latency: LatencyMedium}},
+		{anyCPU, "VADDPS", []Operand{Masked{Op: Z0, Mask: K1}, Z1, Z2, RoundZero}, Info{Access: []Access{ReadWrite, Read, Read}, Reads: []Reg{K1}, Latency: LatencyMedium}},
+		{anyCPU, "VADDPS", []Operand{Masked{Op: Z0, Mask: K1, Zero: true}, Z1, Z2}, Info{Access: []Access{Write, Read, Read}, Reads: []Reg{K1}, Latency: LatencyMedium}},
+		{anyCPU, "VMOVUPS", []Operand{Masked{Op: Mem{Base: RDI}, Mask: K2}, Z1}, Info{Access: []Access{Write, Read}, Reads: []Reg{K2}, Memory: Write}},
+		{anyCPU, "VGATHERDPS", []Operand{Masked{Op: Z0, Mask: K1}, Mem{Base: RAX, Index: Z1, Scale: 4}}, Info{Access: []Access{ReadWrite, Read}, Reads: []Reg{K1}, Writes: []Reg{K1}, Memory: Read, Latency: LatencyGather}},
+		{anyCPU, "VGATHERDPS", []Operand{X0, Mem{Base: RAX, Index: X1, Scale: 4}, X2}, Info{Access: []Access{ReadWrite, Read, ReadWrite}, Memory: Read, Latency: LatencyGather}},
+		{anyCPU, "KORTESTW", []Operand{K1, K2}, Info{Access: []Access{Read, Read}, FlagsWritten: arith}},
+		{mode32, "PUSH", []Operand{EBX}, Info{Access: []Access{Read}, Reads: []Reg{ESP}, Writes: []Reg{ESP}, Memory: Write}},
+		{mode32, "MOVSD", []Operand{REP, ADDRSIZE}, Info{Reads: []Reg{CX, SI, DI}, Writes: []Reg{CX, SI, DI}, FlagsRead: DF, Memory: ReadWrite, Latency: LatencyMicrocode}},
+		{mode32, "VZEROUPPER", nil, Info{Writes: []Reg{Y0, Y1, Y2, Y3, Y4, Y5, Y6, Y7}}},
+	}
+	for _, c := range cases {
+		got, err := c.e.Describe(c.op, c.args...)
+		if err != nil {
+			t.Errorf("%s %v: %v", c.op, c.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s %v:\ngot  %+v\nwant %+v", c.op, c.args, got, c.want)
+		}
+	}
+	if _, err := Describe("ADD", LOCK, RAX, RCX); err == nil || !strings.Contains(err.Error(), "LOCK") {
+		t.Errorf("LOCK ADD with registers: wrong error %v", err)
+	}
+}
+
+func TestDescribeForm(t *testing.T) {
+	got, err := DescribeForm("IMUL r64, r/m64, imm32", RAX, Mem{Base: RBX}, Imm(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Info{Access: []Access{Write, Read, Read}, FlagsWritten: CF | PF | AF | ZF | SF | OF, Memory: Read, Latency: LatencyMedium}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+	if _, err := DescribeForm("IMUL r64, r/m64, imm32", EAX, RBX, Imm(3)); err == nil {
+		t.Error("described a form which does not match its operands")
+	}
+}
+
+// TestMetaTable checks that every row has metadata for each of its arguments.
+func TestMetaTable(t *testing.T) {
+	if len(rowMeta) != len(table) {
+		t.Fatalf("%d rows of metadata for %d rows", len(rowMeta), len(table))
+	}
+	for i := range table {
+		r := &table[i]
+		m := &metaTable[rowMeta[i]]
+		for j, a := range r.args[:r.nargs()] {
+			k := &kindTable[a]
+			if m.access[j] == 0 && k.mem == memNone && k.fixed == nil {
+				t.Errorf("%v: argument %d has no access", r, j+1)
+			}
+		}
+	}
+}
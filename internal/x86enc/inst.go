@@ -0,0 +1,381 @@
+package x86enc
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// EncodeInst encodes an instruction decoded by x86asm for 64-bit mode, so
+// that decoded code can be modified and emitted again. Like Encode, it uses
+// the shortest matching form, preferring forms of the operand size given by
+// inst.DataSize. Legacy prefixes which the operands do not imply, like LOCK,
+// REP, and segment overrides, are kept.
+//
+// Branch targets and RIP-relative addresses are relative to the end of the
+// instruction. If inst.Len is not 0 and the encoding is a different length,
+// EncodeInst adjusts them so that they refer to the same addresses as in the
+// decoded instruction.
+func EncodeInst(inst x86asm.Inst) ([]byte, error) {
+	return hostCPU.EncodeInst(inst)
+}
+
+// EncodeInst encodes an instruction like the package's EncodeInst function,
+// using only forms which the target supports. The instruction must have been
+// decoded for the target's mode.
+func (e *Encoder) EncodeInst(inst x86asm.Inst) ([]byte, error) {
+	b, _, err := e.encodeInst(&inst, nil)
+	return b, err
+}
+
+// RequiresCode returns the features required by a sequence of instructions in
+// the target's mode, decoded by x86asm. It fails on instructions x86asm cannot
+// decode, which include BMI instructions.
+func (e *Encoder) RequiresCode(code []byte) (Feature, error) {
+	mode, err := e.mode()
+	if err != nil {
+		return 0, err
+	}
+	var f Feature
+	for pc := 0; pc < len(code); {
+		inst, err := decode(code[pc:], mode)
+		if err != nil {
+			return 0, fmt.Errorf("x86enc: cannot decode instruction at %#x: %v", pc, err)
+		}
+		// Look for the row among those of the same encoding, so that an
+		// EVEX-encoded instruction counts as AVX-512 even if it has a
+		// shorter VEX encoding.
+		class := encodingClass(&inst)
+		same := func(r *instruction) bool { return r.enc.flags&(encVEX|encEVEX) == class }
+		_, r, err := e.encodeInst(&inst, same)
+		if err != nil {
+			return 0, fmt.Errorf("x86enc: instruction at %#x: %v", pc, err)
+		}
+		f |= e.requires(r)
+		pc += inst.Len
+	}
+	return f, nil
+}
+
+// RequiresCode returns the features required by a sequence of instructions
+// like the Encoder method for a processor with every feature, so that it
+// reports features which the processor running the program may lack.
+func RequiresCode(code []byte) (Feature, error) {
+	return anyCPU.RequiresCode(code)
+}
+
+// decode decodes an instruction in the given mode. x86asm panics on some
+// truncated EVEX-encoded instructions, which decode reports as errors.
+func decode(code []byte, mode int) (inst x86asm.Inst, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return x86asm.Decode(code, mode)
+}
+
+// encodingClass returns encVEX or encEVEX for instructions with those
+// prefixes, or 0 for others.
+func encodingClass(inst *x86asm.Inst) encFlag {
+	for _, p := range inst.Prefix {
+		switch {
+		case p == 0:
+			return 0
+		case p.IsVEX():
+			return encVEX
+		case p.IsEVEX():
+			return encEVEX
+		}
+	}
+	return 0
+}
+
+// encodeInst encodes an instruction decoded by x86asm and returns the row it
+// used. If only is not nil, forms for which it returns true are preferred.
+func (e *Encoder) encodeInst(inst *x86asm.Inst, only func(*instruction) bool) ([]byte, *instruction, error) {
+	mode, err := e.mode()
+	if err != nil {
+		return nil, nil, err
+	}
+	if inst.Mode != 0 && inst.Mode != mode {
+		return nil, nil, fmt.Errorf("x86enc: cannot encode %d-bit instruction %v in %d-bit mode", inst.Mode, inst.Op, mode)
+	}
+	op := inst.Op.String()
+	rows := lookup(op)
+	if rows == nil {
+		return nil, nil, fmt.Errorf("x86enc: unknown instruction %s", op)
+	}
+	pre, err := instPrefixes(inst)
+	if err != nil {
+		return nil, nil, err
+	}
+	cands, err := instArgs(inst)
+	if err != nil {
+		return nil, nil, err
+	}
+	prefer := func(r *instruction) bool {
+		if only != nil && !only(r) {
+			return false
+		}
+		t := r.flags & (tagOperand16 | tagOperand32 | tagOperand64)
+		return t == 0 || t&sizeTag(inst.DataSize) != 0
+	}
+	// Keep relative operands relative to the end of the decoded
+	// instruction, without the prefixes which are not part of the encoding.
+	end := 0
+	if inst.Len != 0 {
+		end = inst.Len - len(pre)
+	}
+	enc := func(args []Operand) ([]byte, *instruction, error) {
+		b, r, err := e.shortest(rows, args, prefer, end)
+		if err != nil {
+			return nil, nil, err
+		}
+		return append(pre[:len(pre):len(pre)], b...), r, nil
+	}
+	for _, args := range cands {
+		var b []byte
+		var r *instruction
+		if b, r, err = enc(args); err == nil {
+			return b, r, nil
+		}
+	}
+	if stringOps[op] {
+		// x86asm gives the memory operands of string instructions, which
+		// x86.csv writes without operands, leaving only the address size.
+		if inst.AddrSize != 0 && inst.AddrSize != mode {
+			pre = append(pre, 0x67)
+		}
+		return enc(nil)
+	}
+	return nil, nil, err
+}
+
+var stringOps = map[string]bool{
+	"CMPSB": true, "CMPSW": true, "CMPSD": true, "CMPSQ": true,
+	"INSB": true, "INSW": true, "INSD": true,
+	"LODSB": true, "LODSW": true, "LODSD": true, "LODSQ": true,
+	"MOVSB": true, "MOVSW": true, "MOVSD": true, "MOVSQ": true,
+	"OUTSB": true, "OUTSW": true, "OUTSD": true,
+	"SCASB": true, "SCASW": true, "SCASD": true, "SCASQ": true,
+	"STOSB": true, "STOSW": true, "STOSD": true, "STOSQ": true,
+	"XLATB": true,
+}
+
+// instPrefixes returns the legacy prefixes of an instruction which its
+// encoding does not otherwise imply.
+func instPrefixes(inst *x86asm.Inst) ([]byte, error) {
+	var pre []byte
+	for _, p := range inst.Prefix {
+		if p == 0 || p.IsVEX() || p.IsEVEX() {
+			// The rest of a VEX or EVEX prefix follows it.
+			break
+		}
+		if p&x86asm.PrefixInvalid != 0 {
+			return nil, fmt.Errorf("x86enc: invalid prefix %v", p)
+		}
+		if p&x86asm.PrefixIgnored != 0 || p.IsREX() {
+			continue
+		}
+		switch b := byte(p); b {
+		case 0x26, 0x2e, 0x36, 0x3e, 0x64, 0x65:
+			// x86asm marks segment overrides as implicit when it shows them
+			// on memory operands, but they still need to be encoded.
+			pre = append(pre, b)
+		default:
+			if p&x86asm.PrefixImplicit == 0 {
+				pre = append(pre, b)
+			}
+		}
+	}
+	return pre, nil
+}
+
+// instArgs converts the arguments of an instruction to operands. It returns
+// the interpretations of the arguments to try in order.
+func instArgs(inst *x86asm.Inst) ([][]Operand, error) {
+	var args []Operand
+	for _, a := range inst.Args {
+		if a == nil {
+			break
+		}
+		o, err := instArg(inst, a)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, o)
+	}
+	if inst.SAE {
+		args = append(args, RoundNearest+Rounding(inst.Rounding&3))
+	}
+	if inst.Op == x86asm.LCALL || inst.Op == x86asm.LJMP {
+		// x86asm gives the segment selector and offset of a far pointer as
+		// separate immediates.
+		seg, ok := argAt(args, 0).(Imm)
+		if off, ok2 := argAt(args, 1).(Imm); ok && ok2 && len(args) == 2 {
+			args = []Operand{FarPtr{Seg: uint16(seg), Off: uint32(off)}}
+		}
+	}
+	cands := [][]Operand{args}
+	evex := false
+	for _, p := range inst.Prefix {
+		evex = evex || p.IsEVEX()
+	}
+	// x86asm lists the opmask of an EVEX instruction as its second
+	// argument. A second opmask argument might instead be a real operand.
+	if k, ok := argAt(args, 1).(K); ok && evex && k != K0 && !strings.HasPrefix(inst.Op.String(), "K") {
+		m := append([]Operand{Masked{Op: args[0], Mask: k, Zero: inst.Zeroing}}, args[2:]...)
+		cands = append([][]Operand{m}, cands...)
+	}
+	if inst.SAE {
+		// The rounding control is only a rounding mode if the instruction
+		// allows one. Otherwise, it suppresses exceptions.
+		for _, c := range cands {
+			sae := append([]Operand(nil), c...)
+			sae[len(sae)-1] = SAE
+			cands = append(cands, sae)
+		}
+	}
+	return cands, nil
+}
+
+func argAt(args []Operand, i int) Operand {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
+
+// instArg converts an argument of an instruction to an operand.
+func instArg(inst *x86asm.Inst, a x86asm.Arg) (Operand, error) {
+	switch a := a.(type) {
+	case x86asm.Reg:
+		return instReg(a)
+	case x86asm.Mem:
+		m := Mem{Size: inst.MemBytes, Broadcast: inst.Broadcast}
+		// x86asm zero-extends 32-bit displacements.
+		if a.Disp != int64(int32(a.Disp)) && a.Disp != int64(uint32(a.Disp)) {
+			return nil, fmt.Errorf("x86enc: displacement %#x out of range", a.Disp)
+		}
+		m.Disp = int32(a.Disp)
+		if a.Base != 0 {
+			r, err := instReg(a.Base)
+			if err != nil {
+				return nil, err
+			}
+			m.Base = r
+		}
+		if a.Index != 0 {
+			r, err := instReg(a.Index)
+			if err != nil {
+				return nil, err
+			}
+			m.Index, m.Scale = r, a.Scale
+		}
+		return m, nil
+	case x86asm.Imm:
+		return Imm(a), nil
+	case x86asm.Rel:
+		return Rel(a), nil
+	}
+	return nil, fmt.Errorf("x86enc: cannot encode argument %v", a)
+}
+
+// instReg converts an x86asm register.
+func instReg(r x86asm.Reg) (Reg, error) {
+	switch {
+	case x86asm.AL <= r && r <= x86asm.BL:
+		return AL + GPR8(r-x86asm.AL), nil
+	case x86asm.AH <= r && r <= x86asm.BH:
+		return AH + GPR8(r-x86asm.AH), nil
+	case x86asm.SPB <= r && r <= x86asm.R15B:
+		return SPL + GPR8(r-x86asm.SPB), nil
+	case x86asm.AX <= r && r <= x86asm.R15W:
+		return AX + GPR16(r-x86asm.AX), nil
+	case x86asm.EAX <= r && r <= x86asm.R15L:
+		return EAX + GPR32(r-x86asm.EAX), nil
+	case x86asm.RAX <= r && r <= x86asm.R15:
+		return RAX + GPR64(r-x86asm.RAX), nil
+	case r == x86asm.RIP:
+		return RIP, nil
+	case r == x86asm.EIP:
+		return EIP, nil
+	case x86asm.F0 <= r && r <= x86asm.F7:
+		return ST0 + ST(r-x86asm.F0), nil
+	case x86asm.M0 <= r && r <= x86asm.M7:
+		return M0 + MMX(r-x86asm.M0), nil
+	case x86asm.X0 <= r && r <= x86asm.X31:
+		return X0 + XMM(r-x86asm.X0), nil
+	case x86asm.Y0 <= r && r <= x86asm.Y31:
+		return Y0 + YMM(r-x86asm.Y0), nil
+	case x86asm.Z0 <= r && r <= x86asm.Z31:
+		return Z0 + ZMM(r-x86asm.Z0), nil
+	case x86asm.K0 <= r && r <= x86asm.K7:
+		return K0 + K(r-x86asm.K0), nil
+	case x86asm.ES <= r && r <= x86asm.GS:
+		return ES + Seg(r-x86asm.ES), nil
+	case x86asm.CR0 <= r && r <= x86asm.CR15:
+		return CR(r - x86asm.CR0), nil
+	case x86asm.DR0 <= r && r <= x86asm.DR15:
+		return DR(r - x86asm.DR0), nil
+	case x86asm.TR0 <= r && r <= x86asm.TR7:
+		return TR(r - x86asm.TR0), nil
+	}
+	return nil, fmt.Errorf("x86enc: cannot encode register %v", r)
+}
+
+// sizeTag returns the row tag for an operand size in bits.
+func sizeTag(bits int) rowFlag {
+	switch bits {
+	case 16:
+		return tagOperand16
+	case 32:
+		return tagOperand32
+	case 64:
+		return tagOperand64
+	}
+	return tagOperand16 | tagOperand32 | tagOperand64
+}
+
+// pcrel returns whether any operand is relative to the end of the
+// instruction.
+func pcrel(args []Operand) bool {
+	for _, a := range args {
+		if m, ok := a.(Masked); ok {
+			a = m.Op
+		}
+		switch a := a.(type) {
+		case Rel:
+			return true
+		case Mem:
+			if _, ok := a.Base.(IP); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shiftRel returns the operands with relative ones moved by d bytes.
+func shiftRel(args []Operand, d int) []Operand {
+	r := make([]Operand, len(args))
+	for i, a := range args {
+		switch a := a.(type) {
+		case Rel:
+			r[i] = a + Rel(d)
+		case Mem:
+			if _, ok := a.Base.(IP); ok {
+				a.Disp += int32(d)
+			}
+			r[i] = a
+		case Masked:
+			a.Op = shiftRel([]Operand{a.Op}, d)[0]
+			r[i] = a
+		default:
+			r[i] = a
+		}
+	}
+	return r
+}
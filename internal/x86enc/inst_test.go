@@ -0,0 +1,170 @@
+package x86enc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// TestEncodeInst checks that instructions decoded by x86asm encode to
+// instructions which decode the same way.
+func TestEncodeInst(t *testing.T) {
+	cases := []string{
+		"4801c8",               // add rax, rcx
+		"6601d8",               // add ax, bx
+		"4883c001",             // add rax, 0x1
+		"480581000000",         // add rax, 0x81
+		"8b0c24",               // mov ecx, dword ptr [rsp]
+		"4c8b4c2408",           // mov r9, qword ptr [rsp+0x8]
+		"488b84cbbbdcfeff",     // mov rax, qword ptr [rbx+rcx*8-0x12345]
+		"488b0500000000",       // mov rax, qword ptr [rip]
+		"48b80000000000010000", // mov rax, 0x10000000000
+		"0fb6c0",               // movzx eax, al
+		"400fb6c6",             // movzx eax, sil
+		"0fb6c4",               // movzx eax, ah
+		"678b00",               // mov eax, dword ptr [eax]
+		"64488b042500000000",   // mov rax, qword ptr fs:[0]
+		"f0480103",             // lock add qword ptr [rbx], rax
+		"f0480fb10b",           // lock cmpxchg qword ptr [rbx], rcx
+		"f348a5",               // rep movsq
+		"67a4",                 // movsb with 32-bit addresses
+		"f2ae",                 // repne scasb
+		"666a01",               // push 0x1 with a 16-bit operand size
+		"660fa1",               // pop fs with a 16-bit operand size
+		"e800000000",           // call .+0
+		"eb10",                 // jmp .+0x10
+		"e900010000",           // jmp .+0x100
+		"c3",                   // ret
+		"90",                   // nop
+		"0f1f4000",             // nop dword ptr [rax]
+		"f390",                 // pause
+		"d9c1",                 // fld st1
+		"dd4008",               // fld qword ptr [rax+0x8]
+		"660f6fc1",             // movdqa xmm0, xmm1
+		"f20f5803",             // addsd xmm0, qword ptr [rbx]
+		"f30fb8c1",             // popcnt eax, ecx
+		"0f20c0",               // mov rax, cr0
+		"c5e958cb",             // vaddpd xmm1, xmm2, xmm3
+		"c4c16958cb",           // vaddpd xmm1, xmm2, xmm11
+		"c4e2fd920cd0",         // vgatherdpd ymm1, [rax+8*xmm2], ymm0
+		"c4e35d02e2f0",         // vpblendd ymm4, ymm4, ymm2, 0xf0
+		"c5ed74cb",             // vpcmpeqb ymm1, ymm2, ymm3
+		"62f1ed4858cb",         // vaddpd zmm1, zmm2, zmm3
+		"62f1edcd58cb",         // vaddpd zmm1 {k5} {z}, zmm2, zmm3
+		"62f1ed58584801",       // vaddpd zmm1, zmm2, qword ptr [rax+0x8]{1to8}
+		"62f1ed3858cb",         // vaddpd zmm1, zmm2, zmm3, {rd-sae}
+		"62f1ed18c2d301",       // vcmppd k2, zmm2, zmm3, {sae}, 0x1
+		"62f2fd49920cd0",       // vgatherdpd zmm1 {k1}, qword ptr [rax+8*ymm2]
+		"62e1fe4b7f7ffe",       // vmovdqu64 zmmword ptr [rdi-0x80] {k3}, zmm23
+	}
+	for _, c := range cases {
+		src, err := hex.DecodeString(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inst, err := x86asm.Decode(src, 64)
+		if err != nil {
+			t.Errorf("%s does not decode: %v", c, err)
+			continue
+		}
+		want := x86asm.IntelSyntax(inst, 0, nil)
+		t.Run(want, func(t *testing.T) {
+			b, err := EncodeInst(inst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDecode(t, b, 64, want)
+		})
+	}
+}
+
+// TestEncodeInst32 checks re-encoding instructions decoded in 32-bit mode.
+func TestEncodeInst32(t *testing.T) {
+	e := &Encoder{Features: AllFeatures, Mode: 32}
+	cases := []string{
+		"01c8",           // add eax, ecx
+		"6601d8",         // add ax, bx
+		"8b4c2404",       // mov ecx, dword ptr [esp+0x4]
+		"a100100000",     // mov eax, dword ptr [0x1000]
+		"41",             // inc ecx
+		"53",             // push ebx
+		"f3a5",           // rep movsd
+		"67a4",           // movsb with 16-bit addresses
+		"e3fe",           // jecxz .+0
+		"ea001000000800", // jmp far 0x1000, 0x8
+		"c5e958cb",       // vaddpd xmm1, xmm2, xmm3
+		"62f1ed4858cb",   // vaddpd zmm1, zmm2, zmm3
+	}
+	for _, c := range cases {
+		src, err := hex.DecodeString(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inst, err := x86asm.Decode(src, 32)
+		if err != nil {
+			t.Errorf("%s does not decode: %v", c, err)
+			continue
+		}
+		want := x86asm.IntelSyntax(inst, 0, nil)
+		t.Run(want, func(t *testing.T) {
+			b, err := e.EncodeInst(inst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDecode(t, b, 32, want)
+		})
+	}
+	inst, err := x86asm.Decode([]byte{0x01, 0xc8}, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := EncodeInst(inst); err == nil {
+		t.Errorf("32-bit instruction encoded in 64-bit mode as %x", b)
+	}
+}
+
+// TestEncodeInstRel checks that relative operands keep their targets when
+// the length of an instruction changes.
+func TestEncodeInstRel(t *testing.T) {
+	cases := []struct {
+		src, want []byte
+	}{
+		{[]byte{0xe9, 0x10, 0x00, 0x00, 0x00}, []byte{0xeb, 0x13}},
+		{[]byte{0xe9, 0x7e, 0x00, 0x00, 0x00}, []byte{0xe9, 0x7e, 0x00, 0x00, 0x00}},
+		{[]byte{0x0f, 0x84, 0x00, 0x00, 0x00, 0x00}, []byte{0x74, 0x04}},
+		{[]byte{0x48, 0x8b, 0x05, 0x00, 0x00, 0x00, 0x00}, []byte{0x48, 0x8b, 0x05, 0x00, 0x00, 0x00, 0x00}},
+		{[]byte{0x40, 0x8b, 0x05, 0x00, 0x00, 0x00, 0x00}, []byte{0x8b, 0x05, 0x01, 0x00, 0x00, 0x00}},
+	}
+	for _, c := range cases {
+		inst, err := x86asm.Decode(c.src, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := EncodeInst(inst)
+		if err != nil {
+			t.Errorf("%x: %v", c.src, err)
+			continue
+		}
+		if !bytes.Equal(b, c.want) {
+			t.Errorf("%x: got %x, want %x", c.src, b, c.want)
+		}
+	}
+}
+
+// TestEncodeInstModified checks re-encoding a decoded instruction after
+// changing it.
+func TestEncodeInstModified(t *testing.T) {
+	inst, err := x86asm.Decode([]byte{0x48, 0x01, 0xc8}, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst.Op = x86asm.SUB
+	inst.Args[1] = x86asm.R12
+	b, err := EncodeInst(inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDecode(t, b, 64, "sub rax, r12")
+}
@@ -0,0 +1,212 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Encoding is the interpretation of the encoding column of x86.csv, such as
+// "REX.W + 81 /2 id".
+type Encoding struct {
+	// Prefix holds mandatory prefixes which precede REX, like 66 and F3.
+	Prefix []byte
+	// REX is set if the form requires a REX prefix even if it is otherwise
+	// empty; REXW is set if it requires REX.W.
+	REX, REXW bool
+	// VEX describes the VEX or EVEX prefix of VEX- and EVEX-encoded forms.
+	VEX *VEX
+	// Opcode holds the opcode bytes.
+	Opcode []byte
+	// Plus is set if a register number is added to the last opcode byte.
+	Plus bool
+	// ModRM is the ModRM reg field for /digit forms, ModRMReg for /r forms, or
+	// ModRMNone if the form has no ModRM byte.
+	ModRM int
+	// Imm holds the sizes of immediates and code offsets in order.
+	Imm []int
+	// Is4 is set if a register is encoded in the high bits of an immediate.
+	Is4 bool
+}
+
+const (
+	ModRMNone = -1
+	ModRMReg  = 8
+)
+
+// VEX is the interpretation of a VEX prefix description like
+// "VEX.NDS.128.66.0F38.W0" or an EVEX prefix description like
+// "EVEX.NDS.512.66.0F.W1".
+type VEX struct {
+	// EVEX is set for EVEX prefixes.
+	EVEX bool
+	// L is VEX.L, or EVEX.L'L: 0 for 128-bit and scalar operations, 1 for
+	// 256-bit, and 2 for 512-bit. LIG is set if the processor ignores it.
+	L   byte
+	LIG bool
+	// PP is VEX.pp, the implied mandatory prefix: 0 for none, 1 for 66, 2 for
+	// F3, and 3 for F2.
+	PP byte
+	// Map is VEX.mmmmm, the implied leading opcode bytes: 1 for 0F, 2 for
+	// 0F38, and 3 for 0F3A.
+	Map byte
+	// W is VEX.W. WIG is set if the processor ignores it.
+	W   byte
+	WIG bool
+	// V is set if VEX.vvvv encodes an operand, as in NDS, NDD, and DDS forms.
+	// Otherwise, vvvv must be 1111b.
+	V bool
+}
+
+// ParseEncoding interprets an encoding column.
+func ParseEncoding(s string) (Encoding, error) {
+	e := Encoding{ModRM: ModRMNone}
+	var bytes []byte
+	for _, t := range strings.Fields(s) {
+		switch {
+		case t == "+":
+			// Separates REX from the rest of the encoding.
+		case t == "REX":
+			e.REX = true
+		case t == "REX.W":
+			e.REXW = true
+		case strings.HasPrefix(t, "VEX."), strings.HasPrefix(t, "EVEX."):
+			if e.VEX != nil {
+				return Encoding{}, fmt.Errorf("multiple VEX prefixes in encoding %q", s)
+			}
+			v, err := parseVEX(t)
+			if err != nil {
+				return Encoding{}, fmt.Errorf("%v in encoding %q", err, s)
+			}
+			e.VEX = &v
+		case t == "/r":
+			e.ModRM = ModRMReg
+		case len(t) == 2 && t[0] == '/' && '0' <= t[1] && t[1] <= '7':
+			e.ModRM = int(t[1] - '0')
+		case t == "/is4":
+			e.Is4 = true
+		case t == "ib" || t == "cb":
+			e.Imm = append(e.Imm, 1)
+		case t == "iw" || t == "cw":
+			e.Imm = append(e.Imm, 2)
+		case t == "id" || t == "cd":
+			e.Imm = append(e.Imm, 4)
+		case t == "io" || t == "cm":
+			// Memory offsets are always 64 bits in 64-bit mode.
+			e.Imm = append(e.Imm, 8)
+		case t == "cp":
+			e.Imm = append(e.Imm, 6)
+		default:
+			if i := strings.IndexByte(t, '+'); i == 2 {
+				// Opcode with a register added, like 58+rd or C0+i.
+				switch t[3:] {
+				case "rb", "rw", "rd", "i":
+				default:
+					return Encoding{}, fmt.Errorf("bad token %q in encoding %q", t, s)
+				}
+				e.Plus = true
+				t = t[:2]
+			}
+			b, err := strconv.ParseUint(t, 16, 8)
+			if err != nil || len(t) != 2 {
+				return Encoding{}, fmt.Errorf("bad token %q in encoding %q", t, s)
+			}
+			bytes = append(bytes, byte(b))
+		}
+	}
+	// Leading 66, F2, and F3 are mandatory prefixes rather than opcodes, as is
+	// 9B before x87 instructions, but only if more opcode bytes follow. VEX
+	// encodes mandatory prefixes itself.
+	for len(bytes) > 1 && e.VEX == nil {
+		switch bytes[0] {
+		case 0x66, 0xf2, 0xf3, 0x9b:
+			e.Prefix = append(e.Prefix, bytes[0])
+			bytes = bytes[1:]
+			continue
+		}
+		break
+	}
+	if len(bytes) == 0 {
+		return Encoding{}, fmt.Errorf("no opcode in encoding %q", s)
+	}
+	if e.VEX != nil && (len(bytes) != 1 || e.REX || e.REXW) {
+		return Encoding{}, fmt.Errorf("bad VEX opcode in encoding %q", s)
+	}
+	e.Opcode = bytes
+	return e, nil
+}
+
+// parseVEX interprets a VEX prefix description.
+func parseVEX(s string) (VEX, error) {
+	var v VEX
+	var l, m, w bool
+	f := strings.Split(s, ".")
+	v.EVEX = f[0] == "EVEX"
+	for _, t := range f[1:] {
+		switch t {
+		case "NDS", "NDD", "DDS":
+			v.V = true
+		case "128", "LZ", "L0":
+			v.L, l = 0, true
+		case "256", "L1":
+			v.L, l = 1, true
+		case "512":
+			v.L, l = 2, v.EVEX
+		case "LIG":
+			v.LIG, l = true, true
+		case "66":
+			v.PP = 1
+		case "F3":
+			v.PP = 2
+		case "F2":
+			v.PP = 3
+		case "0F":
+			v.Map, m = 1, true
+		case "0F38":
+			v.Map, m = 2, true
+		case "0F3A":
+			v.Map, m = 3, true
+		case "W0":
+			v.W, w = 0, true
+		case "W1":
+			v.W, w = 1, true
+		case "WIG":
+			v.WIG, w = true, true
+		default:
+			return VEX{}, fmt.Errorf("bad VEX field %q", t)
+		}
+	}
+	if !l || !m || !w {
+		return VEX{}, fmt.Errorf("incomplete VEX prefix %q", s)
+	}
+	return v, nil
+}
+
+// Disp8 returns the base 2 logarithm of the scale EVEX applies to 8-bit
+// displacements for the memory operand of a form when it is not broadcast.
+// The scale is the size of the memory operand, or for gathers and scatters,
+// the size of each element, which EVEX.W gives. It returns 0 for forms which
+// are not EVEX-encoded or have no memory operand.
+func Disp8(kinds []Kind, e *Encoding) (int, error) {
+	if e.VEX == nil || !e.VEX.EVEX {
+		return 0, nil
+	}
+	for i := range kinds {
+		k := &kinds[i]
+		switch k.Mem {
+		case MemNone:
+			continue
+		case MemVSIBX, MemVSIBY, MemVSIBZ:
+			return 2 + int(e.VEX.W), nil
+		}
+		n := 0
+		for 1<<uint(n) < k.MemSize {
+			n++
+		}
+		if k.MemSize == 0 || 1<<uint(n) != k.MemSize {
+			return 0, fmt.Errorf("no displacement scale for %s", k.Name)
+		}
+		return n, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,115 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEncoding(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Encoding
+	}{
+		{"37", Encoding{Opcode: []byte{0x37}, ModRM: ModRMNone}},
+		{"REX.W + 81 /2 id", Encoding{REXW: true, Opcode: []byte{0x81}, ModRM: 2, Imm: []int{4}}},
+		{"REX + 88 /r", Encoding{REX: true, Opcode: []byte{0x88}, ModRM: ModRMReg}},
+		{"66 0F 3A 16 /r ib", Encoding{Prefix: []byte{0x66}, Opcode: []byte{0x0f, 0x3a, 0x16}, ModRM: ModRMReg, Imm: []int{1}}},
+		{"REX.W + F3 0F BD /r", Encoding{Prefix: []byte{0xf3}, REXW: true, Opcode: []byte{0x0f, 0xbd}, ModRM: ModRMReg}},
+		{"REX.W + B8+rd io", Encoding{REXW: true, Opcode: []byte{0xb8}, Plus: true, ModRM: ModRMNone, Imm: []int{8}}},
+		{"9B DF E0", Encoding{Prefix: []byte{0x9b}, Opcode: []byte{0xdf, 0xe0}, ModRM: ModRMNone}},
+		{"F3", Encoding{Opcode: []byte{0xf3}, ModRM: ModRMNone}},
+		{"EA cp", Encoding{Opcode: []byte{0xea}, ModRM: ModRMNone, Imm: []int{6}}},
+		{
+			"VEX.NDS.LZ.0F38.W1 F2 /r",
+			Encoding{VEX: &VEX{Map: 2, W: 1, V: true}, Opcode: []byte{0xf2}, ModRM: ModRMReg},
+		},
+		{
+			"VEX.DDS.LIG.128.66.0F38.W0 99 /r",
+			Encoding{VEX: &VEX{LIG: true, PP: 1, Map: 2, V: true}, Opcode: []byte{0x99}, ModRM: ModRMReg},
+		},
+		{
+			"VEX.NDS.256.66.0F3A.W0 4B /r /is4",
+			Encoding{VEX: &VEX{L: 1, PP: 1, Map: 3, V: true}, Opcode: []byte{0x4b}, ModRM: ModRMReg, Is4: true},
+		},
+		{
+			"VEX.128.F2.0F.WIG 12 /r",
+			Encoding{VEX: &VEX{PP: 3, Map: 1, WIG: true}, Opcode: []byte{0x12}, ModRM: ModRMReg},
+		},
+		{
+			"EVEX.NDS.512.66.0F.W1 58 /r",
+			Encoding{VEX: &VEX{EVEX: true, L: 2, PP: 1, Map: 1, W: 1, V: true}, Opcode: []byte{0x58}, ModRM: ModRMReg},
+		},
+		{
+			"EVEX.LIG.F3.0F3A.W0 0A /r ib",
+			Encoding{VEX: &VEX{EVEX: true, LIG: true, PP: 2, Map: 3}, Opcode: []byte{0x0a}, ModRM: ModRMReg, Imm: []int{1}},
+		},
+	}
+	for _, c := range cases {
+		e, err := ParseEncoding(c.s)
+		if err != nil {
+			t.Errorf("%q: %v", c.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(e, c.want) {
+			t.Errorf("%q: got %+v, want %+v", c.s, e, c.want)
+		}
+	}
+}
+
+func TestParseEncodingErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"REX.W +",
+		"0F ZZ /r",
+		"B8+rq",
+		"VEX128.66.0F.WIG 17 /r",
+		"VEX.NDS.128.660F.WIG DC /r",
+		"VEX.DDS.256.66.0F38.0 BA /r",
+		"VEX.128.66.0F3A.W0.1D /r ib",
+		"VEX.128.66.0F.WIG 0F 17 /r",
+		"VEX.512.66.0F.W1 58 /r",
+	} {
+		if e, err := ParseEncoding(s); err == nil {
+			t.Errorf("%q parsed as %+v", s, e)
+		}
+	}
+}
+
+func TestDisp8(t *testing.T) {
+	cases := []struct {
+		enc   string
+		kinds []string
+		want  int
+	}{
+		{"VEX.NDS.256.66.0F.WIG 58 /r", []string{"ymm1", "ymm2", "ymm3/m256"}, 0},
+		{"EVEX.NDS.512.66.0F.W1 58 /r", []string{"zmm1{k1}{z}", "zmm2", "zmm3/m512/m64bcst"}, 6},
+		{"EVEX.NDS.LIG.F2.0F.W1 58 /r", []string{"xmm1{k1}{z}", "xmm2", "xmm3/m64{er}"}, 3},
+		{"EVEX.NDS.512.66.0F.W0 F2 /r", []string{"zmm1{k1}{z}", "zmm2", "xmm3/m128"}, 4},
+		{"EVEX.512.66.0F38.W1 92 /r", []string{"zmm1{k1}", "vm32y"}, 3},
+		{"EVEX.512.66.0F38.W0 92 /r", []string{"zmm1{k1}", "vm32z"}, 2},
+		{"EVEX.NDS.512.66.0F.W1 58 /r", []string{"zmm1{k1}{z}", "zmm2", "zmm3"}, 0},
+	}
+	for _, c := range cases {
+		e, err := ParseEncoding(c.enc)
+		if err != nil {
+			t.Errorf("%q: %v", c.enc, err)
+			continue
+		}
+		var ks []Kind
+		for _, s := range c.kinds {
+			k, err := ParseKind(s)
+			if err != nil {
+				t.Fatalf("%q: %v", s, err)
+			}
+			ks = append(ks, k)
+		}
+		n, err := Disp8(ks, &e)
+		if err != nil {
+			t.Errorf("%q %v: %v", c.enc, c.kinds, err)
+			continue
+		}
+		if n != c.want {
+			t.Errorf("%q %v: got %d, want %d", c.enc, c.kinds, n, c.want)
+		}
+	}
+}
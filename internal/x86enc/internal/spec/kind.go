@@ -0,0 +1,369 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Argument kinds are the strings in the operand columns of x86.csv, like
+// "r/m64", "imm8", and "xmm2/m128". Digits in register kinds such as "xmm2"
+// only distinguish arguments from each other, and the a and b suffixes of
+// kinds like "r32a" do the same.
+//
+// EVEX-encoded forms use the notation of the Intel manual for AVX-512:
+// "zmm1{k1}{z}" allows an opmask with merging or zeroing, "zmm3/m512/m64bcst"
+// allows embedded broadcast of 64-bit elements, and "zmm3{er}" and
+// "zmm3{sae}" allow static rounding and exception suppression when the
+// operand is a register.
+
+// Kind is the interpretation of an argument kind.
+type Kind struct {
+	// Name is the kind as it appears in x86.csv.
+	Name string
+	// Fixed names the only operand the argument accepts, as a Go expression
+	// in package x86enc, if it is not empty. Fixed arguments are implied by
+	// the opcode and are not otherwise encoded.
+	Fixed string
+	// Optional is set if the argument may be omitted, as with <XMM0>.
+	Optional bool
+	// Reg is the class of registers the argument accepts. RegSize is the
+	// width of the accepted registers, or 0 if any register in the class is
+	// accepted.
+	Reg     Class
+	RegSize int
+	// Mem is the form of memory operand the argument accepts. MemSize is the
+	// width in bytes of the memory, or 0 if it is unspecified.
+	Mem     Mem
+	MemSize int
+	// Bcst is the width in bytes of the element of an EVEX embedded
+	// broadcast, or 0 if the argument does not allow broadcasting.
+	Bcst int
+	// RM is set if the argument is encoded in ModRM.rm.
+	RM bool
+	// Mask is the opmask the argument allows.
+	Mask Mask
+	// Round is the rounding control the form allows when the argument is a
+	// register.
+	Round Round
+	// Imm is the width in bytes of an immediate argument. Unsigned is set if
+	// the immediate may be given as an unsigned value regardless of the
+	// operation's size.
+	Imm      int
+	Unsigned bool
+	// Rel is the width in bytes of a relative branch target.
+	Rel int
+	// Far is the width in bytes of an absolute far pointer, including its
+	// two-byte segment selector.
+	Far int
+}
+
+// Class is a class of registers.
+type Class uint8
+
+// Register classes. These correspond to the RegClass constants of x86enc.
+const (
+	ClassNone Class = iota
+	ClassGPR
+	ClassXMM
+	ClassYMM
+	ClassMMX
+	ClassSeg
+	ClassX87
+	ClassCR
+	ClassDR
+	ClassTR
+	ClassZMM
+	ClassK
+)
+
+// Mem is a form of memory operand.
+type Mem uint8
+
+const (
+	MemNone   Mem = iota
+	MemModRM      // addressed through ModRM and SIB
+	MemOffset     // absolute address encoded as an immediate
+	MemVSIBX      // vector SIB with an XMM index
+	MemVSIBY      // vector SIB with a YMM index
+	MemVSIBZ      // vector SIB with a ZMM index
+)
+
+// Mask is the opmask an argument allows.
+type Mask uint8
+
+const (
+	MaskNone  Mask = iota
+	MaskMerge      // {k1}, merging
+	MaskZero       // {k1}{z}, merging or zeroing
+)
+
+// Round is the rounding control a form allows.
+type Round uint8
+
+const (
+	RoundNone Round = iota
+	RoundSAE        // {sae}, suppressing exceptions only
+	RoundER         // {er}, static rounding
+)
+
+// fixedKinds are kinds which accept exactly one operand.
+var fixedKinds = map[string]string{
+	"0":      "Imm(0)",
+	"1":      "Imm(1)",
+	"3":      "Imm(3)",
+	"AL":     "AL",
+	"CL":     "CL",
+	"AX":     "AX",
+	"DX":     "DX",
+	"EAX":    "EAX",
+	"RAX":    "RAX",
+	"<XMM0>": "X0",
+	"ST":     "ST0",
+	"ST(0)":  "ST0",
+	"ES":     "ES",
+	"CS":     "CS",
+	"SS":     "SS",
+	"DS":     "DS",
+	"FS":     "FS",
+	"GS":     "GS",
+}
+
+// classKinds are kinds which accept any register of a class.
+var classKinds = map[string]Class{
+	"Sreg":    ClassSeg,
+	"ST(i)":   ClassX87,
+	"CR0-CR7": ClassCR,
+	"DR0-DR7": ClassDR,
+	"TR0-TR7": ClassTR,
+}
+
+// memKinds maps memory-only kinds to the widths of the memory they refer to.
+// Pairs like m16&32 and pointers like m16:32 are the total of their parts.
+// The sizes of m14/28byte and m94/108byte depend on the operand size, so
+// they are left unspecified.
+var memKinds = map[string]int{
+	"m":           0,
+	"mem":         0,
+	"m8":          1,
+	"m16":         2,
+	"m32":         4,
+	"m64":         8,
+	"m128":        16,
+	"m256":        32,
+	"m512":        64,
+	"m2byte":      2,
+	"m14/28byte":  0,
+	"m94/108byte": 0,
+	"m512byte":    512,
+	"m16int":      2,
+	"m32int":      4,
+	"m64int":      8,
+	"m32fp":       4,
+	"m64fp":       8,
+	"m80fp":       10,
+	"m80bcd":      10,
+	"m80dec":      10,
+	"m16&16":      4,
+	"m16&32":      6,
+	"m16&64":      10,
+	"m32&32":      8,
+	"m16:16":      4,
+	"m16:32":      6,
+	"m16:64":      10,
+}
+
+// ParseKind interprets an argument kind.
+func ParseKind(s string) (Kind, error) {
+	k, err := parseKind(s)
+	k.Name = s
+	return k, err
+}
+
+func parseKind(s string) (Kind, error) {
+	if strings.HasSuffix(s, "}") {
+		return parseDecorated(s)
+	}
+	if o, ok := fixedKinds[s]; ok {
+		return Kind{Fixed: o, Optional: s == "<XMM0>"}, nil
+	}
+	if c, ok := classKinds[s]; ok {
+		return Kind{Reg: c}, nil
+	}
+	if n, ok := memKinds[s]; ok {
+		return Kind{Mem: MemModRM, MemSize: n, RM: true}, nil
+	}
+	if i := strings.LastIndexByte(s, '/'); i >= 0 && strings.HasSuffix(s, "bcst") {
+		// Vector or memory with broadcast, like zmm3/m512/m64bcst.
+		n, err := kindBits(strings.TrimSuffix(s[i+2:], "bcst"))
+		if err != nil || n < 16 {
+			return Kind{}, fmt.Errorf("bad broadcast in argument kind %q", s)
+		}
+		k, err := parseKind(s[:i])
+		if err != nil || k.Mem != MemModRM || k.Bcst != 0 {
+			return Kind{}, fmt.Errorf("bad memory in argument kind %q", s)
+		}
+		k.Bcst = n / 8
+		return k, nil
+	}
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		// Register or memory, like r/m32, r32/m16, or xmm2/m64.
+		r, m := s[:i], s[i+1:]
+		if r == "r" {
+			r = "r" + m[1:]
+		}
+		k, err := parseKind(r)
+		if err != nil || k.Reg == ClassNone {
+			return Kind{}, fmt.Errorf("bad register in argument kind %q", s)
+		}
+		mk, err := parseKind(m)
+		if err != nil || mk.Mem != MemModRM {
+			return Kind{}, fmt.Errorf("bad memory in argument kind %q", s)
+		}
+		k.Mem, k.MemSize, k.RM = mk.Mem, mk.MemSize, true
+		return k, nil
+	}
+	switch {
+	case strings.HasPrefix(s, "imm"):
+		n, err := kindBits(strings.TrimSuffix(s[3:], "u"))
+		if err != nil {
+			return Kind{}, fmt.Errorf("bad immediate kind %q", s)
+		}
+		return Kind{Imm: n / 8, Unsigned: strings.HasSuffix(s, "u")}, nil
+	case strings.HasPrefix(s, "rel"):
+		n, err := kindBits(s[3:])
+		if err != nil || n == 64 {
+			return Kind{}, fmt.Errorf("bad relative kind %q", s)
+		}
+		return Kind{Rel: n / 8}, nil
+	case strings.HasPrefix(s, "moffs"):
+		n, err := kindBits(s[5:])
+		if err != nil {
+			return Kind{}, fmt.Errorf("bad memory offset kind %q", s)
+		}
+		return Kind{Mem: MemOffset, MemSize: n / 8}, nil
+	case strings.HasPrefix(s, "ptr16:"):
+		n, err := kindBits(s[6:])
+		if err != nil || n == 8 || n == 64 {
+			return Kind{}, fmt.Errorf("bad far pointer kind %q", s)
+		}
+		return Kind{Far: n/8 + 2}, nil
+	case strings.HasPrefix(s, "rmf"):
+		// A general-purpose register in ModRM.rm, as with MOV to and from
+		// control registers, which ignore ModRM.mod.
+		n, err := kindBits(s[3:])
+		if err != nil {
+			return Kind{}, fmt.Errorf("bad register kind %q", s)
+		}
+		return Kind{Reg: ClassGPR, RegSize: n / 8, RM: true}, nil
+	case strings.HasPrefix(s, "vm"):
+		switch s {
+		case "vm32x", "vm64x":
+			return Kind{Mem: MemVSIBX, RM: true}, nil
+		case "vm32y", "vm64y":
+			return Kind{Mem: MemVSIBY, RM: true}, nil
+		case "vm32z", "vm64z":
+			return Kind{Mem: MemVSIBZ, RM: true}, nil
+		}
+	case strings.HasPrefix(s, "xmm"):
+		if isDigits(s[3:]) {
+			return Kind{Reg: ClassXMM}, nil
+		}
+	case strings.HasPrefix(s, "ymm"):
+		if isDigits(s[3:]) {
+			return Kind{Reg: ClassYMM}, nil
+		}
+	case strings.HasPrefix(s, "zmm"):
+		if isDigits(s[3:]) {
+			return Kind{Reg: ClassZMM}, nil
+		}
+	case strings.HasPrefix(s, "k"):
+		if isDigits(s[1:]) {
+			return Kind{Reg: ClassK}, nil
+		}
+	case strings.HasPrefix(s, "mm"):
+		if isDigits(s[2:]) {
+			return Kind{Reg: ClassMMX}, nil
+		}
+	case strings.HasPrefix(s, "r"):
+		n, err := kindBits(strings.TrimRight(s[1:], "abop"))
+		if err != nil {
+			return Kind{}, fmt.Errorf("bad register kind %q", s)
+		}
+		return Kind{Reg: ClassGPR, RegSize: n / 8}, nil
+	}
+	return Kind{}, fmt.Errorf("unknown argument kind %q", s)
+}
+
+// parseDecorated interprets an argument kind with AVX-512 decorations like
+// {k1}, {z}, {er}, and {sae} following it.
+func parseDecorated(s string) (Kind, error) {
+	i := strings.IndexByte(s, '{')
+	k, err := parseKind(s[:i])
+	if err != nil {
+		return Kind{}, err
+	}
+	for _, d := range strings.SplitAfter(s[i:], "}") {
+		switch d {
+		case "":
+		case "{k1}", "{k2}":
+			if k.Mask != MaskNone {
+				return Kind{}, fmt.Errorf("multiple opmasks in argument kind %q", s)
+			}
+			k.Mask = MaskMerge
+		case "{z}":
+			if k.Mask != MaskMerge {
+				return Kind{}, fmt.Errorf("zeroing without opmask in argument kind %q", s)
+			}
+			k.Mask = MaskZero
+		case "{er}", "{sae}":
+			if k.Round != RoundNone || k.Reg == ClassNone {
+				return Kind{}, fmt.Errorf("bad rounding in argument kind %q", s)
+			}
+			k.Round = RoundSAE
+			if d == "{er}" {
+				k.Round = RoundER
+			}
+		default:
+			return Kind{}, fmt.Errorf("bad decoration %q in argument kind %q", d, s)
+		}
+	}
+	return k, nil
+}
+
+// kindBits parses the width in bits at the end of an argument kind.
+func kindBits(s string) (int, error) {
+	switch s {
+	case "8", "16", "32", "64":
+		return strconv.Atoi(s)
+	}
+	return 0, fmt.Errorf("bad width %q", s)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsFixed returns whether the kind is a specific operand implied by the
+// opcode.
+func (k *Kind) IsFixed() bool {
+	return k.Fixed != ""
+}
+
+// IsImm returns whether the kind is encoded in the immediate bytes following
+// the opcode and ModRM: an immediate, branch target, memory offset, or far
+// pointer.
+func (k *Kind) IsImm() bool {
+	return k.Imm != 0 || k.Rel != 0 || k.Far != 0 || k.Mem == MemOffset
+}
+
+// IsReg returns whether the kind is a register which is encoded in the
+// instruction.
+func (k *Kind) IsReg() bool {
+	return k.Fixed == "" && k.Reg != ClassNone
+}
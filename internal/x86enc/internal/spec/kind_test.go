@@ -0,0 +1,58 @@
+package spec
+
+import "testing"
+
+func TestParseKind(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Kind
+	}{
+		{"AL", Kind{Fixed: "AL"}},
+		{"1", Kind{Fixed: "Imm(1)"}},
+		{"<XMM0>", Kind{Fixed: "X0", Optional: true}},
+		{"ST(i)", Kind{Reg: ClassX87}},
+		{"r32a", Kind{Reg: ClassGPR, RegSize: 4}},
+		{"r64op", Kind{Reg: ClassGPR, RegSize: 8}},
+		{"rmf64", Kind{Reg: ClassGPR, RegSize: 8, RM: true}},
+		{"r/m16", Kind{Reg: ClassGPR, RegSize: 2, Mem: MemModRM, MemSize: 2, RM: true}},
+		{"r32/m8", Kind{Reg: ClassGPR, RegSize: 4, Mem: MemModRM, MemSize: 1, RM: true}},
+		{"xmm2/m64", Kind{Reg: ClassXMM, Mem: MemModRM, MemSize: 8, RM: true}},
+		{"mm/m64", Kind{Reg: ClassMMX, Mem: MemModRM, MemSize: 8, RM: true}},
+		{"m14/28byte", Kind{Mem: MemModRM, RM: true}},
+		{"m16:64", Kind{Mem: MemModRM, MemSize: 10, RM: true}},
+		{"vm32y", Kind{Mem: MemVSIBY, RM: true}},
+		{"moffs64", Kind{Mem: MemOffset, MemSize: 8}},
+		{"imm8u", Kind{Imm: 1, Unsigned: true}},
+		{"imm64", Kind{Imm: 8}},
+		{"rel32", Kind{Rel: 4}},
+		{"ptr16:32", Kind{Far: 6}},
+		{"zmm1{k1}{z}", Kind{Reg: ClassZMM, Mask: MaskZero}},
+		{"k1{k2}", Kind{Reg: ClassK, Mask: MaskMerge}},
+		{"k2/m16", Kind{Reg: ClassK, Mem: MemModRM, MemSize: 2, RM: true}},
+		{"zmm3/m512/m64bcst", Kind{Reg: ClassZMM, Mem: MemModRM, MemSize: 64, Bcst: 8, RM: true}},
+		{"m256/m32bcst", Kind{Mem: MemModRM, MemSize: 32, Bcst: 4, RM: true}},
+		{"xmm3{er}", Kind{Reg: ClassXMM, Round: RoundER}},
+		{"zmm3{sae}", Kind{Reg: ClassZMM, Round: RoundSAE}},
+		{"m512{k1}", Kind{Mem: MemModRM, MemSize: 64, RM: true, Mask: MaskMerge}},
+		{"vm64z{k1}", Kind{Mem: MemVSIBZ, RM: true, Mask: MaskMerge}},
+	}
+	for _, c := range cases {
+		k, err := ParseKind(c.s)
+		if err != nil {
+			t.Errorf("%q: %v", c.s, err)
+			continue
+		}
+		c.want.Name = c.s
+		if k != c.want {
+			t.Errorf("%q: got %+v, want %+v", c.s, k, c.want)
+		}
+	}
+}
+
+func TestParseKindErrors(t *testing.T) {
+	for _, s := range []string{"", "r7", "xmmm", "imm12", "rel64", "q/m32", "r32/xmm1", "zmm1{z}", "zmm1{k1}{k2}", "m64{er}", "xmm1/m8bcst", "zmm2/m512/m64bcst/m32bcst", "xmm1{q}"} {
+		if k, err := ParseKind(s); err == nil {
+			t.Errorf("%q parsed as %+v", s, k)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package spec
+
+import "fmt"
+
+// Role is the part of an encoding which holds an operand.
+type Role int
+
+// Operand roles.
+const (
+	RoleNone   Role = iota // fixed or omitted
+	RoleReg                // ModRM.reg
+	RoleRM                 // ModRM.rm, with SIB and displacement for memory
+	RoleVVVV               // VEX.vvvv
+	RoleOpcode             // added to the last opcode byte
+	RoleImm                // immediate bytes
+	RoleIs4                // high bits of an immediate byte
+)
+
+// Roles assigns the arguments of a form to the parts of its encoding. It
+// returns an error if any argument cannot be placed or if any part of the
+// encoding which requires an operand receives none.
+//
+// Fixed arguments and immediates are assigned first. If a register is added
+// to the opcode, it is the first register. ModRM.rm holds the first argument
+// which may be memory, or if there is none, the last register. ModRM.reg
+// holds the first remaining register, then VEX.vvvv the next, then is4.
+func Roles(kinds []Kind, e *Encoding) ([]Role, error) {
+	rs := make([]Role, len(kinds))
+	used := make([]bool, len(kinds))
+	imms := 0
+	for i := range kinds {
+		k := &kinds[i]
+		switch {
+		case k.IsFixed():
+			used[i] = true
+		case k.IsImm():
+			rs[i] = RoleImm
+			used[i] = true
+			imms++
+		}
+	}
+	take := func(r Role, pred func(*Kind) bool, last bool) bool {
+		j := -1
+		for i := range kinds {
+			if !used[i] && pred(&kinds[i]) {
+				j = i
+				if !last {
+					break
+				}
+			}
+		}
+		if j < 0 {
+			return false
+		}
+		rs[j] = r
+		used[j] = true
+		return true
+	}
+	rm := func(k *Kind) bool { return k.RM }
+	if e.Plus && !take(RoleOpcode, (*Kind).IsReg, false) {
+		return nil, fmt.Errorf("no register to add to opcode")
+	}
+	if e.ModRM != ModRMNone {
+		if !take(RoleRM, rm, false) && !take(RoleRM, (*Kind).IsReg, true) {
+			return nil, fmt.Errorf("no operand for ModRM.rm")
+		}
+		if e.ModRM == ModRMReg && !take(RoleReg, (*Kind).IsReg, false) {
+			return nil, fmt.Errorf("no operand for ModRM.reg")
+		}
+	}
+	if e.VEX != nil && e.VEX.V && !take(RoleVVVV, (*Kind).IsReg, false) {
+		return nil, fmt.Errorf("no operand for VEX.vvvv")
+	}
+	if e.Is4 && !take(RoleIs4, (*Kind).IsReg, false) {
+		return nil, fmt.Errorf("no operand for is4")
+	}
+	for i, u := range used {
+		if !u && !kinds[i].Optional {
+			return nil, fmt.Errorf("no place to encode %s", kinds[i].Name)
+		}
+	}
+	if imms != len(e.Imm) {
+		return nil, fmt.Errorf("%d immediate operands but %d immediates", imms, len(e.Imm))
+	}
+	return rs, nil
+}
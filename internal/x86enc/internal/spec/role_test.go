@@ -0,0 +1,80 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoles(t *testing.T) {
+	cases := []struct {
+		enc   string
+		kinds []string
+		want  []Role
+	}{
+		{"REX.W + 01 /r", []string{"r/m64", "r64"}, []Role{RoleRM, RoleReg}},
+		{"REX.W + 03 /r", []string{"r64", "r/m64"}, []Role{RoleReg, RoleRM}},
+		{"REX.W + 81 /0 id", []string{"r/m64", "imm32"}, []Role{RoleRM, RoleImm}},
+		{"05 id", []string{"EAX", "imm32"}, []Role{RoleNone, RoleImm}},
+		{"B8+rd id", []string{"r32op", "imm32"}, []Role{RoleOpcode, RoleImm}},
+		{"D8 C0+i", []string{"ST(0)", "ST(i)"}, []Role{RoleNone, RoleOpcode}},
+		{"0F 20 /r", []string{"rmf64", "CR0-CR7"}, []Role{RoleRM, RoleReg}},
+		{"F2 0F D6 /r", []string{"mm", "xmm2"}, []Role{RoleReg, RoleRM}},
+		{"66 0F 38 15 /r", []string{"xmm1", "xmm2/m128", "<XMM0>"}, []Role{RoleReg, RoleRM, RoleNone}},
+		{"VEX.NDS.LZ.0F38.W0 F7 /r", []string{"r32a", "r/m32", "r32b"}, []Role{RoleReg, RoleRM, RoleVVVV}},
+		{"VEX.NDD.128.66.0F.WIG 73 /7 ib", []string{"xmm1", "xmm2", "imm8u"}, []Role{RoleVVVV, RoleRM, RoleImm}},
+		{"VEX.DDS.128.66.0F38.W1 92 /r", []string{"xmm1", "vm32x", "xmm2"}, []Role{RoleReg, RoleRM, RoleVVVV}},
+		{"VEX.NDS.128.66.0F3A.W0 4B /r /is4", []string{"xmm1", "xmm2", "xmm3/m128", "xmm4"}, []Role{RoleReg, RoleVVVV, RoleRM, RoleIs4}},
+		{"A1 cm", []string{"EAX", "moffs32"}, []Role{RoleNone, RoleImm}},
+	}
+	for _, c := range cases {
+		e, err := ParseEncoding(c.enc)
+		if err != nil {
+			t.Fatalf("%q: %v", c.enc, err)
+		}
+		ks := make([]Kind, len(c.kinds))
+		for i, s := range c.kinds {
+			ks[i], err = ParseKind(s)
+			if err != nil {
+				t.Fatalf("%q: %v", s, err)
+			}
+		}
+		rs, err := Roles(ks, &e)
+		if err != nil {
+			t.Errorf("%q %v: %v", c.enc, c.kinds, err)
+			continue
+		}
+		if !reflect.DeepEqual(rs, c.want) {
+			t.Errorf("%q %v: got %v, want %v", c.enc, c.kinds, rs, c.want)
+		}
+	}
+}
+
+func TestRolesErrors(t *testing.T) {
+	cases := []struct {
+		enc   string
+		kinds []string
+	}{
+		{"0F 94 /r", []string{"r/m8"}},
+		{"04 ib", []string{"AL"}},
+		{"04", []string{"AL", "imm8"}},
+		{"B8+rd id", []string{"imm32"}},
+		{"01 /r", []string{"r/m32", "r32", "r32"}},
+		{"VEX.NDS.128.66.0F.WIG 58 /r", []string{"xmm1", "xmm2/m128"}},
+	}
+	for _, c := range cases {
+		e, err := ParseEncoding(c.enc)
+		if err != nil {
+			t.Fatalf("%q: %v", c.enc, err)
+		}
+		ks := make([]Kind, len(c.kinds))
+		for i, s := range c.kinds {
+			ks[i], err = ParseKind(s)
+			if err != nil {
+				t.Fatalf("%q: %v", s, err)
+			}
+		}
+		if rs, err := Roles(ks, &e); err == nil {
+			t.Errorf("%q %v: got roles %v", c.enc, c.kinds, rs)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package spec interprets the instruction forms described by x86.csv. It is
+// used by mkenc to precompile the encoding table for x86enc.
+package spec
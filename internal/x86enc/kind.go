@@ -0,0 +1,151 @@
+package x86enc
+
+// kind is the interpretation of an argument kind, one of the strings in the
+// operand columns of x86.csv like "r/m64", "imm8", or "xmm2/m128". mkenc
+// generates kindTable, which holds every kind used by the table.
+type kind struct {
+	// name is the kind as it appears in x86.csv.
+	name string
+	// fixed is the only operand the argument accepts, if it is not nil. Fixed
+	// arguments are implied by the opcode and are not otherwise encoded.
+	fixed Operand
+	// optional is set if the argument may be omitted, as with <XMM0>.
+	optional bool
+	// reg is the class of registers the argument accepts, or ClassNone if it
+	// accepts no registers. regSize is the width of the accepted registers, or
+	// 0 if any register in the class is accepted.
+	reg     RegClass
+	regSize int
+	// mem is the kind of memory operand the argument accepts. memSize is the
+	// width in bytes of the memory, or 0 if it is unspecified.
+	mem     memKind
+	memSize int
+	// bcst is the width in bytes of the element of an EVEX embedded
+	// broadcast, or 0 if the argument does not allow broadcasting.
+	bcst int
+	// rm is set if the argument is encoded in ModRM.rm.
+	rm bool
+	// mask is the opmask the argument allows, and round is the rounding
+	// control the form allows when the argument is a register.
+	mask  maskKind
+	round roundKind
+	// imm is the width in bytes of an immediate argument. unsigned is set if
+	// the immediate may be given as an unsigned value regardless of the
+	// operation's size.
+	imm      int
+	unsigned bool
+	// rel is the width in bytes of a relative branch target.
+	rel int
+	// far is the width in bytes of an absolute far pointer, including its
+	// two-byte segment selector.
+	far int
+}
+
+// memKind is a form of memory operand.
+type memKind uint8
+
+const (
+	memNone   memKind = iota
+	memModRM          // addressed through ModRM and SIB
+	memOffset         // absolute address encoded as an immediate
+	memVSIBX          // vector SIB with an XMM index
+	memVSIBY          // vector SIB with a YMM index
+	memVSIBZ          // vector SIB with a ZMM index
+)
+
+// maskKind is the opmask an EVEX-encoded argument allows.
+type maskKind uint8
+
+const (
+	maskNone  maskKind = iota
+	maskMerge          // {k1}, merging
+	maskZero           // {k1}{z}, merging or zeroing
+)
+
+// roundKind is the rounding control an EVEX-encoded form allows.
+type roundKind uint8
+
+const (
+	roundNone roundKind = iota
+	roundSAE            // {sae}, suppressing exceptions only
+	roundER             // {er}, static rounding
+)
+
+// gprSize returns the width in bytes of general-purpose register operands of
+// the kind, or 0 if the kind accepts none.
+func (k *kind) gprSize() int {
+	if r, ok := k.fixed.(Reg); ok && r.Class() == ClassGPR {
+		return r.Size()
+	}
+	if k.reg == ClassGPR {
+		return k.regSize
+	}
+	return 0
+}
+
+// accepts returns whether the operand satisfies the kind. size is the operand
+// size of the form as immSize gives it, which determines whether immediates
+// may be given as unsigned values.
+func (k *kind) accepts(o Operand, size int) bool {
+	if k.fixed != nil {
+		return o == k.fixed
+	}
+	switch o := o.(type) {
+	case Masked:
+		if k.mask == maskNone || o.Zero && k.mask != maskZero {
+			return false
+		}
+		if _, ok := o.Op.(Masked); ok {
+			return false
+		}
+		return k.accepts(o.Op, size)
+	case Imm:
+		if k.imm == 0 {
+			return false
+		}
+		// Unsigned immediates and immediates as wide as the operation may be
+		// given as either signed or unsigned values; narrower immediates are
+		// sign-extended, so they must fit as signed values.
+		if k.unsigned || k.imm == size {
+			return o.FitsSigned(8*k.imm) || o.FitsUnsigned(8*k.imm)
+		}
+		return o.FitsSigned(8 * k.imm)
+	case Rel:
+		return k.rel != 0 && Imm(o).FitsSigned(8*k.rel)
+	case FarPtr:
+		return k.far != 0 && Imm(o.Off).FitsUnsigned(8*(k.far-2))
+	case Mem:
+		return k.acceptsMem(o)
+	case Reg:
+		return k.reg != ClassNone && o.Class() == k.reg && (k.regSize == 0 || o.Size() == k.regSize)
+	}
+	return false
+}
+
+// acceptsMem returns whether a memory operand satisfies the kind.
+func (k *kind) acceptsMem(m Mem) bool {
+	if m.Broadcast {
+		if k.bcst == 0 || m.Size != 0 && m.Size != k.bcst {
+			return false
+		}
+	} else if m.Size != 0 && k.memSize != 0 && m.Size != k.memSize {
+		return false
+	}
+	var index RegClass
+	if m.Index != nil {
+		index = m.Index.Class()
+	}
+	switch k.mem {
+	case memModRM:
+		return index == ClassNone || index == ClassGPR
+	case memOffset:
+		return m.Base == nil && m.Index == nil
+	case memVSIBX:
+		return index == ClassXMM
+	case memVSIBY:
+		return index == ClassYMM
+	case memVSIBZ:
+		return index == ClassZMM
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package x86enc
+
+import "testing"
+
+// kindNamed returns the kind in kindTable with the given name.
+func kindNamed(name string) *kind {
+	for i := range kindTable {
+		if kindTable[i].name == name {
+			return &kindTable[i]
+		}
+	}
+	return nil
+}
+
+// TestKindTable tests that every kind in the table accepts some operand.
+func TestKindTable(t *testing.T) {
+	for _, k := range kindTable[1:] {
+		if k.fixed == nil && k.reg == ClassNone && k.mem == memNone && k.imm == 0 && k.rel == 0 && k.far == 0 {
+			t.Errorf("%q accepts nothing", k.name)
+		}
+	}
+}
+
+func TestKindAccepts(t *testing.T) {
+	cases := []struct {
+		kind string
+		size int
+		o    Operand
+		want bool
+	}{
+		{"AL", 1, AL, true},
+		{"AL", 1, CL, false},
+		{"1", 4, Imm(1), true},
+		{"1", 4, Imm(2), false},
+		{"<XMM0>", 0, X0, true},
+		{"ST", 0, ST0, true},
+		{"ST(i)", 0, ST(5), true},
+		{"ST(i)", 0, X5, false},
+		{"FS", 0, FS, true},
+		{"Sreg", 0, SS, true},
+		{"Sreg", 0, AX, false},
+		{"CR0-CR7", 0, CR(0), true},
+		{"DR0-DR7", 0, CR(0), false},
+		{"r32a", 4, R9D, true},
+		{"r64op", 8, R9D, false},
+		{"r8", 1, AH, true},
+		{"rmf64", 8, RDI, true},
+		{"rmf64", 8, Mem{Base: RDI}, false},
+		{"r/m16", 2, Mem{Base: RAX}, true},
+		{"r/m16", 2, Mem{Base: RAX, Size: 2}, true},
+		{"r/m16", 2, Mem{Base: RAX, Size: 4}, false},
+		{"r/m16", 2, DX, true},
+		{"r/m16", 2, EDX, false},
+		{"r32/m16", 4, EDX, true},
+		{"r32/m16", 4, Mem{Base: RAX, Size: 2}, true},
+		{"xmm2/m64", 0, X12, true},
+		{"xmm2/m64", 0, Y12, false},
+		{"xmm2/m64", 0, Mem{Base: RIP, Size: 8}, true},
+		{"ymm2/m256", 0, Y1, true},
+		{"mm/m64", 0, M7, true},
+		{"mm/m64", 0, X7, false},
+		{"m", 0, Mem{Base: RAX, Size: 16}, true},
+		{"m80fp", 0, Mem{Base: RAX, Size: 10}, true},
+		{"m80fp", 0, Mem{Base: RAX, Size: 8}, false},
+		{"m14/28byte", 0, Mem{Base: RAX, Size: 28}, true},
+		{"m16&64", 8, Mem{Base: RAX, Size: 10}, true},
+		{"m64", 0, RAX, false},
+		{"m64", 0, Mem{Base: RAX, Index: X1, Scale: 1}, false},
+		{"vm32x", 0, Mem{Base: RAX, Index: X1, Scale: 4}, true},
+		{"vm32x", 0, Mem{Base: RAX, Index: Y1, Scale: 4}, false},
+		{"vm64y", 0, Mem{Base: RAX, Index: Y1, Scale: 4}, true},
+		{"moffs32", 4, Mem{Disp: 0x100}, true},
+		{"moffs32", 4, Mem{Base: RAX}, false},
+		{"imm8", 4, Imm(-128), true},
+		{"imm8", 4, Imm(255), false},
+		{"imm8", 1, Imm(255), true},
+		{"imm8u", 4, Imm(255), true},
+		{"imm8u", 4, Imm(256), false},
+		{"imm32", 8, Imm(0xffffffff), false},
+		{"imm32", 4, Imm(0xffffffff), true},
+		{"imm64", 8, Imm(-1), true},
+		{"imm16", 2, Rel(0), false},
+		{"rel8", 0, Rel(-128), true},
+		{"rel8", 0, Rel(128), false},
+		{"rel32", 0, Rel(-1 << 31), true},
+		{"rel32", 0, Imm(0), false},
+		{"ptr16:16", 0, FarPtr{Seg: 8, Off: 0xffff}, true},
+		{"ptr16:16", 0, FarPtr{Seg: 8, Off: 0x10000}, false},
+		{"ptr16:32", 0, FarPtr{Seg: 8, Off: 0x10000}, true},
+	}
+	for _, c := range cases {
+		k := kindNamed(c.kind)
+		if k == nil {
+			t.Errorf("no kind %q", c.kind)
+			continue
+		}
+		if got := k.accepts(c.o, c.size); got != c.want {
+			t.Errorf("%q with size %d accepts %v: got %t, want %t", c.kind, c.size, c.o, got, c.want)
+		}
+	}
+}
+
+func TestImmFits(t *testing.T) {
+	cases := []struct {
+		i                Imm
+		bits             int
+		signed, unsigned bool
+	}{
+		{0, 8, true, true},
+		{127, 8, true, true},
+		{128, 8, false, true},
+		{255, 8, false, true},
+		{256, 8, false, false},
+		{-128, 8, true, false},
+		{-129, 8, false, false},
+		{1 << 31, 32, false, true},
+		{-1 << 31, 32, true, false},
+		{-1, 64, true, false},
+		{1<<63 - 1, 64, true, true},
+	}
+	for _, c := range cases {
+		if got := c.i.FitsSigned(c.bits); got != c.signed {
+			t.Errorf("%d.FitsSigned(%d) = %t", c.i, c.bits, got)
+		}
+		if got := c.i.FitsUnsigned(c.bits); got != c.unsigned {
+			t.Errorf("%d.FitsUnsigned(%d) = %t", c.i, c.bits, got)
+		}
+	}
+}
@@ -0,0 +1,453 @@
+// Code generated by mkenc; DO NOT EDIT.
+//
+// Sources:
+//	x86.csv sha256:fcbb870b925e607abec992bde67d2c9fab251d70c3151d1541981b5fe9a182b1
+//	evex.csv sha256:3c2ee39d0f826ca169fc168aa33d1b2bc57b02abcdb37693c4e490b5802b4552
+//	meta.csv sha256:5f5f0318ecfe764d00f5af4cbbefdd00ec2b101907117a4ad09f2f2b36e885eb
+
+package x86enc
+
+type meta struct {
+	access        [4]Access
+	reads, writes []Reg
+	// writesMask is whether the instruction writes the opmask of its
+	// operands, as gathers and scatters clear it.
+	writesMask   bool
+	flagsRead    Flags
+	flagsWritten Flags
+	mem          Access // implicit memory access
+	latency      Latency
+}
+
+var metaTable = [...]meta{
+	{reads: []Reg{AL, AH}, writes: []Reg{AL, AH}, flagsRead: AF, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{Read}, reads: []Reg{AL, AH}, writes: []Reg{AL, AH}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{reads: []Reg{AL, AH}, writes: []Reg{AL, AH}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{Read}, reads: []Reg{AL}, writes: []Reg{AL, AH}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{reads: []Reg{AL}, writes: []Reg{AL, AH}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: CF, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, latency: LatencyMedium},
+	{access: [4]Access{Write, Read}, latency: LatencyMedium},
+	{access: [4]Access{Write, Read, Read}, latency: LatencyMedium},
+	{access: [4]Access{Write, Read, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsWritten: ZF, latency: LatencyMicrocode},
+	{access: [4]Access{Write, Read, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{ReadWrite, Read, Read}, latency: LatencyFast},
+	{access: [4]Access{Write, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{Read, Read}, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{ReadWrite}, latency: LatencyFast},
+	{access: [4]Access{Read, Read}, flagsWritten: CF | PF | AF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsWritten: CF | PF | AF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{Read}, reads: []Reg{RSP}, writes: []Reg{RSP}, mem: Write, latency: LatencyBranch},
+	{reads: []Reg{AL}, writes: []Reg{AX}, latency: LatencyFast},
+	{reads: []Reg{EAX}, writes: []Reg{EDX}, latency: LatencyFast},
+	{reads: []Reg{EAX}, writes: []Reg{RAX}, latency: LatencyFast},
+	{flagsWritten: CF, latency: LatencyFast},
+	{flagsWritten: DF, latency: LatencyFast},
+	{access: [4]Access{Write}, latency: LatencyMicrocode},
+	{latency: LatencyMicrocode},
+	{flagsRead: CF, flagsWritten: CF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: CF | ZF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: CF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: ZF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: ZF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: SF | OF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: OF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: PF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: SF, latency: LatencyFast},
+	{access: [4]Access{Read, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read, Read}, latency: LatencyMedium},
+	{reads: []Reg{RSI, RDI}, writes: []Reg{RSI, RDI}, flagsRead: DF, flagsWritten: CF | PF | AF | ZF | SF | OF, mem: Read, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite, Read}, reads: []Reg{AX}, writes: []Reg{AX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{ReadWrite, Read}, reads: []Reg{EAX}, writes: []Reg{EAX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{ReadWrite, Read}, reads: []Reg{RAX}, writes: []Reg{RAX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{ReadWrite, Read}, reads: []Reg{AL}, writes: []Reg{AL}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{ReadWrite}, reads: []Reg{RAX, RBX, RCX, RDX}, writes: []Reg{RAX, RDX}, flagsWritten: ZF, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite}, reads: []Reg{EAX, EBX, ECX, EDX}, writes: []Reg{EAX, EDX}, flagsWritten: ZF, latency: LatencyMicrocode},
+	{access: [4]Access{Read, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{reads: []Reg{EAX, ECX}, writes: []Reg{EAX, EBX, ECX, EDX}, latency: LatencyMicrocode},
+	{reads: []Reg{RAX}, writes: []Reg{RDX}, latency: LatencyFast},
+	{reads: []Reg{AX}, writes: []Reg{DX}, latency: LatencyFast},
+	{reads: []Reg{AX}, writes: []Reg{EAX}, latency: LatencyFast},
+	{reads: []Reg{AL}, writes: []Reg{AL}, flagsRead: CF | AF, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite}, flagsWritten: PF | AF | ZF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{Read}, reads: []Reg{AX, DX}, writes: []Reg{AX, DX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyDivide},
+	{access: [4]Access{Read}, reads: []Reg{EAX, EDX}, writes: []Reg{EAX, EDX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyDivide},
+	{access: [4]Access{Read}, reads: []Reg{RAX, RDX}, writes: []Reg{RAX, RDX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyDivide},
+	{access: [4]Access{Read}, reads: []Reg{AX}, writes: []Reg{AX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyDivide},
+	{access: [4]Access{ReadWrite, Read}, latency: LatencyDivide},
+	{access: [4]Access{Read, Read}, reads: []Reg{RSP, RBP}, writes: []Reg{RSP, RBP}, mem: ReadWrite, latency: LatencyMicrocode},
+	{access: [4]Access{Write, Read, Read}, latency: LatencyShuffle},
+	{reads: []Reg{ST0}, writes: []Reg{ST0}, latency: LatencyMicrocode},
+	{reads: []Reg{ST0}, writes: []Reg{ST0}, latency: LatencyMedium},
+	{access: [4]Access{Read}, reads: []Reg{ST0}, writes: []Reg{ST0}, latency: LatencyMedium},
+	{access: [4]Access{Read}, writes: []Reg{ST0}, latency: LatencyMicrocode},
+	{access: [4]Access{Write}, reads: []Reg{ST0}, writes: []Reg{ST0}, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: CF | PF | ZF, latency: LatencyMedium},
+	{latency: LatencyFast},
+	{access: [4]Access{Read}, reads: []Reg{ST0}, writes: []Reg{ST0}, latency: LatencyDivide},
+	{reads: []Reg{ST0}, writes: []Reg{ST0}, latency: LatencyDivide},
+	{access: [4]Access{Write}, latency: LatencyFast},
+	{access: [4]Access{Read}, writes: []Reg{ST0}, latency: LatencyMedium},
+	{access: [4]Access{Write}, reads: []Reg{ST0}, writes: []Reg{ST0}, latency: LatencyMedium},
+	{writes: []Reg{ST0}, latency: LatencyMedium},
+	{access: [4]Access{Read}, latency: LatencyMicrocode},
+	{writes: []Reg{ST0, ST1, ST2, ST3, ST4, ST5, ST6, ST7}, latency: LatencyMicrocode},
+	{access: [4]Access{Write}, writes: []Reg{ST0, ST1, ST2, ST3, ST4, ST5, ST6, ST7}, latency: LatencyMicrocode},
+	{access: [4]Access{Write}, latency: LatencyMedium},
+	{reads: []Reg{ST0, ST1}, writes: []Reg{ST0, ST1}, latency: LatencyMicrocode},
+	{reads: []Reg{ST0, ST1}, writes: []Reg{ST0}, latency: LatencyMicrocode},
+	{reads: []Reg{ST0}, writes: []Reg{ST0, ST1}, latency: LatencyMicrocode},
+	{access: [4]Access{Read}, writes: []Reg{ST0, ST1, ST2, ST3, ST4, ST5, ST6, ST7}, latency: LatencyMicrocode},
+	{access: [4]Access{Write}, reads: []Reg{ST0}, latency: LatencyMedium},
+	{latency: LatencyMedium},
+	{access: [4]Access{ReadWrite}, reads: []Reg{ST0}, writes: []Reg{ST0}, latency: LatencyFast},
+	{reads: []Reg{ST0}, writes: []Reg{ST0}, latency: LatencyFast},
+	{reads: []Reg{RSP}, writes: []Reg{RSP}, flagsRead: CF | PF | AF | ZF | SF | DF | OF, mem: Write, latency: LatencyMicrocode},
+	{access: [4]Access{Read}, reads: []Reg{AX}, writes: []Reg{AX, DX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{Read}, reads: []Reg{EAX}, writes: []Reg{EAX, EDX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{Read}, reads: []Reg{RAX}, writes: []Reg{RAX, RDX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{Read}, reads: []Reg{AL}, writes: []Reg{AX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{Write, Read}, latency: LatencyMicrocode},
+	{reads: []Reg{RDI, DX}, writes: []Reg{RDI}, flagsRead: DF, mem: Write, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite, Read, Read}, latency: LatencyShuffle},
+	{access: [4]Access{Read}, reads: []Reg{RSP}, writes: []Reg{RSP}, flagsRead: CF | PF | AF | ZF | SF | DF | OF, mem: Write, latency: LatencyMicrocode},
+	{access: [4]Access{0}, latency: LatencyMicrocode},
+	{reads: []Reg{RSP}, writes: []Reg{RSP}, flagsWritten: CF | PF | AF | ZF | SF | DF | OF, mem: Read, latency: LatencyMicrocode},
+	{access: [4]Access{Read}, flagsRead: CF | ZF, latency: LatencyBranch},
+	{access: [4]Access{Read}, flagsRead: CF, latency: LatencyBranch},
+	{access: [4]Access{Read}, reads: []Reg{CX}, latency: LatencyBranch},
+	{access: [4]Access{Read}, flagsRead: ZF, latency: LatencyBranch},
+	{access: [4]Access{Read}, reads: []Reg{ECX}, latency: LatencyBranch},
+	{access: [4]Access{Read}, flagsRead: ZF | SF | OF, latency: LatencyBranch},
+	{access: [4]Access{Read}, flagsRead: SF | OF, latency: LatencyBranch},
+	{access: [4]Access{Read}, latency: LatencyBranch},
+	{access: [4]Access{Read}, flagsRead: OF, latency: LatencyBranch},
+	{access: [4]Access{Read}, flagsRead: PF, latency: LatencyBranch},
+	{access: [4]Access{Read}, flagsRead: SF, latency: LatencyBranch},
+	{access: [4]Access{Read}, reads: []Reg{RCX}, latency: LatencyBranch},
+	{access: [4]Access{Write, Read, Read}, latency: LatencyFast},
+	{access: [4]Access{Write, Read}, latency: LatencyFast},
+	{writes: []Reg{AH}, flagsRead: CF | PF | AF | ZF | SF, latency: LatencyFast},
+	{access: [4]Access{Read}, reads: []Reg{RSP}, writes: []Reg{RSP}, mem: Write, latency: LatencyMicrocode},
+	{access: [4]Access{Write, 0}, latency: LatencyFast},
+	{reads: []Reg{RBP}, writes: []Reg{RSP, RBP}, mem: Read, latency: LatencyFast},
+	{reads: []Reg{RSI}, writes: []Reg{RSI, AL}, flagsRead: DF, mem: Read, latency: LatencyMicrocode},
+	{reads: []Reg{RSI}, writes: []Reg{RSI, EAX}, flagsRead: DF, mem: Read, latency: LatencyMicrocode},
+	{reads: []Reg{RSI}, writes: []Reg{RSI, RAX}, flagsRead: DF, mem: Read, latency: LatencyMicrocode},
+	{reads: []Reg{RSI}, writes: []Reg{RSI, AX}, flagsRead: DF, mem: Read, latency: LatencyMicrocode},
+	{access: [4]Access{Read}, reads: []Reg{RCX}, writes: []Reg{RCX}, latency: LatencyBranch},
+	{access: [4]Access{Read}, reads: []Reg{RCX}, writes: []Reg{RCX}, flagsRead: ZF, latency: LatencyBranch},
+	{access: [4]Access{Read}, reads: []Reg{RSP}, writes: []Reg{RSP}, mem: Read, latency: LatencyMicrocode},
+	{reads: []Reg{RSP}, writes: []Reg{RSP}, mem: Read, latency: LatencyMicrocode},
+	{access: [4]Access{Write, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{Read, Read}, reads: []Reg{RDI}, mem: Write, latency: LatencyMicrocode},
+	{reads: []Reg{RAX, ECX, EDX}, latency: LatencyMicrocode},
+	{access: [4]Access{Write, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{Write, Read}, latency: LatencyShuffle},
+	{access: [4]Access{ReadWrite, Read}, latency: LatencyShuffle},
+	{reads: []Reg{RSI, RDI}, writes: []Reg{RSI, RDI}, flagsRead: DF, mem: ReadWrite, latency: LatencyMicrocode},
+	{access: [4]Access{Write, Write, Read}, reads: []Reg{EDX}, latency: LatencyMedium},
+	{access: [4]Access{Write, Write, Read}, reads: []Reg{RDX}, latency: LatencyMedium},
+	{reads: []Reg{EAX, ECX}, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{0}, latency: LatencyFast},
+	{reads: []Reg{RSI, DX}, writes: []Reg{RSI}, flagsRead: DF, mem: Read, latency: LatencyMicrocode},
+	{access: [4]Access{Read, Read, Read}, reads: []Reg{EAX, EDX}, writes: []Reg{ECX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{Read, Read, Read}, reads: []Reg{EAX, EDX}, writes: []Reg{X0}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{Read, Read, Read}, writes: []Reg{ECX}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{Read, Read, Read}, writes: []Reg{X0}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{Write}, reads: []Reg{RSP}, writes: []Reg{RSP}, mem: Read, latency: LatencyFast},
+	{reads: []Reg{RSP}, writes: []Reg{RAX, RCX, RDX, RBX, RSP, RBP, RSI, RDI}, mem: Read, latency: LatencyMicrocode},
+	{access: [4]Access{Read}, reads: []Reg{RSP}, writes: []Reg{RSP}, mem: Write, latency: LatencyFast},
+	{reads: []Reg{RAX, RCX, RDX, RBX, RSP, RBP, RSI, RDI}, writes: []Reg{RSP}, mem: Write, latency: LatencyMicrocode},
+	{reads: []Reg{RSP}, writes: []Reg{RSP}, flagsRead: CF | PF | AF | ZF | SF | DF | OF, mem: Write, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read}, flagsRead: CF, flagsWritten: CF | OF, latency: LatencyMicrocode},
+	{reads: []Reg{ECX}, writes: []Reg{EAX, EDX}, latency: LatencyMicrocode},
+	{access: [4]Access{Write}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+	{writes: []Reg{EAX, EDX}, latency: LatencyMicrocode},
+	{writes: []Reg{EAX, EDX, ECX}, latency: LatencyMicrocode},
+	{access: [4]Access{Read}, reads: []Reg{RSP}, writes: []Reg{RSP}, mem: Read, latency: LatencyBranch},
+	{reads: []Reg{RSP}, writes: []Reg{RSP}, mem: Read, latency: LatencyBranch},
+	{access: [4]Access{ReadWrite, Read}, flagsWritten: CF | OF, latency: LatencyFast},
+	{flagsWritten: CF | PF | AF | ZF | SF | DF | OF, latency: LatencyMicrocode},
+	{reads: []Reg{AH}, flagsWritten: CF | PF | AF | ZF | SF, latency: LatencyFast},
+	{reads: []Reg{RDI, AL}, writes: []Reg{RDI}, flagsRead: DF, flagsWritten: CF | PF | AF | ZF | SF | OF, mem: Read, latency: LatencyMicrocode},
+	{reads: []Reg{RDI, EAX}, writes: []Reg{RDI}, flagsRead: DF, flagsWritten: CF | PF | AF | ZF | SF | OF, mem: Read, latency: LatencyMicrocode},
+	{reads: []Reg{RDI, RAX}, writes: []Reg{RDI}, flagsRead: DF, flagsWritten: CF | PF | AF | ZF | SF | OF, mem: Read, latency: LatencyMicrocode},
+	{reads: []Reg{RDI, AX}, writes: []Reg{RDI}, flagsRead: DF, flagsWritten: CF | PF | AF | ZF | SF | OF, mem: Read, latency: LatencyMicrocode},
+	{access: [4]Access{Write}, flagsRead: CF | ZF, latency: LatencyFast},
+	{access: [4]Access{Write}, flagsRead: CF, latency: LatencyFast},
+	{access: [4]Access{Write}, flagsRead: ZF, latency: LatencyFast},
+	{access: [4]Access{Write}, flagsRead: ZF | SF | OF, latency: LatencyFast},
+	{access: [4]Access{Write}, flagsRead: SF | OF, latency: LatencyFast},
+	{access: [4]Access{Write}, flagsRead: OF, latency: LatencyFast},
+	{access: [4]Access{Write}, flagsRead: PF, latency: LatencyFast},
+	{access: [4]Access{Write}, flagsRead: SF, latency: LatencyFast},
+	{access: [4]Access{ReadWrite, Read, Read}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{Write, Read}, latency: LatencyDivide},
+	{reads: []Reg{RDI, AL}, writes: []Reg{RDI}, flagsRead: DF, mem: Write, latency: LatencyMicrocode},
+	{reads: []Reg{RDI, EAX}, writes: []Reg{RDI}, flagsRead: DF, mem: Write, latency: LatencyMicrocode},
+	{reads: []Reg{RDI, RAX}, writes: []Reg{RDI}, flagsRead: DF, mem: Write, latency: LatencyMicrocode},
+	{reads: []Reg{RDI, AX}, writes: []Reg{RDI}, flagsRead: DF, mem: Write, latency: LatencyMicrocode},
+	{writes: []Reg{RCX, R11}, flagsRead: CF | PF | AF | ZF | SF | DF | OF, flagsWritten: CF | PF | AF | ZF | SF | DF | OF, latency: LatencyMicrocode},
+	{writes: []Reg{RSP}, flagsWritten: CF | PF | AF | ZF | SF | DF | OF, latency: LatencyMicrocode},
+	{reads: []Reg{ECX, EDX}, writes: []Reg{RSP}, latency: LatencyMicrocode},
+	{reads: []Reg{RCX, R11}, writes: []Reg{RSP}, flagsWritten: CF | PF | AF | ZF | SF | DF | OF, latency: LatencyMicrocode},
+	{access: [4]Access{Write, Read, Read, Read}, latency: LatencyShuffle},
+	{access: [4]Access{Write, Read, Read, Read}, latency: LatencyFast},
+	{access: [4]Access{Write, Read, Read, Read}, latency: LatencyMedium},
+	{access: [4]Access{Write, Read, Read}, latency: LatencyDivide},
+	{access: [4]Access{Read}, flagsWritten: ZF, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite, Read, Read, Read}, latency: LatencyMedium},
+	{access: [4]Access{ReadWrite, Read, ReadWrite}, latency: LatencyGather},
+	{access: [4]Access{ReadWrite, Read}, writesMask: true, latency: LatencyGather},
+	{access: [4]Access{0}, writesMask: true, latency: LatencyGather},
+	{access: [4]Access{Write, Read}, writesMask: true, latency: LatencyGather},
+	{access: [4]Access{ReadWrite, Read, Read, Read}, latency: LatencyFast},
+	{writes: []Reg{Y0, Y1, Y2, Y3, Y4, Y5, Y6, Y7, Y8, Y9, Y10, Y11, Y12, Y13, Y14, Y15}, latency: LatencyMicrocode},
+	{writes: []Reg{Y0, Y1, Y2, Y3, Y4, Y5, Y6, Y7, Y8, Y9, Y10, Y11, Y12, Y13, Y14, Y15}, latency: LatencyFast},
+	{reads: []Reg{ECX, EAX, EDX}, latency: LatencyMicrocode},
+	{access: [4]Access{Read}, writes: []Reg{EAX}, latency: LatencyMicrocode},
+	{access: [4]Access{ReadWrite, ReadWrite}, flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMedium},
+	{access: [4]Access{ReadWrite, ReadWrite}, latency: LatencyMedium},
+	{reads: []Reg{RBX, AL}, writes: []Reg{AL}, mem: Read, latency: LatencyFast},
+	{access: [4]Access{Read}, reads: []Reg{EAX, EDX}, latency: LatencyMicrocode},
+	{access: [4]Access{Write}, reads: []Reg{EAX, EDX}, latency: LatencyMicrocode},
+	{flagsWritten: CF | PF | AF | ZF | SF | OF, latency: LatencyMicrocode},
+}
+
+var rowMeta = [...]uint16{
+	0, 1, 2, 3, 4, 0, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5,
+	5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 6, 6, 6, 6,
+	6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+	6, 6, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 8, 9, 6, 6,
+	6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+	6, 6, 6, 6, 10, 10, 11, 11, 11, 11, 12, 13, 13, 14, 14, 14,
+	14, 15, 15, 15, 15, 15, 15, 16, 16, 17, 17, 17, 17, 17, 17, 18,
+	18, 18, 19, 19, 19, 19, 19, 19, 20, 20, 20, 20, 20, 20, 20, 20,
+	20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 10, 10, 21, 21, 21, 21,
+	21, 21, 22, 23, 24, 25, 26, 27, 28, 28, 29, 30, 30, 30, 31, 31,
+	31, 31, 31, 31, 30, 30, 30, 32, 32, 32, 33, 33, 33, 34, 34, 34,
+	34, 34, 34, 33, 33, 33, 32, 32, 32, 35, 35, 35, 36, 36, 36, 37,
+	37, 37, 35, 35, 35, 36, 36, 36, 37, 37, 37, 38, 38, 38, 38, 38,
+	38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38,
+	38, 39, 39, 40, 40, 39, 40, 39, 40, 41, 42, 43, 44, 44, 45, 46,
+	47, 47, 48, 49, 7, 7, 7, 7, 7, 7, 8, 8, 8, 8, 8, 8,
+	7, 8, 8, 8, 8, 8, 7, 7, 7, 7, 7, 7, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 8, 50, 51, 52, 52, 53, 53, 53, 53, 53, 53,
+	53, 54, 55, 56, 57, 57, 58, 58, 58, 58, 39, 39, 28, 59, 59, 59,
+	60, 61, 62, 7, 7, 63, 63, 7, 62, 64, 65, 62, 66, 66, 66, 66,
+	66, 66, 66, 66, 63, 63, 63, 62, 47, 47, 63, 63, 63, 62, 62, 61,
+	67, 58, 58, 68, 68, 58, 69, 58, 58, 68, 68, 58, 69, 70, 70, 63,
+	63, 63, 63, 63, 63, 68, 68, 68, 68, 71, 71, 71, 63, 63, 67, 72,
+	72, 72, 72, 72, 72, 72, 72, 63, 63, 63, 63, 71, 71, 71, 71, 73,
+	74, 74, 73, 73, 73, 73, 7, 7, 63, 63, 7, 62, 28, 75, 67, 76,
+	77, 27, 77, 77, 78, 79, 79, 80, 61, 81, 79, 61, 80, 69, 82, 82,
+	82, 82, 82, 82, 82, 7, 7, 63, 63, 7, 62, 7, 7, 63, 63, 7,
+	62, 62, 63, 62, 47, 47, 63, 62, 62, 83, 62, 84, 85, 74, 74, 27,
+	27, 80, 78, 78, 7, 7, 28, 7, 7, 86, 54, 55, 56, 57, 57, 87,
+	88, 89, 90, 17, 13, 13, 17, 13, 13, 17, 13, 13, 91, 91, 91, 91,
+	91, 91, 53, 53, 53, 53, 53, 53, 53, 92, 92, 93, 92, 94, 94, 86,
+	28, 95, 16, 16, 96, 96, 96, 97, 97, 97, 97, 98, 98, 98, 98, 98,
+	98, 98, 98, 97, 97, 97, 97, 99, 100, 100, 100, 100, 101, 102, 102, 102,
+	102, 103, 103, 103, 103, 103, 103, 103, 103, 102, 102, 102, 102, 104, 104, 104,
+	104, 104, 104, 104, 100, 100, 100, 100, 105, 105, 105, 105, 106, 106, 106, 106,
+	107, 107, 107, 107, 105, 105, 105, 105, 106, 106, 106, 106, 108, 107, 107, 107,
+	107, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 109, 109, 109, 38, 38, 38, 38, 109, 60, 60, 60, 60, 60,
+	60, 60, 60, 38, 38, 38, 38, 60, 60, 60, 109, 109, 109, 109, 109, 109,
+	109, 109, 111, 12, 12, 12, 112, 112, 112, 112, 112, 110, 74, 91, 91, 113,
+	113, 113, 114, 114, 114, 91, 91, 28, 91, 91, 91, 74, 74, 91, 91, 91,
+	74, 74, 74, 74, 74, 74, 74, 74, 74, 115, 116, 117, 118, 119, 120, 120,
+	121, 122, 12, 12, 12, 91, 91, 91, 74, 123, 123, 123, 124, 124, 7, 7,
+	7, 7, 28, 7, 7, 7, 7, 125, 110, 110, 110, 126, 126, 126, 126, 110,
+	110, 91, 91, 91, 126, 126, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 126, 126, 126, 126, 126, 126, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 127, 110, 110, 110, 110, 110, 128, 128, 127, 127,
+	128, 128, 11, 110, 110, 11, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 129, 129, 11, 110, 127, 127,
+	129, 11, 110, 129, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 39, 87, 88, 89, 90, 90, 7, 7, 7,
+	7, 130, 131, 132, 133, 133, 133, 133, 133, 134, 134, 67, 18, 18, 18, 18,
+	18, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+	6, 6, 6, 6, 6, 6, 6, 11, 11, 16, 16, 16, 16, 16, 16, 135,
+	135, 135, 110, 110, 110, 110, 110, 110, 128, 128, 128, 128, 128, 128, 128, 11,
+	11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 93,
+	93, 11, 11, 11, 11, 28, 11, 11, 11, 11, 14, 14, 39, 11, 11, 11,
+	11, 11, 11, 11, 136, 137, 11, 11, 11, 11, 11, 11, 11, 138, 139, 9,
+	9, 9, 9, 60, 60, 60, 60, 60, 60, 7, 7, 7, 7, 7, 7, 8,
+	7, 7, 7, 7, 7, 7, 93, 93, 93, 93, 93, 7, 7, 7, 7, 11,
+	11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 110,
+	110, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 7, 7, 7,
+	7, 7, 7, 7, 7, 7, 7, 7, 7, 140, 140, 140, 140, 140, 140, 140,
+	140, 140, 140, 140, 140, 140, 140, 140, 141, 141, 123, 123, 123, 96, 96, 96,
+	11, 11, 134, 134, 134, 134, 134, 7, 7, 128, 128, 60, 60, 60, 60, 11,
+	11, 11, 11, 11, 11, 11, 11, 11, 11, 128, 11, 11, 11, 11, 11, 11,
+	11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 128, 11,
+	11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11,
+	11, 11, 11, 11, 11, 11, 11, 47, 128, 128, 128, 128, 128, 128, 128, 128,
+	128, 128, 128, 128, 128, 128, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142,
+	142, 142, 142, 142, 142, 143, 143, 144, 144, 144, 11, 11, 145, 145, 145, 145,
+	145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 8, 7, 145, 145, 145,
+	145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 27, 27, 27, 27,
+	146, 146, 147, 147, 147, 148, 149, 150, 151, 152, 152, 152, 152, 152, 152, 152,
+	152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152,
+	152, 152, 152, 152, 152, 152, 152, 109, 109, 9, 9, 39, 39, 153, 8, 7,
+	154, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+	109, 109, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5,
+	5, 5, 5, 5, 5, 5, 5, 5, 155, 156, 157, 158, 159, 159, 160, 160,
+	160, 160, 159, 159, 161, 161, 162, 162, 163, 163, 163, 163, 162, 162, 161, 161,
+	164, 164, 165, 165, 166, 166, 164, 164, 165, 165, 166, 166, 28, 27, 6, 6,
+	6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 167, 167, 167,
+	167, 167, 167, 109, 109, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+	6, 6, 6, 6, 167, 167, 167, 167, 167, 167, 109, 109, 93, 93, 27, 27,
+	27, 27, 27, 27, 27, 168, 168, 58, 58, 25, 26, 28, 27, 169, 170, 171,
+	172, 27, 27, 27, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+	6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 7, 7, 7, 7, 28, 173,
+	174, 175, 175, 176, 176, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38,
+	38, 38, 38, 123, 123, 123, 47, 47, 28, 28, 128, 128, 128, 128, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	8, 9, 177, 177, 177, 177, 177, 177, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 178, 178, 178, 178, 178, 178, 178, 178, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 127, 127, 179, 179, 179, 179, 179, 179, 179, 179,
+	179, 179, 179, 179, 179, 179, 47, 47, 47, 47, 127, 127, 127, 127, 127, 127,
+	8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 9, 9, 9, 9,
+	9, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 9, 9, 8, 8, 8, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 8, 9, 9, 9, 9, 9, 9, 179, 179, 179, 180,
+	180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 180, 179, 179, 179,
+	181, 181, 8, 8, 127, 127, 127, 127, 127, 127, 60, 60, 60, 60, 60, 60,
+	60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 182, 182, 182, 182, 182, 182,
+	182, 182, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 9, 9, 9, 9, 9, 9, 9, 9, 183, 183,
+	184, 184, 184, 183, 183, 184, 184, 184, 185, 185, 185, 185, 185, 185, 185, 185,
+	183, 183, 184, 184, 184, 183, 183, 184, 184, 184, 8, 8, 8, 8, 8, 8,
+	9, 9, 9, 9, 9, 9, 9, 9, 179, 179, 179, 179, 179, 179, 179, 179,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 177, 177, 177, 177, 177,
+	177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 110, 110, 74, 124, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 127, 127, 127,
+	127, 127, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 60, 60,
+	127, 60, 60, 127, 127, 60, 60, 127, 60, 60, 110, 109, 109, 110, 110, 109,
+	109, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 109, 109, 110, 110, 109, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 110, 110, 109, 109, 110, 110, 109, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 179,
+	179, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 110, 110, 110, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 60, 60, 60, 60, 60,
+	60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 177, 177, 177, 177, 177, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 178, 178, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 178, 178, 178, 178, 127, 127, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 110, 110, 110, 110, 110,
+	110, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127,
+	127, 179, 179, 179, 179, 178, 178, 178, 178, 178, 178, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	136, 137, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 138, 139, 178, 178, 178, 178, 178, 178, 178, 178,
+	178, 178, 178, 178, 178, 178, 178, 178, 178, 178, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 91, 91, 91, 91, 91, 91, 39, 39, 39, 39,
+	39, 39, 39, 39, 39, 39, 39, 39, 177, 177, 60, 60, 60, 60, 60, 60,
+	93, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93,
+	93, 93, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60,
+	60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60,
+	60, 60, 60, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93, 93,
+	93, 93, 93, 93, 93, 60, 60, 60, 127, 127, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60,
+	183, 183, 184, 184, 184, 183, 183, 184, 184, 184, 183, 183, 184, 184, 184, 183,
+	183, 184, 184, 184, 9, 9, 9, 9, 9, 9, 8, 9, 9, 9, 9, 9,
+	9, 177, 177, 177, 177, 177, 177, 177, 177, 177, 8, 8, 8, 8, 8, 8,
+	39, 39, 39, 39, 39, 39, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	110, 110, 110, 110, 110, 110, 127, 127, 127, 127, 127, 127, 110, 110, 110, 110,
+	110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 110, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 110, 110, 110, 127, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127, 127,
+	127, 127, 127, 127, 127, 127, 127, 127, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 60, 60, 60, 9, 9, 9, 9,
+	9, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 9, 9, 9,
+	9, 9, 186, 186, 186, 186, 186, 186, 186, 186, 186, 186, 186, 186, 177, 177,
+	177, 177, 177, 177, 93, 93, 93, 93, 93, 93, 93, 93, 93, 177, 177, 177,
+	177, 177, 177, 177, 177, 177, 93, 93, 93, 93, 93, 93, 93, 93, 93, 177,
+	177, 177, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60,
+	60, 60, 60, 60, 60, 60, 60, 60, 60, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 60, 60, 60, 60, 60, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 60, 60, 60, 60, 60, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 187, 187, 187, 187, 187, 187, 47, 47, 109, 109, 109, 109,
+	109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 109, 109, 109, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60,
+	60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60,
+	60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 109, 109, 109, 109,
+	109, 109, 109, 109, 179, 179, 179, 179, 179, 179, 179, 179, 8, 8, 8, 8,
+	8, 8, 9, 9, 8, 8, 9, 9, 8, 8, 9, 9, 9, 9, 9, 9,
+	9, 179, 179, 9, 9, 9, 9, 9, 9, 179, 179, 9, 9, 9, 9, 179,
+	179, 8, 8, 8, 8, 8, 8, 9, 9, 8, 8, 9, 9, 8, 8, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 186, 186, 186, 186, 186, 186, 185, 185,
+	185, 185, 185, 185, 185, 185, 186, 186, 186, 186, 186, 186, 177, 177, 177, 177,
+	177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 177, 168, 168,
+	168, 168, 168, 168, 168, 168, 168, 168, 180, 180, 180, 180, 27, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 47, 47, 47, 47, 47,
+	47, 47, 47, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60,
+	60, 60, 60, 60, 60, 60, 60, 109, 109, 109, 109, 109, 109, 109, 109, 109,
+	109, 188, 189, 28, 74, 74, 74, 74, 190, 191, 192, 192, 192, 192, 192, 191,
+	191, 193, 193, 193, 193, 193, 193, 193, 193, 193, 193, 193, 193, 193, 193, 193,
+	193, 28, 146, 194, 194, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+	6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 11, 11, 195, 195, 195,
+	195, 196, 196, 196, 196, 196, 196, 196, 196, 190, 197,
+}
@@ -0,0 +1,343 @@
+package x86enc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MismatchError is the error Encode and its relatives return when none of the
+// forms of a mnemonic encodes the operands. It lists each form with the reason
+// it was rejected.
+type MismatchError struct {
+	// Op is the mnemonic.
+	Op string
+	// Operands are the operands as given, including prefixes and rounding
+	// controls.
+	Operands []Operand
+	// Mode is the processor mode of the target, 32 or 64.
+	Mode int
+	// Candidates are the forms of the mnemonic in table order, excluding
+	// pseudo-instructions. Forms the table repeats which are rejected alike
+	// appear once.
+	Candidates []Candidate
+}
+
+// Candidate is a form which an encoder considered and rejected.
+type Candidate struct {
+	// Form is the form as named for EncodeForm.
+	Form string
+	// Reason is why the form was rejected.
+	Reason Reason
+	// Operand is the index in Operands of the operand which the form does
+	// not accept, or -1 if the reason concerns no one operand.
+	Operand int
+	// Kind is the form's argument kind which Operand does not satisfy, like
+	// "imm8", or empty if Operand is -1.
+	Kind string
+	// Err is the error from encoding with the form, for ReasonEncoding, or
+	// naming the missing features, for ReasonFeature.
+	Err error
+}
+
+// Reason is a reason an encoder rejected a form.
+type Reason uint8
+
+// Reasons for rejecting forms.
+const (
+	// ReasonMode forms are invalid in the target's mode, like AAA in 64-bit
+	// mode.
+	ReasonMode Reason = 1 + iota
+	// ReasonCount forms take a different number of operands.
+	ReasonCount
+	// ReasonKind forms take a different kind of operand, like an immediate
+	// instead of a register, or do not allow a mask.
+	ReasonKind
+	// ReasonWidth forms take a register or memory operand of the right kind
+	// but a different width.
+	ReasonWidth
+	// ReasonRange forms have an immediate or relative operand too narrow
+	// for its value.
+	ReasonRange
+	// ReasonRounding forms do not allow the rounding control.
+	ReasonRounding
+	// ReasonEncoding forms match the operands but cannot encode them, like
+	// AH with a REX prefix.
+	ReasonEncoding
+	// ReasonFeature forms require features the target lacks.
+	ReasonFeature
+)
+
+var reasonStrings = [...]string{
+	ReasonMode:     "invalid in mode",
+	ReasonCount:    "operand count",
+	ReasonKind:     "operand kind",
+	ReasonWidth:    "width mismatch",
+	ReasonRange:    "out of range",
+	ReasonRounding: "rounding not allowed",
+	ReasonEncoding: "cannot encode",
+	ReasonFeature:  "feature unavailable",
+}
+
+func (r Reason) String() string {
+	if 0 < r && int(r) < len(reasonStrings) {
+		return reasonStrings[r]
+	}
+	return fmt.Sprintf("Reason(%d)", uint8(r))
+}
+
+// Error returns the best explanation of the mismatch, preferring a form which
+// only the target's features prevent, then a form which matched but could not
+// encode the operands, followed by the candidates which take as many operands
+// as were given, one per line, or all of them if none does.
+func (e *MismatchError) Error() string {
+	var s string
+	for _, want := range []Reason{ReasonFeature, ReasonEncoding} {
+		if c := e.find(want); c != nil {
+			s = c.Err.Error()
+			break
+		}
+	}
+	if s == "" {
+		ops, _ := splitPrefixes(e.Operands)
+		ops, _ = splitRounding(ops)
+		kinds := make([]string, len(ops))
+		for i, o := range ops {
+			kinds[i] = operandKind(o)
+		}
+		s = fmt.Sprintf("x86enc: no form of %s matches operands %v", e.Op, e.Operands)
+		if len(kinds) > 0 {
+			s += " (" + strings.Join(kinds, ", ") + ")"
+		}
+	}
+	all := true
+	for _, c := range e.Candidates {
+		if c.Reason != ReasonCount {
+			all = false
+			break
+		}
+	}
+	for i := range e.Candidates {
+		c := &e.Candidates[i]
+		if all || c.Reason != ReasonCount {
+			s += "\n\t" + e.explain(c)
+		}
+	}
+	return s
+}
+
+// find returns the first candidate rejected for a reason, or nil if there is
+// none.
+func (e *MismatchError) find(r Reason) *Candidate {
+	for i := range e.Candidates {
+		if e.Candidates[i].Reason == r {
+			return &e.Candidates[i]
+		}
+	}
+	return nil
+}
+
+// explain describes why a candidate was rejected.
+func (e *MismatchError) explain(c *Candidate) string {
+	var why string
+	switch c.Reason {
+	case ReasonMode:
+		why = fmt.Sprintf("invalid in %d-bit mode", e.Mode)
+	case ReasonCount:
+		why = "takes a different number of operands"
+	case ReasonKind:
+		why = fmt.Sprintf("%v is not %s", e.Operands[c.Operand], c.Kind)
+	case ReasonWidth:
+		why = fmt.Sprintf("%v has the wrong width for %s", e.Operands[c.Operand], c.Kind)
+	case ReasonRange:
+		why = fmt.Sprintf("%v is out of range for %s", e.Operands[c.Operand], c.Kind)
+	case ReasonRounding:
+		why = "does not allow the rounding control"
+	case ReasonEncoding, ReasonFeature:
+		why = strings.TrimPrefix(c.Err.Error(), "x86enc: ")
+		if c.Reason == ReasonFeature {
+			// The error already names the form.
+			return why
+		}
+	default:
+		why = c.Reason.String()
+	}
+	return c.Form + ": " + why
+}
+
+// mismatch explains why none of the rows encodes the operands for the target,
+// as shortest finds them.
+func (e *Encoder) mismatch(rows []instruction, args []Operand, end int, mode int) *MismatchError {
+	ops, ps := splitPrefixes(args)
+	ops, rc := splitRounding(ops)
+	err := &MismatchError{Op: rows[0].op.String(), Operands: args, Mode: mode}
+	pseudo := tagPseudo
+	if mode == 64 {
+		pseudo |= tagPseudo64
+	}
+	for i := range rows {
+		r := &rows[i]
+		if r.flags&pseudo != 0 {
+			continue
+		}
+		c := Candidate{Form: r.String(), Operand: -1}
+		c.Reason, c.Operand = reject(r, ops, rc, mode)
+		if c.Reason == 0 {
+			b, rerr := encode(r, ops, rc, mode)
+			if rerr == nil && end != 0 && pcrel(ops) {
+				moved := shiftRel(ops, end-len(b))
+				c.Reason, c.Operand = reject(r, moved, rc, mode)
+				if c.Reason == 0 {
+					_, rerr = encode(r, moved, rc, mode)
+				}
+			}
+			switch {
+			case c.Reason != 0:
+			case rerr != nil:
+				c.Reason, c.Err = ReasonEncoding, rerr
+			default:
+				c.Err = e.supports(r)
+				if c.Err == nil {
+					// The row encodes the operands.
+					continue
+				}
+				c.Reason = ReasonFeature
+			}
+		}
+		if c.Operand >= 0 {
+			c.Kind = kindTable[r.args[c.Operand]].name
+			c.Operand += len(ps)
+		}
+		if !err.has(c) {
+			err.Candidates = append(err.Candidates, c)
+		}
+	}
+	return err
+}
+
+// has returns whether the error already has a candidate like c. The table
+// repeats some forms with different encodings, which are rejected alike.
+func (e *MismatchError) has(c Candidate) bool {
+	for _, d := range e.Candidates {
+		if d.Form == c.Form && d.Reason == c.Reason && d.Operand == c.Operand && d.Kind == c.Kind && (d.Err == nil) == (c.Err == nil) && (d.Err == nil || d.Err.Error() == c.Err.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// reject returns why a row does not match the operands and the index of the
+// operand it does not accept, or 0 and -1 if the row matches.
+func reject(r *instruction, ops []Operand, rc Rounding, mode int) (Reason, int) {
+	if !r.valid(mode) {
+		return ReasonMode, -1
+	}
+	if n := r.nargs(); n != len(ops) {
+		if n != len(ops)+1 || !kindTable[r.args[n-1]].optional {
+			return ReasonCount, -1
+		}
+	}
+	size := r.immSize()
+	for i, o := range ops {
+		k := &kindTable[r.args[i]]
+		if !k.accepts(o, size) {
+			return k.mismatch(o), i
+		}
+		if k.rm && r.flags&(tagRegOnly|tagMemOnly) != 0 {
+			if _, mem := o.(Mem); mem == (r.flags&tagRegOnly != 0) {
+				return ReasonKind, i
+			}
+		}
+	}
+	if !r.rounds(rc) {
+		return ReasonRounding, -1
+	}
+	return 0, -1
+}
+
+// mismatch returns why the kind does not accept an operand.
+func (k *kind) mismatch(o Operand) Reason {
+	if k.fixed != nil {
+		if f, ok := k.fixed.(Reg); ok {
+			if r, ok := o.(Reg); ok && r.Class() == f.Class() && r.Size() != f.Size() {
+				return ReasonWidth
+			}
+		}
+		return ReasonKind
+	}
+	switch o := o.(type) {
+	case Masked:
+		if _, ok := o.Op.(Masked); ok || k.mask == maskNone || o.Zero && k.mask != maskZero {
+			return ReasonKind
+		}
+		return k.mismatch(o.Op)
+	case Imm:
+		if k.imm != 0 {
+			return ReasonRange
+		}
+	case Rel:
+		if k.rel != 0 {
+			return ReasonRange
+		}
+	case FarPtr:
+		if k.far != 0 {
+			return ReasonRange
+		}
+	case Mem:
+		switch {
+		case k.mem == memNone, o.Broadcast && k.bcst == 0:
+			return ReasonKind
+		case o.Broadcast && o.Size != 0 && o.Size != k.bcst, !o.Broadcast && o.Size != 0 && k.memSize != 0 && o.Size != k.memSize:
+			return ReasonWidth
+		}
+	case Reg:
+		if c := o.Class(); c == k.reg || vector(c) && vector(k.reg) {
+			return ReasonWidth
+		}
+	}
+	return ReasonKind
+}
+
+// vector returns whether a register class holds vectors of SSE or AVX.
+func vector(c RegClass) bool {
+	return c == ClassXMM || c == ClassYMM || c == ClassZMM
+}
+
+// operandKind names the kind of an operand in the notation of x86.csv, like
+// r32 or m64.
+func operandKind(o Operand) string {
+	switch o := o.(type) {
+	case Masked:
+		if o.Zero {
+			return operandKind(o.Op) + "{k}{z}"
+		}
+		return operandKind(o.Op) + "{k}"
+	case Mem:
+		s := "m"
+		if o.Size != 0 {
+			s += fmt.Sprint(8 * o.Size)
+		}
+		if o.Broadcast {
+			s += "bcst"
+		}
+		return s
+	case Imm:
+		return "imm"
+	case Rel:
+		return "rel"
+	case FarPtr:
+		return "ptr16:32"
+	case Reg:
+		switch o.Class() {
+		case ClassGPR:
+			return fmt.Sprintf("r%d", 8*o.Size())
+		case ClassMMX:
+			return "mm"
+		case ClassSeg:
+			return "Sreg"
+		case ClassX87:
+			return "ST(i)"
+		}
+		return strings.ToLower(o.Class().String())
+	}
+	return fmt.Sprintf("%T", o)
+}
@@ -0,0 +1,72 @@
+package x86enc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMismatchError(t *testing.T) {
+	mode32 := &Encoder{Features: AllFeatures, Mode: 32}
+	cases := []struct {
+		e       *Encoder
+		op      string
+		args    []Operand
+		form    string
+		reason  Reason
+		operand int
+		msg     string
+	}{
+		{anyCPU, "ADD", []Operand{RAX, EBX}, "ADD r/m64, r64", ReasonWidth, 1, "no form of ADD matches operands [RAX EBX] (r64, r32)"},
+		{anyCPU, "ADD", []Operand{RAX, EBX}, "ADD EAX, imm32", ReasonWidth, 0, "ADD EAX, imm32: RAX has the wrong width for EAX"},
+		{anyCPU, "ADD", []Operand{RAX, X0}, "ADD r/m64, imm8", ReasonKind, 1, "ADD r/m64, imm8: X0 is not imm8"},
+		{anyCPU, "ADD", []Operand{RAX}, "ADD r/m64, r64", ReasonCount, -1, "ADD r/m64, r64: takes a different number of operands"},
+		{anyCPU, "ADD", []Operand{LOCK, Mem{Base: RAX, Size: 8}, Imm(1 << 40)}, "ADD r/m64, imm32", ReasonRange, 2, "$1099511627776 is out of range for imm32"},
+		{anyCPU, "JRCXZ", []Operand{Rel(200)}, "JRCXZ rel8", ReasonRange, 0, ".+200 is out of range for rel8"},
+		{anyCPU, "AAA", nil, "AAA", ReasonMode, -1, "AAA: invalid in 64-bit mode"},
+		{mode32, "SWAPGS", nil, "SWAPGS", ReasonMode, -1, "SWAPGS: invalid in 32-bit mode"},
+		{anyCPU, "VADDPS", []Operand{Y0, Y1, Z2}, "VADDPS ymm1, ymm2, ymm3/m256", ReasonWidth, 2, "Z2 has the wrong width"},
+		{anyCPU, "VPADDD", []Operand{Z1, Z2, Z3, RoundNearest}, "VPADDD zmm1{k1}{z}, zmm2, zmm3/m512/m32bcst", ReasonRounding, -1, "does not allow the rounding control"},
+		{anyCPU, "MOV", []Operand{AH, SIL}, "MOV r/m8, r8", ReasonEncoding, -1, "x86enc: MOV cannot be encoded with AH"},
+		{&Encoder{}, "ADDPD", []Operand{X0, X1}, "ADDPD xmm1, xmm2/m128", ReasonFeature, -1, "requires SSE2"},
+	}
+	for _, c := range cases {
+		_, err := c.e.Encode(c.op, c.args...)
+		var m *MismatchError
+		if !errors.As(err, &m) {
+			t.Errorf("%s %v: wrong error %v", c.op, c.args, err)
+			continue
+		}
+		if m.Op != c.op || len(m.Operands) != len(c.args) {
+			t.Errorf("%s %v: error describes %s %v", c.op, c.args, m.Op, m.Operands)
+		}
+		var got *Candidate
+		for i := range m.Candidates {
+			if m.Candidates[i].Form == c.form {
+				got = &m.Candidates[i]
+				break
+			}
+		}
+		switch {
+		case got == nil:
+			t.Errorf("%s %v: no candidate %s", c.op, c.args, c.form)
+		case got.Reason != c.reason || got.Operand != c.operand:
+			t.Errorf("%s %v: %s rejected for %v at operand %d, want %v at %d", c.op, c.args, c.form, got.Reason, got.Operand, c.reason, c.operand)
+		}
+		if !strings.Contains(err.Error(), c.msg) {
+			t.Errorf("%s %v: error %q does not contain %q", c.op, c.args, err, c.msg)
+		}
+	}
+	// Forms the table repeats are listed once.
+	_, err := anyCPU.Encode("MOV", Mem{Base: R8, Size: 1}, AH)
+	for _, line := range []string{"MOV AL, moffs8: (R8) is not AL", "MOV moffs8, AL: (R8) is not moffs8"} {
+		if n := strings.Count(err.Error(), line); n != 1 {
+			t.Errorf("MOV [r8], AH: %q appears %d times in %q", line, n, err)
+		}
+	}
+	// Misused prefixes are not mismatches.
+	var m *MismatchError
+	if _, err := Encode("ADD", LOCK, RAX, RBX); err == nil || errors.As(err, &m) {
+		t.Errorf("LOCK ADD with registers: wrong error %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/zephyrtronium/ikitai/internal/x86enc/internal/spec"
+)
+
+// A method is a Builder method for one signature of operand types of a
+// mnemonic. Arguments which accept both registers and memory give separate
+// signatures for each, so several rows can share a method, like ADD r/m64, r64
+// and ADD r64, r/m64 with two registers.
+type method struct {
+	name   string
+	params []string // parameter declarations
+	args   []string // operand expressions, including fixed operands
+	rows   []int
+	forms  []string // descriptions of the rows for the doc comment
+}
+
+// choice is one way to give an argument of a row.
+type choice struct {
+	// name is the argument's part of the method name, empty if the argument
+	// is omitted.
+	name string
+	// typ is the type of the parameter, or empty for a fixed operand, which
+	// expr gives.
+	typ, expr string
+}
+
+// methods adds the Builder methods for a row at index n in the table.
+// Rows which are pseudo-instructions in every mode have none.
+func (g *gen) methods(n int, f []string, ks []spec.Kind, tags []string) {
+	for _, t := range tags {
+		if t == "pseudo" || t == "pseudo64" {
+			return
+		}
+	}
+	if f[6] != "V" && f[7] != "V" {
+		return
+	}
+	sigs := [][]choice{nil}
+	for _, k := range ks {
+		cs := choices(&k)
+		var next [][]choice
+		for _, s := range sigs {
+			for _, c := range cs {
+				next = append(next, append(s[:len(s):len(s)], c))
+			}
+		}
+		sigs = next
+	}
+	desc := formDesc(f)
+	for _, s := range sigs {
+		name := f[0]
+		var params, args []string
+		for i, c := range s {
+			if c.name == "" {
+				continue
+			}
+			name += "_" + c.name
+			if c.typ == "" {
+				args = append(args, c.expr)
+				continue
+			}
+			p := fmt.Sprintf("op%d", i+1)
+			params = append(params, p+" "+c.typ)
+			args = append(args, p)
+		}
+		m := g.midx[name]
+		if m == nil {
+			m = &method{name: name, params: params, args: args}
+			g.midx[name] = m
+			g.ms = append(g.ms, m)
+		}
+		if len(m.rows) == 0 || m.rows[len(m.rows)-1] != n {
+			m.rows = append(m.rows, n)
+			m.forms = append(m.forms, desc)
+		}
+	}
+}
+
+// choices returns the ways to give an argument of a kind.
+func choices(k *spec.Kind) []choice {
+	if k.Fixed != "" {
+		if k.Optional {
+			return []choice{{}}
+		}
+		return []choice{{name: identChars(k.Name), expr: k.Fixed}}
+	}
+	var cs []choice
+	if k.Reg != spec.ClassNone {
+		cs = append(cs, regChoice(k))
+	}
+	switch k.Mem {
+	case spec.MemModRM:
+		cs = append(cs, choice{name: "m" + bits(k.MemSize), typ: "Mem"})
+	case spec.MemOffset:
+		cs = append(cs, choice{name: "moffs" + bits(k.MemSize), typ: "Mem"})
+	case spec.MemVSIBX:
+		cs = append(cs, choice{name: "vm" + bits(k.MemSize) + "x", typ: "Mem"})
+	case spec.MemVSIBY:
+		cs = append(cs, choice{name: "vm" + bits(k.MemSize) + "y", typ: "Mem"})
+	case spec.MemVSIBZ:
+		cs = append(cs, choice{name: "vm" + bits(k.MemSize) + "z", typ: "Mem"})
+	}
+	switch {
+	case k.Imm != 0:
+		cs = append(cs, choice{name: "imm" + bits(k.Imm), typ: "Imm"})
+	case k.Rel != 0:
+		cs = append(cs, choice{name: "rel" + bits(k.Rel), typ: "Rel"})
+	case k.Far != 0:
+		cs = append(cs, choice{name: "ptr16_" + bits(k.Far-2), typ: "FarPtr"})
+	}
+	return cs
+}
+
+// regChoice returns the choice of a register for a kind.
+func regChoice(k *spec.Kind) choice {
+	switch k.Reg {
+	case spec.ClassGPR:
+		return choice{name: "r" + bits(k.RegSize), typ: "GPR" + bits(k.RegSize)}
+	case spec.ClassXMM:
+		return choice{name: "xmm", typ: "XMM"}
+	case spec.ClassYMM:
+		return choice{name: "ymm", typ: "YMM"}
+	case spec.ClassZMM:
+		return choice{name: "zmm", typ: "ZMM"}
+	case spec.ClassK:
+		return choice{name: "k", typ: "K"}
+	case spec.ClassMMX:
+		return choice{name: "mm", typ: "MMX"}
+	case spec.ClassSeg:
+		return choice{name: "sreg", typ: "Seg"}
+	case spec.ClassX87:
+		return choice{name: "st", typ: "ST"}
+	case spec.ClassCR:
+		return choice{name: "cr", typ: "CR"}
+	case spec.ClassDR:
+		return choice{name: "dr", typ: "DR"}
+	case spec.ClassTR:
+		return choice{name: "tr", typ: "TR"}
+	}
+	panic(fmt.Sprintf("mkenc: no register type for %q", k.Name))
+}
+
+// bits formats a width in bytes as bits, or as nothing if it is 0.
+func bits(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(8 * n)
+}
+
+// identChars returns the letters and digits of s.
+func identChars(s string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+			return c
+		}
+		return -1
+	}, s)
+}
+
+// formDesc describes a row by its form, encoding, features, and modes.
+func formDesc(f []string) string {
+	s := fmt.Sprintf("%s, encoded as %s", form(f), strings.Join(strings.Fields(f[5]), " "))
+	if feat := featureText(f[8]); feat != "" {
+		s += ", requiring " + feat
+	}
+	switch {
+	case f[6] != "V":
+		s += ", in 64-bit mode only"
+	case f[7] != "V":
+		s += ", in 32-bit mode only"
+	}
+	return s
+}
+
+// featureText returns the features in the feature column of a row as
+// prose, like "AVX512F and AVX512VL".
+func featureText(f string) string {
+	if strings.HasPrefix(f, "Both ") && strings.HasSuffix(f, " flags") {
+		return strings.TrimSuffix(strings.TrimPrefix(f, "Both "), " flags")
+	}
+	return f
+}
+
+// forms returns the source of forms.go, which holds the Builder methods.
+func (g *gen) forms() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, hdr, g.source)
+	b.WriteString("\npackage x86enc\n")
+	for _, m := range g.ms {
+		b.WriteByte('\n')
+		if len(m.forms) == 1 {
+			fmt.Fprintf(&b, "// %s appends %s.\n", m.name, m.forms[0])
+		} else {
+			fmt.Fprintf(&b, "// %s appends the shortest encoding among the forms:\n//\n", m.name)
+			for _, s := range m.forms {
+				fmt.Fprintf(&b, "//   - %s\n", s)
+			}
+		}
+		fmt.Fprintf(&b, "func (b *Builder) %s(%s) {\n", m.name, strings.Join(m.params, ", "))
+		rows := make([]string, len(m.rows))
+		for i, r := range m.rows {
+			rows[i] = fmt.Sprint(r)
+		}
+		fmt.Fprintf(&b, "\tb.form([]Operand{%s}, %s)\n}\n", strings.Join(m.args, ", "), strings.Join(rows, ", "))
+	}
+	return b.Bytes()
+}
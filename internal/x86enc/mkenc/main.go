@@ -1,90 +1,193 @@
 // mkenc generates the encoding table for x86enc.
+//
+// mkenc reads x86.csv, along with the AVX-512 rows in evex.csv which mkevex
+// derives from Intel XED, and interprets each row's operands and encoding, so
+// that x86enc never parses them at run time. It writes the table to table.go,
+// the index of mnemonics to idcs.go, and the methods of Builder to forms.go.
+// It also attaches to each row the metadata of the first matching line of
+// meta.csv, which gives the row's implicit registers, flags, memory accesses,
+// and latency class, and writes them to meta.go. Rows which it cannot
+// interpret, or which no line of meta.csv describes, are listed on standard
+// error along with lines which describe no row, and mkenc exits with a
+// non-zero status if there are any.
+//
+// x86.csv is a copy of the file in golang.org/x/arch, pinned by its SHA-256 so
+// that the table only changes when the copy is deliberately updated. To
+// update it, replace x86.csv and run mkenc with -sum set to the new file's
+// checksum, then change x86csvSum to match. The output depends only on the
+// contents of the input files, and TestGenerated checks that the committed
+// table is what mkenc generates from them.
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
+	"go/format"
 	"io"
-	"net/http"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
-	"golang.org/x/xerrors"
+	"github.com/zephyrtronium/ikitai/internal/x86enc/internal/spec"
 )
 
-const hdr = `package x86enc
+// x86csvSum is the SHA-256 of x86.csv as of golang.org/x/arch
+// v0.0.0-20190815191158-8a70ba74b3a1.
+const x86csvSum = "fcbb870b925e607abec992bde67d2c9fab251d70c3151d1541981b5fe9a182b1"
 
-// Code generated by go generate; DO NOT EDIT
-`
+const hdr = `// Code generated by mkenc; DO NOT EDIT.
+//
+// Sources:
+%s`
 
 const insns = `
 type instruction struct {
-	op, a1, a2, a3, a4, encoding, valid32, valid64, feature, tags string
+	op      mnemonic
+	args    [4]uint8 // indices into kindTable, 0 if absent
+	roles   [4]role
+	enc     encoding
+	feature uint8 // index into featureTable
+	flags   rowFlag
 }
-
-var table = [...]instruction{
-`
-
-const idcs = `
-var tableIdcs = [...][2]int16{
 `
 
 func main() {
-	resp, err := http.Get("https://raw.githubusercontent.com/golang/arch/master/x86/x86.csv")
+	src := flag.String("csv", "x86.csv", "path of x86.csv")
+	sum := flag.String("sum", x86csvSum, "expected SHA-256 of x86.csv")
+	evex := flag.String("evex", "evex.csv", "path of the AVX-512 rows generated by mkevex")
+	meta := flag.String("meta", "meta.csv", "path of the instruction metadata")
+	flag.Parse()
+	out, bad, err := generate(*src, *sum, *evex, *meta)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "mkenc:", err)
+		os.Exit(1)
+	}
+	if len(bad) > 0 {
+		fmt.Fprintf(os.Stderr, "mkenc: %d rows or lines of meta.csv not understood:\n", len(bad))
+		for _, s := range bad {
+			fmt.Fprintln(os.Stderr, s)
+		}
+		os.Exit(1)
+	}
+	for _, o := range out {
+		if err := ioutil.WriteFile(o.name, o.src, 0644); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// An output is a generated file.
+type output struct {
+	name string
+	src  []byte
+}
+
+// generate returns the sources of table.go, idcs.go, forms.go, and meta.go
+// for the rows of the files at src, which must have the SHA-256 sum, and
+// evex, described by the metadata at meta. bad lists the rows which could not
+// be interpreted and the lines of meta which describe no row.
+func generate(src, sum, evex, meta string) (out []output, bad []string, err error) {
+	rows, got, err := read(src)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
+	}
+	if got != sum {
+		return nil, nil, fmt.Errorf("%s has SHA-256 %s, want %s", src, got, sum)
+	}
+	for _, r := range missingRows {
+		rows = append(rows, append([]string(nil), r...))
 	}
-	defer resp.Body.Close()
-	out, err := os.Create("table.go")
+	more, esum, err := read(evex)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
 	}
-	defer out.Close()
-	iut, err := os.Create("idcs.go")
+	lines, msum, err := readMeta(meta)
 	if err != nil {
-		panic(err)
-	}
-	defer iut.Close()
-	must(out.WriteString(hdr + insns))
-	must(iut.WriteString(hdr + idcs))
-	defer iut.WriteString("}\n")
-	defer out.WriteString("}\n")
-	r := csv.NewReader(resp.Body)
-	r.Comment = '#'
-	r.FieldsPerRecord = 6
-	cur := ""
-	l := [][]string{}
-	n := 0
-	defer func() {
-		args := make([]string, 0, len(argtypes))
-		for arg := range argtypes {
-			args = append(args, arg)
+		return nil, nil, err
+	}
+	// The AVX-512 rows follow the rows of x86.csv so that shorter VEX forms
+	// of the same mnemonics come first.
+	rows = append(rows, more...)
+	for _, r := range rows {
+		if aliases[r[0]] && r[1] == "" {
+			r[9] = strings.TrimPrefix(strings.Replace(r[9], "pseudo", "", 1), ",")
 		}
-		sort.Strings(args)
-		for _, arg := range args {
-			fmt.Fprintln(os.Stderr, arg)
+	}
+	// Some mnemonics, like the conditional jumps, are split into several
+	// groups of rows. Gather each mnemonic's rows, keeping their order.
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+	g := newGen()
+	g.lines = lines
+	for len(rows) > 0 {
+		n := 1
+		for n < len(rows) && rows[n][0] == rows[0][0] {
+			n++
 		}
-	}()
+		g.emit(rows[:n])
+		rows = rows[n:]
+	}
+	// Name the inputs by their base names so that the output does not
+	// depend on where mkenc runs.
+	g.source = fmt.Sprintf("//\t%s sha256:%s\n//\t%s sha256:%s\n//\t%s sha256:%s\n", filepath.Base(src), got, filepath.Base(evex), esum, filepath.Base(meta), msum)
+	for _, l := range lines {
+		if !l.used {
+			g.bad = append(g.bad, fmt.Sprintf("\t%s:%d: %s describes no row", filepath.Base(meta), l.line, l.pattern))
+		}
+	}
+	gens := []struct {
+		name string
+		src  func() []byte
+	}{
+		{"table.go", g.table},
+		{"idcs.go", g.index},
+		{"forms.go", g.forms},
+		{"meta.go", g.metas},
+	}
+	for _, f := range gens {
+		b, err := format.Source(f.src())
+		if err != nil {
+			return nil, nil, fmt.Errorf("formatting %s: %w", f.name, err)
+		}
+		out = append(out, output{f.name, b})
+	}
+	return out, g.bad, nil
+}
+
+// read reads the rows of a file in the format of x86.csv and returns them
+// with the file's SHA-256. Each row is split into the mnemonic, four
+// arguments, and the remaining columns.
+func read(src string) ([][]string, string, error) {
+	b, err := ioutil.ReadFile(src)
+	if err != nil {
+		return nil, "", err
+	}
+	h := sha256.Sum256(b)
+	r := csv.NewReader(bytes.NewReader(b))
+	r.Comment = '#'
+	r.FieldsPerRecord = 6
+	var rows [][]string
 	for {
 		insn, err := r.Read()
 		switch {
 		default:
 			op, a1, a2, a3, a4 := getop(insn[0])
-			if op != cur {
-				n = emit(out, iut, l, n)
-				l = l[:0]
-				cur = op
-			}
-			l = append(l, append([]string{op, a1, a2, a3, a4}, insn[1:]...))
-		case xerrors.Is(err, io.EOF):
-			emit(out, iut, l, n)
-			return
-		case xerrors.Is(err, csv.ErrFieldCount):
+			rows = append(rows, append([]string{op, a1, a2, a3, a4}, insn[1:]...))
+			continue
+		case errors.Is(err, io.EOF):
+		case errors.Is(err, csv.ErrFieldCount):
 			continue
 		case err != nil:
-			panic(err)
+			return nil, "", fmt.Errorf("reading %s: %w", src, err)
 		}
+		return rows, hex.EncodeToString(h[:]), nil
 	}
 }
 
@@ -121,45 +224,552 @@ func getop(m string) (op, a1, a2, a3, a4 string) {
 	return
 }
 
-func emit(out, iut io.Writer, l [][]string, n int) int {
+// aliases are mnemonics which x86.csv lists as pseudo-instructions, since
+// they are aliases of XCHG EAX, EAX, but which are the usual names of their
+// encodings and are what x86asm decodes them as.
+var aliases = map[string]bool{
+	"NOP":   true,
+	"PAUSE": true,
+}
+
+// missingRows are rows which x86.csv lacks, split as read splits them. It
+// lists the 256-bit form of VPCMPEQQ twice instead of having one of VPCMPEQB.
+var missingRows = [][]string{
+	{"VPCMPEQB", "ymm1", "ymm2", "ymm3/m256", "", "VEX.NDS.256.66.0F.WIG 74 /r", "V", "V", "AVX2", ""},
+}
+
+// encodingFixes corrects typos in the encoding column.
+var encodingFixes = strings.NewReplacer(
+	// VMOVHPD is missing a dot.
+	"VEX128.", "VEX.128.",
+	// VPADDUSB is missing a dot.
+	".660F.", ".66.0F.",
+	// VCVTPS2PH has a dot instead of a space before its opcode.
+	".W0.1D ", ".W0 1D ",
+	// The 256-bit forms of VFMSUB231PS, VFNMADD231PS, and VFNMSUB231PS are
+	// missing the W of W0.
+	".0F38.0 ", ".0F38.W0 ",
+)
+
+// gen accumulates the generated table.
+type gen struct {
+	rows     bytes.Buffer
+	n        int
+	names    []string
+	starts   []int
+	kinds    []spec.Kind
+	kidx     map[string]int
+	features []string
+	fidx     map[string]int
+	ms       []*method
+	midx     map[string]*method
+	// lines are the lines of meta.csv. infos are the distinct metadata of
+	// rows, indexed by iidx, and rowInfo holds each row's index into infos.
+	lines   []*metaLine
+	infos   []string
+	iidx    map[string]int
+	rowInfo []int
+	bad     []string
+	// source describes the input files for the generated headers.
+	source string
+}
+
+func newGen() *gen {
+	return &gen{
+		kinds:    []spec.Kind{{}},
+		kidx:     map[string]int{"": 0},
+		features: []string{""},
+		fidx:     map[string]int{"": 0},
+		midx:     make(map[string]*method),
+		iidx:     make(map[string]int),
+	}
+}
+
+// emit interprets and adds the rows of one mnemonic. Each row is the
+// mnemonic, four arguments, and the remaining columns of x86.csv.
+func (g *gen) emit(l [][]string) {
 	if len(l) == 0 {
-		return n
+		return
 	}
-	o := make([]string, len(l))
-	args := make([]string, 0, 4)
-	var b strings.Builder
 	ok := false
-	for i, insn := range l {
-		if !ok && !strings.Contains(insn[len(insn)-1], "pseudo") {
+	for _, r := range l {
+		if !strings.Contains(r[9], "pseudo") {
 			// We want to remove pseudo-opcodes, but only if all insns with
 			// this op are pseudo. Perhaps more precisely, we still want 1-byte
 			// NOP.
 			ok = true
 		}
-		b.WriteString("instruction{")
-		for _, s := range insn {
-			b.WriteString(fmt.Sprintf("%q,", s))
+	}
+	if !ok {
+		return
+	}
+	start := g.n
+	for _, r := range l {
+		s, err := g.row(len(g.names), r)
+		if err != nil {
+			g.bad = append(g.bad, fmt.Sprintf("\t%s %s [%s]: %v", r[0], strings.Join(r[1:5], ","), r[5], err))
+			continue
+		}
+		g.rows.WriteString(s)
+		g.n++
+	}
+	if g.n > start {
+		g.names = append(g.names, l[0][0])
+		g.starts = append(g.starts, start)
+	}
+}
+
+// row interprets a row and returns its entry in the table.
+func (g *gen) row(op int, f []string) (string, error) {
+	enc := encodingFixes.Replace(f[5])
+	if strings.HasPrefix(f[0], "SET") && f[2] == "" {
+		// SETcc is listed as /r, but it has no register operand, and the
+		// manual gives ModRM.reg as 0.
+		enc = strings.Replace(enc, "/r", "/0", 1)
+	}
+	e, err := spec.ParseEncoding(enc)
+	if err != nil {
+		return "", err
+	}
+	var ks []spec.Kind
+	var args [4]int
+	for i, a := range f[1:5] {
+		if a == "" {
+			break
+		}
+		k, err := spec.ParseKind(a)
+		if err != nil {
+			return "", err
+		}
+		ks = append(ks, k)
+		args[i] = g.kind(k)
+	}
+	rs, err := spec.Roles(ks, &e)
+	if err != nil {
+		return "", err
+	}
+	disp8, err := spec.Disp8(ks, &e)
+	if err != nil {
+		return "", err
+	}
+	var tags []string
+	if f[9] != "" {
+		tags = strings.Split(f[9], ",")
+	}
+	for _, t := range tags {
+		// evex.csv tags forms which scale 8-bit displacements by their
+		// element size rather than the size of their memory operands, like
+		// VCOMPRESSPD, with that size in bits.
+		if !strings.HasPrefix(t, "esize") {
+			continue
+		}
+		n, err := strconv.Atoi(t[len("esize"):])
+		if err != nil || n < 8 || n&(n-1) != 0 {
+			return "", fmt.Errorf("bad tag %q", t)
 		}
-		b.WriteByte('}')
-		o[i] = b.String()
-		b.Reset()
-		args = append(args, insn[1:5]...)
+		for disp8 = 0; 8<<uint(disp8) < n; disp8++ {
+		}
+	}
+	el, err := encodingLit(&e, disp8)
+	if err != nil {
+		return "", err
+	}
+	feat, err := g.feature(f[8])
+	if err != nil {
+		return "", err
 	}
-	if ok {
-		for _, arg := range args {
-			argtypes[arg] = true
+	var flags []string
+	for i, v := range f[6:8] {
+		switch v {
+		case "V":
+			flags = append(flags, []string{"valid32", "valid64"}[i])
+		case "I", "N.E.", "N.S.":
+		default:
+			return "", fmt.Errorf("unknown validity %q", v)
+		}
+	}
+	for _, t := range tags {
+		if strings.HasPrefix(t, "esize") {
+			continue
+		}
+		n, ok := tagNames[t]
+		if !ok {
+			return "", fmt.Errorf("unknown tag %q", t)
 		}
-		must(fmt.Fprintf(out, "\t%s,\n", strings.Join(o, ", ")))
-		must(fmt.Fprintf(iut, "\t[2]int16{%d, %d},\n", n, n+len(l)))
-		return n + len(l)
+		if n != "" {
+			flags = append(flags, n)
+		}
+	}
+	if len(flags) == 0 {
+		flags = append(flags, "0")
 	}
-	return n
+	info, err := g.info(f, ks)
+	if err != nil {
+		return "", err
+	}
+	g.rowInfo = append(g.rowInfo, info)
+	g.methods(g.n, f, ks, tags)
+	var b strings.Builder
+	fmt.Fprintf(&b, "\t{%d, [4]uint8{", op)
+	for i, a := range args[:len(ks)] {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprint(&b, a)
+	}
+	b.WriteString("}, [4]role{")
+	for i, r := range rs {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(roleNames[r])
+	}
+	fmt.Fprintf(&b, "}, %s, %d, %s}, // %s\n", el, feat, strings.Join(flags, " | "), form(f))
+	return b.String(), nil
 }
 
-var argtypes = map[string]bool{}
+// form returns the form of a row as it appears in x86.csv, like
+// "ADD r/m64, imm8".
+func form(f []string) string {
+	return strings.TrimSpace(f[0] + " " + strings.Join(nonEmpty(f[1:5]), ", "))
+}
 
-func must(_ int, err error) {
-	if err != nil {
-		panic(err)
+// nonEmpty trims empty strings from the end of s.
+func nonEmpty(s []string) []string {
+	for len(s) > 0 && s[len(s)-1] == "" {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+var tagNames = map[string]string{
+	"pseudo":        "tagPseudo",
+	"pseudo64":      "tagPseudo64",
+	"operand16":     "tagOperand16",
+	"operand32":     "tagOperand32",
+	"operand64":     "tagOperand64",
+	"modrm_regonly": "tagRegOnly",
+	"modrm_memonly": "tagMemOnly",
+	"address16":     "tagAddress16",
+	"address32":     "tagAddress32",
+	"address64":     "tagAddress64",
+	// keepop tells x86asm to decode PAUSE as itself.
+	"keepop": "",
+}
+
+// kind interns an argument kind.
+func (g *gen) kind(k spec.Kind) int {
+	if i, ok := g.kidx[k.Name]; ok {
+		return i
+	}
+	g.kidx[k.Name] = len(g.kinds)
+	g.kinds = append(g.kinds, k)
+	return len(g.kinds) - 1
+}
+
+// feature interns the features named by a row.
+func (g *gen) feature(f string) (int, error) {
+	if i, ok := g.fidx[f]; ok {
+		return i, nil
+	}
+	if _, err := featureLit(f); err != nil {
+		return 0, err
+	}
+	g.fidx[f] = len(g.features)
+	g.features = append(g.features, f)
+	return len(g.features) - 1, nil
+}
+
+// featureLit returns a featureSet literal for the feature column of a row,
+// which is empty, a single feature like "SSE4_1", "Both AVX512F and AVX512VL
+// flags", or "HLE or RTM". Feature constants are named like FeatureSSE41.
+func featureLit(f string) (string, error) {
+	if f == "" {
+		return "{}", nil
+	}
+	field, sep := "all", " and "
+	if strings.HasPrefix(f, "Both ") && strings.HasSuffix(f, " flags") {
+		f = strings.TrimSuffix(strings.TrimPrefix(f, "Both "), " flags")
+	} else if strings.Contains(f, " or ") {
+		field, sep = "any", " or "
+	}
+	names := strings.Split(f, sep)
+	for i, n := range names {
+		n = strings.ReplaceAll(n, "_", "")
+		if n == "" || strings.TrimLeft(n, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") != "" {
+			return "", fmt.Errorf("bad feature %q", f)
+		}
+		names[i] = "Feature" + n
+	}
+	return fmt.Sprintf("{%s: %s}", field, strings.Join(names, " | ")), nil
+}
+
+// table returns the source of table.go.
+func (g *gen) table() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, hdr, g.source)
+	b.WriteString("\npackage x86enc\n" + insns)
+	b.WriteString("\nvar kindTable = [...]kind{\n")
+	for _, k := range g.kinds {
+		fmt.Fprintf(&b, "\t%s,\n", kindLit(&k))
+	}
+	b.WriteString("}\n\nvar featureTable = [...]featureSet{\n")
+	for _, f := range g.features {
+		l, _ := featureLit(f)
+		if f == "" {
+			fmt.Fprintf(&b, "\t%s,\n", l)
+		} else {
+			fmt.Fprintf(&b, "\t%s, // %s\n", l, f)
+		}
+	}
+	b.WriteString("}\n\nvar table = [...]instruction{\n")
+	b.Write(g.rows.Bytes())
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// index returns the source of idcs.go, which holds the names of mnemonics,
+// their ranges of rows, and a perfect hash from names to mnemonics.
+func (g *gen) index() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, hdr, g.source)
+	b.WriteString("\npackage x86enc\n")
+	fmt.Fprintf(&b, "\nconst mnemonicText = %q\n", strings.Join(g.names, ""))
+	offs := make([]int, 0, len(g.names)+1)
+	n := 0
+	for _, s := range g.names {
+		offs = append(offs, n)
+		n += len(s)
+	}
+	seeds, slots := perfectHash(g.names)
+	writeInts(&b, "mnemonicIdcs", append(offs, n))
+	writeInts(&b, "tableIdcs", append(g.starts, g.n))
+	writeInts(&b, "mnemonicSeeds", seeds)
+	writeInts(&b, "mnemonicSlots", slots)
+	return b.Bytes()
+}
+
+// writeInts writes a uint16 array.
+func writeInts(b *bytes.Buffer, name string, v []int) {
+	fmt.Fprintf(b, "\nvar %s = [...]uint16{", name)
+	for i, x := range v {
+		if i%16 == 0 {
+			b.WriteString("\n\t")
+		} else {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(b, "%d,", x)
+	}
+	b.WriteString("\n}\n")
+}
+
+// perfectHash builds a two-level perfect hash of names. Each name hashes to a
+// bucket in seeds, and the seed stored there selects the name's slot in
+// slots, which holds the index of the name plus one. Buckets are filled
+// largest first, trying seeds until every name in the bucket lands in a
+// distinct empty slot. This must agree with lookup in x86enc.
+func perfectHash(names []string) (seeds, slots []int) {
+	m := 1
+	for m < len(names) {
+		m <<= 1
+	}
+	seeds = make([]int, m/4)
+	slots = make([]int, m*2)
+	buckets := make([][]int, len(seeds))
+	for i, s := range names {
+		h := hash(s, 0) & uint32(len(seeds)-1)
+		buckets[h] = append(buckets[h], i)
+	}
+	order := make([]int, len(buckets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return len(buckets[order[i]]) > len(buckets[order[j]]) })
+	for _, bi := range order {
+		bucket := buckets[bi]
+		if len(bucket) == 0 {
+			break
+		}
+		d := 0
+		for ; !place(names, bucket, d, slots); d++ {
+			if d == 1<<16-1 {
+				panic("mkenc: no perfect hash")
+			}
+		}
+		seeds[bi] = d
+	}
+	return seeds, slots
+}
+
+// place tries to put the names in a bucket into distinct empty slots using a
+// seed, and fills the slots if it succeeds.
+func place(names []string, bucket []int, seed int, slots []int) bool {
+	hs := make([]uint32, len(bucket))
+	for k, i := range bucket {
+		h := hash(names[i], uint32(seed)+1) & uint32(len(slots)-1)
+		if slots[h] != 0 {
+			return false
+		}
+		for _, u := range hs[:k] {
+			if u == h {
+				return false
+			}
+		}
+		hs[k] = h
+	}
+	for k, i := range bucket {
+		slots[hs[k]] = i + 1
+	}
+	return true
+}
+
+// hash is FNV-1a with a seed.
+func hash(s string, seed uint32) uint32 {
+	h := 2166136261 ^ seed*16777619
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
+
+var roleNames = [...]string{
+	spec.RoleNone:   "roleNone",
+	spec.RoleReg:    "roleReg",
+	spec.RoleRM:     "roleRM",
+	spec.RoleVVVV:   "roleVVVV",
+	spec.RoleOpcode: "roleOpcode",
+	spec.RoleImm:    "roleImm",
+	spec.RoleIs4:    "roleIs4",
+}
+
+var classNames = [...]string{
+	spec.ClassNone: "ClassNone",
+	spec.ClassGPR:  "ClassGPR",
+	spec.ClassXMM:  "ClassXMM",
+	spec.ClassYMM:  "ClassYMM",
+	spec.ClassMMX:  "ClassMMX",
+	spec.ClassSeg:  "ClassSeg",
+	spec.ClassX87:  "ClassX87",
+	spec.ClassCR:   "ClassCR",
+	spec.ClassDR:   "ClassDR",
+	spec.ClassTR:   "ClassTR",
+	spec.ClassZMM:  "ClassZMM",
+	spec.ClassK:    "ClassK",
+}
+
+var memNames = [...]string{
+	spec.MemNone:   "memNone",
+	spec.MemModRM:  "memModRM",
+	spec.MemOffset: "memOffset",
+	spec.MemVSIBX:  "memVSIBX",
+	spec.MemVSIBY:  "memVSIBY",
+	spec.MemVSIBZ:  "memVSIBZ",
+}
+
+var maskNames = [...]string{
+	spec.MaskNone:  "maskNone",
+	spec.MaskMerge: "maskMerge",
+	spec.MaskZero:  "maskZero",
+}
+
+var roundNames = [...]string{
+	spec.RoundNone: "roundNone",
+	spec.RoundSAE:  "roundSAE",
+	spec.RoundER:   "roundER",
+}
+
+// fields formats the non-zero fields of a composite literal.
+type fields []string
+
+func (f *fields) add(cond bool, name string, v interface{}) {
+	if cond {
+		*f = append(*f, fmt.Sprintf("%s: %v", name, v))
+	}
+}
+
+func (f fields) lit(typ string) string {
+	return typ + "{" + strings.Join(f, ", ") + "}"
+}
+
+func kindLit(k *spec.Kind) string {
+	var f fields
+	f.add(k.Name != "", "name", fmt.Sprintf("%q", k.Name))
+	f.add(k.Fixed != "", "fixed", k.Fixed)
+	f.add(k.Optional, "optional", true)
+	f.add(k.Reg != spec.ClassNone, "reg", classNames[k.Reg])
+	f.add(k.RegSize != 0, "regSize", k.RegSize)
+	f.add(k.Mem != spec.MemNone, "mem", memNames[k.Mem])
+	f.add(k.MemSize != 0, "memSize", k.MemSize)
+	f.add(k.Bcst != 0, "bcst", k.Bcst)
+	f.add(k.RM, "rm", true)
+	f.add(k.Mask != spec.MaskNone, "mask", maskNames[k.Mask])
+	f.add(k.Round != spec.RoundNone, "round", roundNames[k.Round])
+	f.add(k.Imm != 0, "imm", k.Imm)
+	f.add(k.Unsigned, "unsigned", true)
+	f.add(k.Rel != 0, "rel", k.Rel)
+	f.add(k.Far != 0, "far", k.Far)
+	return f.lit("")
+}
+
+// encodingLit formats an encoding with the scale of its compressed
+// displacements, or returns an error if it does not fit the representation in
+// x86enc.
+func encodingLit(e *spec.Encoding, disp8 int) (string, error) {
+	if len(e.Prefix) > 2 || len(e.Opcode) > 3 || len(e.Imm) > 2 {
+		return "", fmt.Errorf("encoding too long")
+	}
+	var f fields
+	f.add(len(e.Prefix) > 0, "prefix", bytesLit(2, e.Prefix))
+	f.add(len(e.Prefix) > 0, "nprefix", len(e.Prefix))
+	f.add(true, "opcode", bytesLit(3, e.Opcode))
+	f.add(true, "nopcode", len(e.Opcode))
+	switch e.ModRM {
+	case spec.ModRMNone:
+		f.add(true, "modrm", "modrmNone")
+	case spec.ModRMReg:
+		f.add(true, "modrm", "modrmR")
+	default:
+		f.add(true, "modrm", e.ModRM)
+	}
+	if len(e.Imm) > 0 {
+		s := make([]string, len(e.Imm))
+		for i, n := range e.Imm {
+			s[i] = fmt.Sprint(n)
+		}
+		f.add(true, "imm", "[2]uint8{"+strings.Join(s, ", ")+"}")
+	}
+	var flags []string
+	flag := func(cond bool, name string) {
+		if cond {
+			flags = append(flags, name)
+		}
+	}
+	flag(e.REX, "encREX")
+	flag(e.REXW, "encREXW")
+	flag(e.Plus, "encPlus")
+	flag(e.Is4, "encIs4")
+	if v := e.VEX; v != nil {
+		flag(!v.EVEX, "encVEX")
+		flag(v.EVEX, "encEVEX")
+		flag(v.L&1 != 0, "vexL")
+		flag(v.LIG, "vexLIG")
+		flag(v.L&2 != 0, "evexL2")
+		flag(v.W != 0, "vexW")
+		flag(v.WIG, "vexWIG")
+		flag(v.V, "vexV")
+		f.add(v.PP != 0, "pp", v.PP)
+		f.add(true, "mmmmm", v.Map)
+		f.add(disp8 != 0, "disp8", disp8)
+	}
+	f.add(len(flags) > 0, "flags", strings.Join(flags, " | "))
+	return f.lit("encoding"), nil
+}
+
+func bytesLit(n int, b []byte) string {
+	s := make([]string, len(b))
+	for i, c := range b {
+		s[i] = fmt.Sprintf("0x%02x", c)
 	}
+	return fmt.Sprintf("[%d]byte{%s}", n, strings.Join(s, ", "))
 }
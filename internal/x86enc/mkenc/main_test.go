@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+// TestGenerated checks that the committed table is what mkenc generates from
+// the committed inputs, so that it cannot drift from them.
+func TestGenerated(t *testing.T) {
+	out, bad, err := generate("../x86.csv", x86csvSum, "../evex.csv", "../meta.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range bad {
+		t.Errorf("row not understood: %s", s)
+	}
+	for _, o := range out {
+		got, err := ioutil.ReadFile("../" + o.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, o.src) {
+			t.Errorf("%s differs from mkenc's output; run go generate", o.name)
+		}
+	}
+}
+
+func TestGenerateChecksum(t *testing.T) {
+	const sum = "0000000000000000000000000000000000000000000000000000000000000000"
+	if _, _, err := generate("../x86.csv", sum, "../evex.csv", "../meta.csv"); err == nil {
+		t.Error("generated from x86.csv with the wrong checksum")
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/zephyrtronium/ikitai/internal/x86enc/internal/spec"
+)
+
+// A metaLine is a line of meta.csv, which describes the rows matching its
+// pattern.
+type metaLine struct {
+	pattern string
+	// access is the access of each operand, the last applying to any
+	// further operands.
+	access        []string
+	reads, writes []string
+	// mask is whether the line writes the opmask of the operands.
+	mask         bool
+	flagsRead    string
+	flagsWritten string
+	mem          string
+	latency      string
+	line         int
+	used         bool
+}
+
+// readMeta reads the lines of meta.csv and returns them with the file's
+// SHA-256.
+func readMeta(src string) ([]*metaLine, string, error) {
+	b, err := ioutil.ReadFile(src)
+	if err != nil {
+		return nil, "", err
+	}
+	h := sha256.Sum256(b)
+	r := csv.NewReader(bytes.NewReader(b))
+	r.Comment = '#'
+	r.FieldsPerRecord = 8
+	var lines []*metaLine
+	for {
+		f, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return lines, hex.EncodeToString(h[:]), nil
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("reading %s: %w", src, err)
+		}
+		n, _ := r.FieldPos(0)
+		l, err := parseMeta(f)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s:%d: %v", src, n, err)
+		}
+		l.line = n
+		lines = append(lines, l)
+	}
+}
+
+// parseMeta interprets the fields of a line of meta.csv.
+func parseMeta(f []string) (*metaLine, error) {
+	l := &metaLine{
+		pattern:      f[0],
+		access:       strings.Fields(f[1]),
+		reads:        strings.Fields(f[2]),
+		flagsRead:    f[4],
+		flagsWritten: f[5],
+		mem:          f[6],
+		latency:      f[7],
+	}
+	for _, s := range strings.Fields(f[3]) {
+		if s == "{k1}" {
+			l.mask = true
+			continue
+		}
+		l.writes = append(l.writes, s)
+	}
+	if l.pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	for _, a := range l.access {
+		if a != "-" && accessNames[a] == "" {
+			return nil, fmt.Errorf("bad access %q", a)
+		}
+	}
+	for _, s := range append(l.reads[:len(l.reads):len(l.reads)], l.writes...) {
+		if !implicitRegs[s] {
+			return nil, fmt.Errorf("bad register %q", s)
+		}
+	}
+	for _, s := range []string{l.flagsRead, l.flagsWritten} {
+		if strings.Trim(s, "ODSZAPC") != "" {
+			return nil, fmt.Errorf("bad flags %q", s)
+		}
+	}
+	if l.mem != "" && accessNames[l.mem] == "" {
+		return nil, fmt.Errorf("bad memory access %q", l.mem)
+	}
+	if latencyNames[l.latency] == "" {
+		return nil, fmt.Errorf("bad latency class %q", l.latency)
+	}
+	return l, nil
+}
+
+// matches returns whether a line describes a row. A pattern containing a
+// space is a form, one ending in * is a prefix of mnemonics, and others are
+// mnemonics.
+func (l *metaLine) matches(f []string) bool {
+	switch {
+	case strings.Contains(l.pattern, " "):
+		return l.pattern == form(f)
+	case strings.HasSuffix(l.pattern, "*"):
+		return strings.HasPrefix(f[0], strings.TrimSuffix(l.pattern, "*"))
+	}
+	return l.pattern == f[0]
+}
+
+var accessNames = map[string]string{
+	"r":  "Read",
+	"w":  "Write",
+	"rw": "ReadWrite",
+}
+
+var latencyNames = map[string]string{
+	"fast":      "LatencyFast",
+	"shuffle":   "LatencyShuffle",
+	"medium":    "LatencyMedium",
+	"divide":    "LatencyDivide",
+	"branch":    "LatencyBranch",
+	"gather":    "LatencyGather",
+	"microcode": "LatencyMicrocode",
+}
+
+// flagNames are the constants of the flags in meta.csv, in the order of
+// their bits.
+var flagNames = []struct {
+	c    byte
+	name string
+}{
+	{'C', "CF"},
+	{'P', "PF"},
+	{'A', "AF"},
+	{'Z', "ZF"},
+	{'S', "SF"},
+	{'D', "DF"},
+	{'O', "OF"},
+}
+
+// implicitRegs are the registers meta.csv may name, which are also the names
+// of their constants in x86enc.
+var implicitRegs = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, s := range strings.Fields(`AL CL DL BL AH CH DH BH AX CX DX BX SP BP
+		SI DI EAX ECX EDX EBX ESP EBP ESI EDI RAX RCX RDX RBX RSP RBP RSI RDI
+		R8 R9 R10 R11 R12 R13 R14 R15`) {
+		m[s] = true
+	}
+	for i := 0; i < 32; i++ {
+		m[fmt.Sprint("X", i)] = true
+		m[fmt.Sprint("Y", i)] = true
+		m[fmt.Sprint("Z", i)] = true
+	}
+	for i := 0; i < 8; i++ {
+		m[fmt.Sprint("ST", i)] = true
+	}
+	return m
+}()
+
+// info finds the first line of meta.csv which describes a row and returns
+// the index of the row's metadata.
+func (g *gen) info(f []string, ks []spec.Kind) (int, error) {
+	var l *metaLine
+	for _, m := range g.lines {
+		if m.matches(f) {
+			l = m
+			break
+		}
+	}
+	if l == nil {
+		return 0, fmt.Errorf("no line of meta.csv describes %s", form(f))
+	}
+	l.used = true
+	var v fields
+	if len(ks) > 0 {
+		acc := make([]string, len(ks))
+		for i, k := range ks {
+			switch {
+			case k.Imm != 0 || k.Rel != 0 || k.Far != 0:
+				acc[i] = "Read"
+			case len(l.access) == 0:
+				return 0, fmt.Errorf("%s gives no access for the operands of %s", l.pattern, form(f))
+			case i < len(l.access):
+				acc[i] = accessLit(l.access[i])
+			default:
+				acc[i] = accessLit(l.access[len(l.access)-1])
+			}
+		}
+		v.add(true, "access", "[4]Access{"+strings.Join(acc, ", ")+"}")
+	}
+	v.add(len(l.reads) > 0, "reads", "[]Reg{"+strings.Join(l.reads, ", ")+"}")
+	v.add(len(l.writes) > 0, "writes", "[]Reg{"+strings.Join(l.writes, ", ")+"}")
+	v.add(l.mask, "writesMask", true)
+	v.add(l.flagsRead != "", "flagsRead", flagsLit(l.flagsRead))
+	v.add(l.flagsWritten != "", "flagsWritten", flagsLit(l.flagsWritten))
+	v.add(l.mem != "", "mem", accessNames[l.mem])
+	v.add(true, "latency", latencyNames[l.latency])
+	s := v.lit("")
+	if i, ok := g.iidx[s]; ok {
+		return i, nil
+	}
+	g.iidx[s] = len(g.infos)
+	g.infos = append(g.infos, s)
+	return len(g.infos) - 1, nil
+}
+
+// accessLit returns the Access constant for an access in meta.csv.
+func accessLit(a string) string {
+	if a == "-" {
+		return "0"
+	}
+	return accessNames[a]
+}
+
+// flagsLit returns a Flags expression for the letters of flags in meta.csv.
+func flagsLit(s string) string {
+	var names []string
+	for _, f := range flagNames {
+		if strings.IndexByte(s, f.c) >= 0 {
+			names = append(names, f.name)
+		}
+	}
+	return strings.Join(names, " | ")
+}
+
+const metaDecl = `
+type meta struct {
+	access        [4]Access
+	reads, writes []Reg
+	// writesMask is whether the instruction writes the opmask of its
+	// operands, as gathers and scatters clear it.
+	writesMask   bool
+	flagsRead    Flags
+	flagsWritten Flags
+	mem          Access // implicit memory access
+	latency      Latency
+}
+`
+
+// metas returns the source of meta.go, which holds the distinct metadata of
+// rows and the index of each row's metadata.
+func (g *gen) metas() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, hdr, g.source)
+	b.WriteString("\npackage x86enc\n" + metaDecl)
+	b.WriteString("\nvar metaTable = [...]meta{\n")
+	for _, s := range g.infos {
+		fmt.Fprintf(&b, "\t%s,\n", s)
+	}
+	b.WriteString("}\n")
+	writeInts(&b, "rowMeta", g.rowInfo)
+	return b.Bytes()
+}
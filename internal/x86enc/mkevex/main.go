@@ -0,0 +1,599 @@
+// mkevex derives rows for AVX-512 instructions from Intel XED's datafiles.
+//
+// x86.csv has no rows for EVEX-encoded forms or the VEX-encoded opmask
+// instructions which accompany them. mkevex reads the XED instruction
+// database distributed with golang.org/x/arch and writes those forms to
+// evex.csv in the format of x86.csv, using the operand notation of the Intel
+// manual, for mkenc to read alongside x86.csv. Forms which it cannot express
+// are listed on standard error.
+//
+// Deriving the rows is necessary because no version of x86.csv has them:
+// x86.v0.2.csv, the newer table in golang.org/x/arch, has no EVEX rows
+// either. The XED datafiles are test fixtures of x86avxgen, which may move or
+// change in any release of golang.org/x/arch, so mkevex checks them against
+// their SHA-256 sums in xedSums, as mkenc checks x86.csv, and evex.csv only
+// changes when they are deliberately updated. To update them, run mkevex with
+// -xed naming a directory holding the new files, review the changes to
+// evex.csv, and change xedSums to match.
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/arch/x86/xeddata"
+
+	"github.com/zephyrtronium/ikitai/internal/x86enc/internal/spec"
+)
+
+const hdr = `# AVX-512 instruction forms for x86enc.
+#
+# Code generated by mkevex from the Intel XED datafiles in
+# golang.org/x/arch/x86/x86avxgen/testdata/xedpath; DO NOT EDIT.
+#
+# Each line has the six fields of x86.csv: mnemonic, encoding, valid-32,
+# valid-64, feature, and tags. Operands use the AVX-512 notation of the Intel
+# manual: {k1} and {z} mark an argument which allows an opmask with merging
+# and zeroing, m64bcst allows broadcasting 64-bit elements, and {er} and {sae}
+# allow static rounding and suppressing exceptions.
+`
+
+// xedSums are the SHA-256 sums of the XED datafiles which mkevex reads, as of
+// golang.org/x/arch v0.30.0.
+var xedSums = []struct {
+	name, sum string
+}{
+	{"all-dec-instructions.txt", "d0bff468a6751be367cc50bf0dbcb89c5ec812df51970a72e63cc67dffc03ff9"},
+	{"all-element-types.txt", "00083b2fb4619ab7ed09064744378623065adfbeabaf718becb9f887eb94c611"},
+	{"all-extra-widths.txt", "5e0da4a6c4a0433a6d4c0fce0593e06cc5eb3252c01c6a2597c93aa0c3eeb25c"},
+	{"all-state.txt", "c56822f5d011dc8bde75b9dd780aef15ef6101481126aaaaba55f9a80eae2870"},
+	{"all-widths.txt", "f2ad5252cb04cdbd2e4b8fdfacdb8f4dcaac554b38a83f9697cba6ebe5b9fb6c"},
+}
+
+func main() {
+	xed := flag.String("xed", "", "directory holding XED's all-dec-instructions.txt (default from golang.org/x/arch)")
+	out := flag.String("o", "evex.csv", "output file")
+	flag.Parse()
+	if *xed == "" {
+		dir, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "golang.org/x/arch").Output()
+		if err != nil {
+			panic(fmt.Errorf("locating golang.org/x/arch: %w", err))
+		}
+		*xed = filepath.Join(strings.TrimSpace(string(dir)), "x86", "x86avxgen", "testdata", "xedpath")
+	}
+	if err := checkSums(*xed); err != nil {
+		fmt.Fprintln(os.Stderr, "mkevex:", err)
+		os.Exit(1)
+	}
+	db, err := xeddata.NewDatabase(*xed)
+	if err != nil {
+		panic(err)
+	}
+	f, err := os.Open(filepath.Join(*xed, "all-dec-instructions.txt"))
+	if err != nil {
+		panic(err)
+	}
+	objs, err := xeddata.NewReader(f).ReadAll()
+	f.Close()
+	if err != nil {
+		panic(err)
+	}
+	// XED lists the variants of an instruction as separate objects. Gather
+	// them so that register and memory forms can be paired.
+	var groups [][]*xeddata.Object
+	idx := make(map[string]int)
+	for _, o := range objs {
+		if o.Extension != "AVX512EVEX" && o.Extension != "AVX512VEX" {
+			continue
+		}
+		k := o.Opcode() + " " + o.ISASet
+		i, ok := idx[k]
+		if !ok {
+			i = len(groups)
+			idx[k] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], o)
+	}
+	var b bytes.Buffer
+	b.WriteString(hdr)
+	seen := make(map[string]bool)
+	var bad []string
+	for _, g := range groups {
+		rows, err := convert(db, g)
+		if err != nil {
+			bad = append(bad, fmt.Sprintf("\t%s (%s): %v", g[0].Opcode(), g[0].ISASet, err))
+			continue
+		}
+		for _, r := range rows {
+			if !seen[r] {
+				seen[r] = true
+				b.WriteString(r)
+			}
+		}
+	}
+	if err := ioutil.WriteFile(*out, b.Bytes(), 0644); err != nil {
+		panic(err)
+	}
+	if len(bad) > 0 {
+		fmt.Fprintf(os.Stderr, "mkevex: %d instructions not converted:\n", len(bad))
+		for _, s := range bad {
+			fmt.Fprintln(os.Stderr, s)
+		}
+	}
+}
+
+// checkSums checks that the XED datafiles in dir have the sums in xedSums.
+func checkSums(dir string) error {
+	for _, f := range xedSums {
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.name))
+		if err != nil {
+			return err
+		}
+		h := sha256.Sum256(b)
+		if got := hex.EncodeToString(h[:]); got != f.sum {
+			return fmt.Errorf("%s has SHA-256 %s, want %s", f.name, got, f.sum)
+		}
+	}
+	return nil
+}
+
+// form is one instruction form of an XED object.
+type form struct {
+	enc   string
+	args  []arg
+	rm    int // index of the ModRM.rm argument, or -1
+	mem   bool
+	valid [2]string
+	// round is {er} or {sae} for the rounding variants of register forms.
+	round string
+	// tags is the tags column of the row.
+	tags string
+}
+
+// arg is an argument of a form. reg is the register kind of a register
+// argument and mem the memory kind of a memory argument, both without
+// decorations; deco holds its {k1} and {z} decorations.
+type arg struct {
+	reg, mem, deco string
+	role           spec.Role
+}
+
+// convert derives the rows for the XED objects of one instruction and ISA
+// set. Register and memory forms which differ only in ModRM.rm are merged into
+// one row as in x86.csv.
+func convert(db *xeddata.Database, objs []*xeddata.Object) ([]string, error) {
+	var forms, rounds []*form
+	for _, o := range objs {
+		for _, in := range o.Insts {
+			f, err := parse(db, in)
+			if err != nil {
+				return nil, err
+			}
+			if f.round != "" {
+				rounds = append(rounds, f)
+			} else {
+				forms = append(forms, f)
+			}
+		}
+	}
+	// Rounding forms are the register forms with EVEX.b set.
+	for _, r := range rounds {
+		ok := false
+		for _, f := range forms {
+			if !f.mem && f.enc == r.enc && equal(f.args, r.args) {
+				f.args[f.rm].deco += r.round
+				ok = true
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("no register form for %s rounding", r.round)
+		}
+	}
+	var rows []string
+	used := make([]bool, len(forms))
+	for i, f := range forms {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+		for j := i + 1; j < len(forms); j++ {
+			if g := forms[j]; !used[j] && pairs(f, g) {
+				used[j] = true
+				f = merge(f, g)
+				break
+			}
+		}
+		r, err := row(objs[0], f)
+		if err != nil {
+			if alternate(forms, i) {
+				// Register forms like the MR encoding of VMOVSS are
+				// written like another form, and x86enc always has the
+				// other.
+				continue
+			}
+			return nil, err
+		}
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+// pairs returns whether two forms are the register and memory forms of one
+// instruction.
+func pairs(f, g *form) bool {
+	if f.mem == g.mem || f.enc != g.enc || f.valid != g.valid || f.rm != g.rm || f.rm < 0 || len(f.args) != len(g.args) {
+		return false
+	}
+	for i := range f.args {
+		if i != f.rm && f.args[i] != g.args[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// alternate returns whether forms[i] is a register form with the same
+// arguments as another form, differing only in how they are encoded.
+func alternate(forms []*form, i int) bool {
+	f := forms[i]
+	if f.mem {
+		return false
+	}
+outer:
+	for j, g := range forms {
+		if j == i || g.mem || g.enc == f.enc || g.valid != f.valid || len(g.args) != len(f.args) {
+			continue
+		}
+		for k := range f.args {
+			if f.args[k].reg != g.args[k].reg || f.args[k].deco != g.args[k].deco {
+				continue outer
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func equal(a, b []arg) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// merge combines a register form and a memory form.
+func merge(f, g *form) *form {
+	if f.mem {
+		f, g = g, f
+	}
+	m := *f
+	m.args = append([]arg(nil), f.args...)
+	a := &m.args[m.rm]
+	b := g.args[g.rm]
+	a.mem = b.mem
+	m.tags = g.tags
+	// Zeroing applies to the register form. The memory form only allows
+	// merging, which x86enc enforces.
+	if a.deco == "" {
+		a.deco = b.deco
+	}
+	m.mem = true
+	return &m
+}
+
+// row formats a form as a line of x86.csv.
+func row(o *xeddata.Object, f *form) (string, error) {
+	ks := make([]spec.Kind, len(f.args))
+	names := make([]string, len(f.args))
+	for i, a := range f.args {
+		s := a.reg
+		switch {
+		case a.reg == "":
+			s = a.mem
+		case a.mem != "":
+			s = a.reg + "/" + a.mem
+			if strings.HasPrefix(a.reg, "r") && strings.HasPrefix(a.mem, "m"+a.reg[1:]) && !strings.Contains(a.mem, "/") {
+				// General-purpose registers are written r/m32 when the
+				// memory is as wide as the register.
+				s = "r/" + a.mem
+			}
+		}
+		names[i] = s + a.deco
+		k, err := spec.ParseKind(names[i])
+		if err != nil {
+			return "", err
+		}
+		ks[i] = k
+	}
+	e, err := spec.ParseEncoding(f.enc)
+	if err != nil {
+		return "", err
+	}
+	// Check that mkenc will place each argument where XED does.
+	rs, err := spec.Roles(ks, &e)
+	if err != nil {
+		return "", err
+	}
+	for i, a := range f.args {
+		if rs[i] != a.role {
+			return "", fmt.Errorf("%s would be encoded in the wrong place", names[i])
+		}
+	}
+	if _, err := spec.Disp8(ks, &e); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%q,%q,%q,%q,%q,%q\n", strings.TrimSpace(o.Opcode()+" "+strings.Join(names, ", ")), f.enc, f.valid[0], f.valid[1], feature(o.ISASet), f.tags), nil
+}
+
+// parse interprets the pattern and operands of an XED instruction.
+func parse(db *xeddata.Database, in *xeddata.Inst) (*form, error) {
+	f := &form{rm: -1, valid: [2]string{"V", "V"}}
+	var (
+		prefix          = "VEX"
+		vl, pp, mp, w   string
+		digit           = "/r"
+		nds             = true
+		ib              bool
+		zeroing, masked = true, true
+		vsib            string
+		opcode          string
+		esize           int
+		nelem           string
+	)
+	for _, t := range strings.Fields(in.Pattern) {
+		switch {
+		case t == "EVV":
+			prefix = "EVEX"
+		case t == "VV1":
+		case strings.HasPrefix(t, "0x"):
+			if opcode != "" {
+				return nil, fmt.Errorf("multiple opcode bytes in %q", in.Pattern)
+			}
+			opcode = strings.ToUpper(t[2:])
+		case t == "V66", t == "VF2", t == "VF3":
+			pp = "." + t[1:]
+		case t == "VNP":
+		case t == "V0F", t == "V0F38", t == "V0F3A":
+			mp = "." + t[1:]
+		case t == "VL128", t == "VL256", t == "VL512":
+			vl = "." + t[2:]
+		case t == "VL=0", t == "VL=1":
+			vl = ".L" + t[3:]
+		case t == "W0", t == "W1":
+			w = "." + t
+		case t == "MOD[mm]":
+			f.mem = true
+		case strings.HasPrefix(t, "REG[0b"):
+			n, err := strconv.ParseUint(t[6:len(t)-1], 2, 3)
+			if err != nil {
+				return nil, fmt.Errorf("bad token %q", t)
+			}
+			digit = fmt.Sprintf("/%d", n)
+		case t == "NOEVSR", t == "NOVSR":
+			nds = false
+		case t == "UIMM8()":
+			ib = true
+		case t == "ZEROING=0":
+			zeroing = false
+		case t == "MASK=0":
+			masked = false
+		case t == "mode64":
+			f.valid[0] = "N.E."
+		case t == "not64":
+			f.valid[1] = "N.E."
+		case strings.HasPrefix(t, "UISA_VMODRM_"):
+			vsib = strings.ToLower(strings.TrimSuffix(t[12:], "()")[:1])
+		case strings.HasPrefix(t, "ESIZE_"):
+			n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(t, "ESIZE_"), "_BITS()"))
+			if err != nil {
+				return nil, fmt.Errorf("bad token %q", t)
+			}
+			esize = n / 8
+		case strings.HasPrefix(t, "NELEM_"):
+			nelem = strings.TrimSuffix(t[6:], "()")
+		case t == "AVX512_ROUND()":
+			f.round = "{er}"
+		case t == "SAE()":
+			f.round = "{sae}"
+		case t == "FIX_ROUND_LEN512()":
+			// 512-bit forms round with EVEX.L'L holding the rounding mode.
+			vl = ".512"
+		case t == "MOD[0b11]", t == "MOD=3", t == "MOD!=3", t == "REG[rrr]", t == "RM[nnn]",
+			t == "RM[0b100]", t == "RM=4", t == "MODRM()", t == "BCRC=0", t == "BCRC=1",
+			t == "EVEXRR_ONE", t == "eanot16", t == "FIX_ROUND_LEN128()":
+			// Implied by the operands or irrelevant to encoding.
+		default:
+			return nil, fmt.Errorf("unknown token %q in pattern", t)
+		}
+	}
+	if opcode == "" || mp == "" {
+		return nil, fmt.Errorf("incomplete pattern %q", in.Pattern)
+	}
+	if nelem == "GSCAT" && vsib == "" {
+		// Compressing and expanding loads and stores scale 8-bit
+		// displacements by the element size like gathers and scatters, which
+		// the operands do not say.
+		f.tags = fmt.Sprintf("esize%d", 8*esize)
+	}
+	if vl == "" {
+		vl = ".LIG"
+	}
+	if w == "" {
+		w = ".WIG"
+	}
+	ndsField := ""
+	if nds {
+		ndsField = ".NDS"
+	}
+	f.enc = prefix + ndsField + vl + pp + mp + w + " " + opcode + " " + digit
+	if ib {
+		f.enc += " ib"
+	}
+	bits := map[string]int{".128": 128, ".256": 256, ".512": 512}[vl]
+	nreg := 0
+	for _, s := range strings.Fields(in.Operands) {
+		op, err := xeddata.NewOperand(db, s)
+		if err != nil {
+			return nil, err
+		}
+		if op.Visibility == xeddata.VisSuppressed || op.Visibility == xeddata.VisEcond || strings.HasPrefix(s, "EMX_BROADCAST_") {
+			// EMX_BROADCAST operands describe what the broadcast
+			// instructions do, not how they are encoded.
+			continue
+		}
+		name := op.Name
+		if op.NonterminalName() {
+			name = op.NameRHS()
+		}
+		if op.Attributes["MULTISOURCE4"] {
+			return nil, fmt.Errorf("register blocks are not supported")
+		}
+		switch {
+		case name == "MASK1()" || name == "MASKNOT0()":
+			if len(f.args) == 0 {
+				return nil, fmt.Errorf("opmask without destination")
+			}
+			if masked {
+				d := &f.args[len(f.args)-1]
+				d.deco = "{k1}"
+				if op.Attributes["TXT=ZEROSTR"] && zeroing && name == "MASK1()" {
+					d.deco += "{z}"
+				}
+			}
+		case name == "IMM0":
+			// XED reads every EVEX immediate as UIMM8.
+			f.args = append(f.args, arg{reg: "imm8u", role: spec.RoleImm})
+		case name == "MEM0":
+			// Memory operands take a register's number so that register
+			// and memory forms name their other arguments alike.
+			nreg++
+			a := arg{role: spec.RoleRM}
+			if vsib != "" {
+				idx := "32"
+				if in.HasAttribute("QWORD_INDICES") {
+					idx = "64"
+				}
+				a.mem = "vm" + idx + vsib
+			} else {
+				n, err := memSize(db, op.Width, nelem, bits, esize)
+				if err != nil {
+					return nil, err
+				}
+				a.mem = fmt.Sprintf("m%d", 8*n)
+				if op.Attributes["TXT=BCASTSTR"] {
+					a.mem += fmt.Sprintf("/m%dbcst", 8*esize)
+				}
+			}
+			f.rm = len(f.args)
+			f.args = append(f.args, a)
+		default:
+			i := strings.LastIndexByte(name, '_')
+			if i < 0 || !strings.HasSuffix(name, "()") {
+				return nil, fmt.Errorf("unknown operand %s", s)
+			}
+			var reg string
+			nreg++
+			switch name[:i] {
+			case "XMM", "YMM", "ZMM", "MASK":
+				reg = strings.ToLower(name[:i])
+				if reg == "mask" {
+					reg = "k"
+				}
+				reg += strconv.Itoa(nreg)
+			case "GPR32", "VGPR32":
+				reg = "r32"
+			case "GPR64", "VGPR64":
+				reg = "r64"
+			default:
+				return nil, fmt.Errorf("unknown operand %s", s)
+			}
+			a := arg{reg: reg}
+			switch strings.TrimSuffix(name[i+1:len(name)-2], "3") {
+			case "R":
+				a.role = spec.RoleReg
+			case "N":
+				a.role = spec.RoleVVVV
+			case "B":
+				a.role = spec.RoleRM
+				f.rm = len(f.args)
+			default:
+				return nil, fmt.Errorf("unknown operand %s", s)
+			}
+			f.args = append(f.args, a)
+		}
+	}
+	for _, a := range f.args {
+		if a.reg == "r64" {
+			f.valid[0] = "N.E."
+		}
+	}
+	return f, nil
+}
+
+// memSize returns the width in bytes of a memory operand. Widths which vary
+// with the vector length are given by the number of elements accessed.
+func memSize(db *xeddata.Database, width, nelem string, bits, esize int) (int, error) {
+	if width != "vv" && width != "zv" {
+		s := db.WidthSize(width, xeddata.OpSize64)
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "bits"))
+		if err != nil || n == 0 {
+			return 0, fmt.Errorf("unknown memory width %q", width)
+		}
+		if strings.HasSuffix(s, "bits") {
+			n /= 8
+		}
+		return n, nil
+	}
+	switch nelem {
+	case "FULL", "FULLMEM":
+		return bits / 8, nil
+	case "HALF", "HALFMEM":
+		return bits / 16, nil
+	case "QUARTERMEM":
+		return bits / 32, nil
+	case "EIGHTHMEM":
+		return bits / 64, nil
+	case "MEM128":
+		return 16, nil
+	case "SCALAR", "TUPLE1":
+		return esize, nil
+	case "TUPLE2":
+		return 2 * esize, nil
+	case "TUPLE4":
+		return 4 * esize, nil
+	case "TUPLE8":
+		return 8 * esize, nil
+	}
+	return 0, fmt.Errorf("unknown memory width %q with %q elements", width, nelem)
+}
+
+// feature names the CPUID feature flags of an XED ISA set, like "AVX512F" for
+// AVX512F_512 and "Both AVX512F and AVX512VL flags" for AVX512F_128.
+func feature(isa string) string {
+	i := strings.LastIndexByte(isa, '_')
+	if i < 0 {
+		return isa
+	}
+	switch isa[i+1:] {
+	case "128", "256":
+		return "Both " + isa[:i] + " and AVX512VL flags"
+	case "512", "SCALAR", "KOP", "128N":
+		return isa[:i]
+	}
+	return isa
+}
@@ -0,0 +1,39 @@
+package x86enc
+
+// nops are the multi-byte NOPs recommended by the Intel manual for each
+// length up to 9 bytes, forms of NOP r/m32 with increasing displacements.
+// They are valid in both 32-bit and 64-bit mode.
+var nops = [...][]byte{
+	1: {0x90},
+	2: {0x66, 0x90},
+	3: {0x0f, 0x1f, 0x00},
+	4: {0x0f, 0x1f, 0x40, 0x00},
+	5: {0x0f, 0x1f, 0x44, 0x00, 0x00},
+	6: {0x66, 0x0f, 0x1f, 0x44, 0x00, 0x00},
+	7: {0x0f, 0x1f, 0x80, 0x00, 0x00, 0x00, 0x00},
+	8: {0x0f, 0x1f, 0x84, 0x00, 0x00, 0x00, 0x00, 0x00},
+	9: {0x66, 0x0f, 0x1f, 0x84, 0x00, 0x00, 0x00, 0x00, 0x00},
+}
+
+// AppendNOP appends n bytes of padding which does nothing when executed,
+// using as few instructions as the recommended multi-byte NOPs allow.
+func AppendNOP(b []byte, n int) []byte {
+	for n > 0 {
+		k := n
+		if k >= len(nops) {
+			k = len(nops) - 1
+		}
+		b = append(b, nops[k]...)
+		n -= k
+	}
+	return b
+}
+
+// AppendINT3 appends n bytes of INT3, which traps when executed, for padding
+// which should never be reached.
+func AppendINT3(b []byte, n int) []byte {
+	for ; n > 0; n-- {
+		b = append(b, 0xcc)
+	}
+	return b
+}
@@ -0,0 +1,36 @@
+package x86enc
+
+import (
+	"testing"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// TestAppendNOP checks that padding of each length decodes as that many bytes
+// of NOPs, in as few instructions as possible.
+func TestAppendNOP(t *testing.T) {
+	for n := 0; n <= 40; n++ {
+		for _, mode := range []int{32, 64} {
+			b := AppendNOP([]byte{0xcc}, n)
+			if len(b) != n+1 || b[0] != 0xcc {
+				t.Errorf("%d bytes in mode %d: got %x", n, mode, b)
+				continue
+			}
+			insts := 0
+			for p := b[1:]; len(p) > 0; insts++ {
+				inst, err := x86asm.Decode(p, mode)
+				if err != nil || inst.Op != x86asm.NOP {
+					t.Errorf("%d bytes in mode %d: %x decodes as %v, %v", n, mode, p, inst, err)
+					break
+				}
+				p = p[inst.Len:]
+			}
+			if want := (n + 8) / 9; insts != want {
+				t.Errorf("%d bytes in mode %d: %d instructions, want %d", n, mode, insts, want)
+			}
+		}
+	}
+	if b := AppendINT3(nil, 3); string(b) != "\xcc\xcc\xcc" {
+		t.Errorf("AppendINT3: got %x", b)
+	}
+}
@@ -0,0 +1,663 @@
+package x86enc
+
+import "fmt"
+
+// An Operand is an operand to an instruction: a register, memory reference,
+// immediate, relative branch target, far pointer, masked operand, or
+// rounding control.
+type Operand interface {
+	isOperand()
+	String() string
+}
+
+// A Reg is a register operand.
+type Reg interface {
+	Operand
+	// Class returns the register's class.
+	Class() RegClass
+	// Num returns the register's number as encoded in ModRM, SIB, and REX.
+	Num() int
+	// Size returns the register's width in bytes.
+	Size() int
+}
+
+// RegClass is a class of registers.
+type RegClass uint8
+
+// Register classes.
+const (
+	ClassNone RegClass = iota
+	ClassGPR           // general-purpose registers, of any width
+	ClassXMM           // 128-bit vector registers
+	ClassYMM           // 256-bit vector registers
+	ClassMMX           // MMX registers
+	ClassSeg           // segment registers
+	ClassX87           // x87 stack registers
+	ClassCR            // control registers
+	ClassDR            // debug registers
+	ClassTR            // test registers
+	ClassIP            // instruction pointer, for relative addressing
+	ClassZMM           // 512-bit vector registers
+	ClassK             // AVX-512 opmask registers
+)
+
+var classNames = [...]string{"none", "GPR", "XMM", "YMM", "MMX", "Seg", "X87", "CR", "DR", "TR", "IP", "ZMM", "K"}
+
+func (c RegClass) String() string {
+	if int(c) < len(classNames) {
+		return classNames[c]
+	}
+	return fmt.Sprintf("RegClass(%d)", uint8(c))
+}
+
+// GPR8 is an 8-bit general-purpose register.
+type GPR8 uint8
+
+// GPR16 is a 16-bit general-purpose register.
+type GPR16 uint8
+
+// GPR32 is a 32-bit general-purpose register.
+type GPR32 uint8
+
+// GPR64 is a 64-bit general-purpose register.
+type GPR64 uint8
+
+// XMM is a 128-bit vector register.
+type XMM uint8
+
+// YMM is a 256-bit vector register.
+type YMM uint8
+
+// MMX is a 64-bit MMX register.
+type MMX uint8
+
+// Seg is a segment register.
+type Seg uint8
+
+// ST is an x87 floating-point stack register.
+type ST uint8
+
+// CR is a control register.
+type CR uint8
+
+// DR is a debug register.
+type DR uint8
+
+// TR is a test register, which only exists on old processors in 32-bit mode.
+type TR uint8
+
+// ZMM is a 512-bit vector register.
+type ZMM uint8
+
+// K is an AVX-512 opmask register.
+type K uint8
+
+// IP is the instruction pointer. It is only useful as the base of a memory
+// operand, for RIP-relative addressing.
+type IP uint8
+
+// 8-bit general-purpose registers. AH, CH, DH, and BH cannot be used in
+// instructions which require a REX prefix.
+const (
+	AL GPR8 = iota
+	CL
+	DL
+	BL
+	SPL
+	BPL
+	SIL
+	DIL
+	R8B
+	R9B
+	R10B
+	R11B
+	R12B
+	R13B
+	R14B
+	R15B
+	AH
+	CH
+	DH
+	BH
+)
+
+// 16-bit general-purpose registers.
+const (
+	AX GPR16 = iota
+	CX
+	DX
+	BX
+	SP
+	BP
+	SI
+	DI
+	R8W
+	R9W
+	R10W
+	R11W
+	R12W
+	R13W
+	R14W
+	R15W
+)
+
+// 32-bit general-purpose registers.
+const (
+	EAX GPR32 = iota
+	ECX
+	EDX
+	EBX
+	ESP
+	EBP
+	ESI
+	EDI
+	R8D
+	R9D
+	R10D
+	R11D
+	R12D
+	R13D
+	R14D
+	R15D
+)
+
+// 64-bit general-purpose registers.
+const (
+	RAX GPR64 = iota
+	RCX
+	RDX
+	RBX
+	RSP
+	RBP
+	RSI
+	RDI
+	R8
+	R9
+	R10
+	R11
+	R12
+	R13
+	R14
+	R15
+)
+
+// 128-bit vector registers. X16 through X31 require EVEX-encoded forms.
+const (
+	X0 XMM = iota
+	X1
+	X2
+	X3
+	X4
+	X5
+	X6
+	X7
+	X8
+	X9
+	X10
+	X11
+	X12
+	X13
+	X14
+	X15
+	X16
+	X17
+	X18
+	X19
+	X20
+	X21
+	X22
+	X23
+	X24
+	X25
+	X26
+	X27
+	X28
+	X29
+	X30
+	X31
+)
+
+// 256-bit vector registers. Y16 through Y31 require EVEX-encoded forms.
+const (
+	Y0 YMM = iota
+	Y1
+	Y2
+	Y3
+	Y4
+	Y5
+	Y6
+	Y7
+	Y8
+	Y9
+	Y10
+	Y11
+	Y12
+	Y13
+	Y14
+	Y15
+	Y16
+	Y17
+	Y18
+	Y19
+	Y20
+	Y21
+	Y22
+	Y23
+	Y24
+	Y25
+	Y26
+	Y27
+	Y28
+	Y29
+	Y30
+	Y31
+)
+
+// 512-bit vector registers.
+const (
+	Z0 ZMM = iota
+	Z1
+	Z2
+	Z3
+	Z4
+	Z5
+	Z6
+	Z7
+	Z8
+	Z9
+	Z10
+	Z11
+	Z12
+	Z13
+	Z14
+	Z15
+	Z16
+	Z17
+	Z18
+	Z19
+	Z20
+	Z21
+	Z22
+	Z23
+	Z24
+	Z25
+	Z26
+	Z27
+	Z28
+	Z29
+	Z30
+	Z31
+)
+
+// MMX registers.
+const (
+	M0 MMX = iota
+	M1
+	M2
+	M3
+	M4
+	M5
+	M6
+	M7
+)
+
+// Segment registers.
+const (
+	ES Seg = iota
+	CS
+	SS
+	DS
+	FS
+	GS
+)
+
+// Opmask registers. K0 cannot be used as a mask, since EVEX encodes the
+// absence of a mask as K0.
+const (
+	K0 K = iota
+	K1
+	K2
+	K3
+	K4
+	K5
+	K6
+	K7
+)
+
+// x87 stack registers.
+const (
+	ST0 ST = iota
+	ST1
+	ST2
+	ST3
+	ST4
+	ST5
+	ST6
+	ST7
+)
+
+// Instruction pointers. EIP-relative addressing in 64-bit mode uses an
+// address size prefix.
+const (
+	RIP IP = iota
+	EIP
+)
+
+var gpr8Names = [...]string{"AL", "CL", "DL", "BL", "SPL", "BPL", "SIL", "DIL", "R8B", "R9B", "R10B", "R11B", "R12B", "R13B", "R14B", "R15B", "AH", "CH", "DH", "BH"}
+var gpr16Names = [...]string{"AX", "CX", "DX", "BX", "SP", "BP", "SI", "DI", "R8W", "R9W", "R10W", "R11W", "R12W", "R13W", "R14W", "R15W"}
+var gpr32Names = [...]string{"EAX", "ECX", "EDX", "EBX", "ESP", "EBP", "ESI", "EDI", "R8D", "R9D", "R10D", "R11D", "R12D", "R13D", "R14D", "R15D"}
+var gpr64Names = [...]string{"RAX", "RCX", "RDX", "RBX", "RSP", "RBP", "RSI", "RDI", "R8", "R9", "R10", "R11", "R12", "R13", "R14", "R15"}
+var segNames = [...]string{"ES", "CS", "SS", "DS", "FS", "GS"}
+var ipNames = [...]string{"RIP", "EIP"}
+
+func (GPR8) isOperand()  {}
+func (GPR16) isOperand() {}
+func (GPR32) isOperand() {}
+func (GPR64) isOperand() {}
+func (XMM) isOperand()   {}
+func (YMM) isOperand()   {}
+func (ZMM) isOperand()   {}
+func (K) isOperand()     {}
+func (MMX) isOperand()   {}
+func (Seg) isOperand()   {}
+func (ST) isOperand()    {}
+func (CR) isOperand()    {}
+func (DR) isOperand()    {}
+func (TR) isOperand()    {}
+func (IP) isOperand()    {}
+
+func (GPR8) Class() RegClass  { return ClassGPR }
+func (GPR16) Class() RegClass { return ClassGPR }
+func (GPR32) Class() RegClass { return ClassGPR }
+func (GPR64) Class() RegClass { return ClassGPR }
+func (XMM) Class() RegClass   { return ClassXMM }
+func (YMM) Class() RegClass   { return ClassYMM }
+func (ZMM) Class() RegClass   { return ClassZMM }
+func (K) Class() RegClass     { return ClassK }
+func (MMX) Class() RegClass   { return ClassMMX }
+func (Seg) Class() RegClass   { return ClassSeg }
+func (ST) Class() RegClass    { return ClassX87 }
+func (CR) Class() RegClass    { return ClassCR }
+func (DR) Class() RegClass    { return ClassDR }
+func (TR) Class() RegClass    { return ClassTR }
+func (IP) Class() RegClass    { return ClassIP }
+
+// Num returns the register's encoded number. AH through BH are encoded as 4
+// through 7, the same as SPL through DIL without a REX prefix.
+func (r GPR8) Num() int {
+	if r >= AH {
+		return int(r-AH) + 4
+	}
+	return int(r)
+}
+
+func (r GPR16) Num() int { return int(r) }
+func (r GPR32) Num() int { return int(r) }
+func (r GPR64) Num() int { return int(r) }
+func (r XMM) Num() int   { return int(r) }
+func (r YMM) Num() int   { return int(r) }
+func (r ZMM) Num() int   { return int(r) }
+func (r K) Num() int     { return int(r) }
+func (r MMX) Num() int   { return int(r) }
+func (r Seg) Num() int   { return int(r) }
+func (r ST) Num() int    { return int(r) }
+func (r CR) Num() int    { return int(r) }
+func (r DR) Num() int    { return int(r) }
+func (r TR) Num() int    { return int(r) }
+
+// Num returns 5, the ModRM.rm value for IP-relative addressing.
+func (IP) Num() int { return 5 }
+
+func (GPR8) Size() int  { return 1 }
+func (GPR16) Size() int { return 2 }
+func (GPR32) Size() int { return 4 }
+func (GPR64) Size() int { return 8 }
+func (XMM) Size() int   { return 16 }
+func (YMM) Size() int   { return 32 }
+func (ZMM) Size() int   { return 64 }
+func (K) Size() int     { return 8 }
+func (MMX) Size() int   { return 8 }
+func (Seg) Size() int   { return 2 }
+func (ST) Size() int    { return 10 }
+func (CR) Size() int    { return 8 }
+func (DR) Size() int    { return 8 }
+func (TR) Size() int    { return 4 }
+
+// Size returns 8 for RIP and 4 for EIP.
+func (r IP) Size() int {
+	if r == EIP {
+		return 4
+	}
+	return 8
+}
+
+func (r GPR8) String() string  { return regName(gpr8Names[:], int(r), "GPR8") }
+func (r GPR16) String() string { return regName(gpr16Names[:], int(r), "GPR16") }
+func (r GPR32) String() string { return regName(gpr32Names[:], int(r), "GPR32") }
+func (r GPR64) String() string { return regName(gpr64Names[:], int(r), "GPR64") }
+func (r XMM) String() string   { return fmt.Sprintf("X%d", int(r)) }
+func (r YMM) String() string   { return fmt.Sprintf("Y%d", int(r)) }
+func (r ZMM) String() string   { return fmt.Sprintf("Z%d", int(r)) }
+func (r K) String() string     { return fmt.Sprintf("K%d", int(r)) }
+func (r MMX) String() string   { return fmt.Sprintf("M%d", int(r)) }
+func (r Seg) String() string   { return regName(segNames[:], int(r), "Seg") }
+func (r ST) String() string    { return fmt.Sprintf("ST(%d)", int(r)) }
+func (r CR) String() string    { return fmt.Sprintf("CR%d", int(r)) }
+func (r DR) String() string    { return fmt.Sprintf("DR%d", int(r)) }
+func (r TR) String() string    { return fmt.Sprintf("TR%d", int(r)) }
+func (r IP) String() string    { return regName(ipNames[:], int(r), "IP") }
+
+func regName(names []string, r int, typ string) string {
+	if r < len(names) {
+		return names[r]
+	}
+	return fmt.Sprintf("%s(%d)", typ, r)
+}
+
+// Mem is a memory operand referring to the address
+// Base + Index*Scale + Disp.
+//
+// Base may be nil, a 32- or 64-bit general-purpose register, or RIP or EIP
+// for addressing relative to the end of the instruction. Index may be nil, a
+// general-purpose register of the same width as Base, or, for the vector SIB
+// addressing used by gathers and scatters, an XMM, YMM, or ZMM register. If
+// neither is set, the operand refers to the absolute address Disp.
+//
+// Seg may be nil or a segment register to override the segment of the
+// address. In 64-bit mode, only FS and GS have bases, which thread-local
+// storage uses.
+type Mem struct {
+	Seg   Reg
+	Base  Reg
+	Index Reg
+	// Scale is the multiplier for Index, which must be 1, 2, 4, or 8 if Index
+	// is not nil.
+	Scale uint8
+	Disp  int32
+	// Size is the width in bytes of the data referenced, which selects
+	// between forms that differ only in the width of a memory operand. If it
+	// is 0, the operand matches memory of any width, but the width of the
+	// instruction must follow from its other operands.
+	Size int
+	// Broadcast requests EVEX embedded broadcast, which loads one element
+	// from memory and repeats it across the vector. Size, if set, is then
+	// the width of the element.
+	Broadcast bool
+}
+
+func (Mem) isOperand() {}
+
+func (m Mem) String() string {
+	s := ""
+	if m.Seg != nil {
+		s = m.Seg.String() + ":"
+	}
+	if m.Disp != 0 || (m.Base == nil && m.Index == nil) {
+		if m.Disp < 0 {
+			s += fmt.Sprintf("-%#x", -int64(m.Disp))
+		} else {
+			s += fmt.Sprintf("%#x", m.Disp)
+		}
+	}
+	if m.Base == nil && m.Index == nil {
+		return "[" + s + "]"
+	}
+	s += "("
+	if m.Base != nil {
+		s += m.Base.String()
+	}
+	if m.Index != nil {
+		s += fmt.Sprintf("+%v*%d", m.Index, m.Scale)
+	}
+	s += ")"
+	if m.Broadcast {
+		s += "{1toN}"
+	}
+	return s
+}
+
+// Imm is an immediate operand. Whether an immediate's value fits an
+// instruction form depends on the width of the form's immediate and whether
+// the processor sign-extends it: an immediate narrower than the operation is
+// sign-extended, so it must fit as a signed value, while one as wide as the
+// operation may be given as either a signed or an unsigned value.
+type Imm int64
+
+func (Imm) isOperand() {}
+
+// FitsSigned returns whether the immediate is representable as a signed
+// integer of the given number of bits.
+func (i Imm) FitsSigned(bits int) bool {
+	if bits >= 64 {
+		return true
+	}
+	return -int64(1)<<uint(bits-1) <= int64(i) && int64(i) < int64(1)<<uint(bits-1)
+}
+
+// FitsUnsigned returns whether the immediate is representable as an unsigned
+// integer of the given number of bits.
+func (i Imm) FitsUnsigned(bits int) bool {
+	if bits >= 64 {
+		return i >= 0
+	}
+	return 0 <= int64(i) && int64(i) < int64(1)<<uint(bits)
+}
+
+func (i Imm) String() string { return fmt.Sprintf("$%d", int64(i)) }
+
+// Rel is a branch target relative to the end of the instruction.
+type Rel int32
+
+func (Rel) isOperand() {}
+
+func (r Rel) String() string { return fmt.Sprintf(".%+d", int32(r)) }
+
+// FarPtr is an absolute far pointer operand, a segment selector and offset,
+// as used by far JMP and CALL in 32-bit mode.
+type FarPtr struct {
+	Seg uint16
+	Off uint32
+}
+
+func (FarPtr) isOperand() {}
+
+func (p FarPtr) String() string { return fmt.Sprintf("$%#x:%#x", p.Seg, p.Off) }
+
+// Masked is a destination operand of an EVEX-encoded form with an opmask.
+// Elements of the destination whose bits in Mask are clear are left unchanged,
+// or set to zero if Zero is set.
+type Masked struct {
+	Op   Operand
+	Mask K
+	Zero bool
+}
+
+func (Masked) isOperand() {}
+
+func (m Masked) String() string {
+	s := fmt.Sprintf("%v{%v}", m.Op, m.Mask)
+	if m.Zero {
+		s += "{z}"
+	}
+	return s
+}
+
+// Rounding is a static rounding mode for an EVEX-encoded form, which also
+// suppresses floating-point exceptions. It is given after all other operands
+// and applies only to forms whose operands are all registers.
+type Rounding uint8
+
+// Rounding modes. SAE suppresses exceptions without changing the rounding
+// mode, for forms which do not round.
+const (
+	RoundNearest Rounding = 1 + iota
+	RoundDown
+	RoundUp
+	RoundZero
+	SAE
+)
+
+var roundingNames = [...]string{"", "{rn-sae}", "{rd-sae}", "{ru-sae}", "{rz-sae}", "{sae}"}
+
+func (Rounding) isOperand() {}
+
+func (r Rounding) String() string {
+	if int(r) < len(roundingNames) && r != 0 {
+		return roundingNames[r]
+	}
+	return fmt.Sprintf("Rounding(%d)", uint8(r))
+}
+
+// Prefix is a legacy prefix which changes the operation of an instruction. It
+// is given before all other operands, as in Encode("ADD", LOCK, m, EAX), and
+// may only be used with instructions it applies to.
+type Prefix uint8
+
+// Prefixes. LOCK makes a read-modify-write of a memory destination atomic.
+// REP repeats a string instruction RCX times, or while equal for CMPS and
+// SCAS, and REPN repeats CMPS or SCAS while not equal. ADDRSIZE makes a
+// string instruction use 32-bit addresses in 64-bit mode, or 16-bit addresses
+// in 32-bit mode; other instructions take it from the registers of their
+// memory operands. SegPrefix gives the prefixes overriding segments.
+const (
+	LOCK     Prefix = 0xf0
+	REP      Prefix = 0xf3
+	REPN     Prefix = 0xf2
+	ADDRSIZE Prefix = 0x67
+)
+
+// SegPrefix returns the prefix overriding the DS segment of the source of
+// MOVS, CMPS, LODS, OUTS, or XLATB. Other instructions take their segments
+// from their memory operands.
+func SegPrefix(s Seg) Prefix {
+	return Prefix(segPrefixes[s])
+}
+
+func (Prefix) isOperand() {}
+
+func (p Prefix) String() string {
+	switch p {
+	case LOCK:
+		return "LOCK"
+	case REP:
+		return "REP"
+	case REPN:
+		return "REPN"
+	case ADDRSIZE:
+		return "ADDRSIZE"
+	}
+	if s, ok := p.seg(); ok {
+		return s.String() + ":"
+	}
+	return fmt.Sprintf("Prefix(%#x)", uint8(p))
+}
+
+// seg returns the segment register a segment override prefix selects.
+func (p Prefix) seg() (Seg, bool) {
+	for s, b := range segPrefixes {
+		if byte(p) == b {
+			return Seg(s), true
+		}
+	}
+	return 0, false
+}